@@ -1,29 +1,74 @@
+// Command compliance-service runs the compliance API, its background jobs and its migrations.
+//
+// Usage:
+//
+//	compliance-service [-config file] [serve|worker|migrate]
+//
+// serve runs the HTTP API, and the background jobs unless SERVE_RUN_JOBS=false. worker runs only
+// the background jobs. migrate applies the pending schema migrations and exits. The config file
+// holds KEY=VALUE lines; the environment overrides it.
 package main
 
 import (
-	"log"
+	"flag"
+	"fmt"
+	"log/slog"
 	"os"
 
-	"github.com/gofiber/fiber/v2"
-	"github.com/kodra-pay/compliance-service/internal/middleware"
-	"github.com/kodra-pay/compliance-service/internal/routes"
+	"github.com/kodra-pay/compliance-service/internal/config"
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/kodra-pay/compliance-service/internal/pii"
 )
 
+var commands = map[string]func(cfg *config.Config) error{
+	"serve":   runServe,
+	"worker":  runWorker,
+	"migrate": runMigrate,
+}
+
 func main() {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "7015"
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "config file of KEY=VALUE lines, overridden by the environment")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-config file] [serve|worker|migrate]\n", os.Args[0])
+		flag.PrintDefaults()
 	}
+	flag.Parse()
 
-	serviceName := "compliance-service"
+	name := flag.Arg(0)
+	if name == "" {
+		name = "serve"
+	}
+	command, ok := commands[name]
+	if !ok || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
 
-	app := fiber.New()
-	app.Use(middleware.RequestID())
+	// Scrub BVNs, phone numbers and emails from everything the service logs. Until the
+	// configuration is loaded, log JSON at info level.
+	logOutput := pii.NewScrubWriter(os.Stderr)
+	slog.SetDefault(logging.New(logOutput, slog.LevelInfo, logging.FormatJSON))
 
-	routes.Register(app, serviceName)
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		fatal("failed to load configuration", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		fatal("configuration is not valid", err)
+	}
+
+	// Validate has checked the level
+	level, _ := logging.ParseLevel(cfg.Log.Level)
+	slog.SetDefault(logging.New(logOutput, level, cfg.Log.Format))
+	slog.Info("starting", "service", cfg.ServiceName, "command", name, "config", cfg)
 
-	log.Printf("%s listening on :%s", serviceName, port)
-	if err := app.Listen(":" + port); err != nil {
-		log.Fatal(err)
+	if err := command(cfg); err != nil {
+		fatal(name+" failed", err)
 	}
 }
+
+// fatal logs err and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
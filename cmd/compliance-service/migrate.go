@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/kodra-pay/compliance-service/internal/app"
+	"github.com/kodra-pay/compliance-service/internal/config"
+	"github.com/kodra-pay/compliance-service/internal/migrate"
+	"github.com/kodra-pay/compliance-service/migrations"
+)
+
+// runMigrate applies the pending schema migrations
+func runMigrate(cfg *config.Config) error {
+	if cfg.Storage != config.StoragePostgres {
+		return fmt.Errorf("STORAGE=%s has no schema to migrate", cfg.Storage)
+	}
+
+	db, err := app.OpenDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	applied, err := migrate.Up(context.Background(), db, migrations.Files)
+	for _, name := range applied {
+		slog.Info("applied migration", "migration", name)
+	}
+	if err != nil {
+		return err
+	}
+	slog.Info("schema up to date", "applied", len(applied))
+	return nil
+}
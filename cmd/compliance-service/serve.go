@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os/signal"
+	"syscall"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+
+	"github.com/kodra-pay/compliance-service/internal/app"
+	"github.com/kodra-pay/compliance-service/internal/config"
+	"github.com/kodra-pay/compliance-service/internal/handlers"
+	"github.com/kodra-pay/compliance-service/internal/jobs"
+	"github.com/kodra-pay/compliance-service/internal/middleware"
+	"github.com/kodra-pay/compliance-service/internal/routes"
+)
+
+// runServe runs the HTTP API until SIGINT or SIGTERM, then drains in-flight requests and jobs
+func runServe(cfg *config.Config) error {
+	defer startTracing(cfg)()
+
+	a, err := app.New(cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	server := fiber.New(fiber.Config{
+		ErrorHandler: handlers.ErrorHandler,
+		BodyLimit:    cfg.HTTP.MaxBodyBytes,
+		// The client IP, used by the per-IP rate limits, is only taken from trusted proxies
+		ProxyHeader:             cfg.HTTP.ProxyHeader,
+		EnableTrustedProxyCheck: cfg.HTTP.ProxyHeader != "",
+		TrustedProxies:          cfg.HTTP.TrustedProxies,
+		EnableIPValidation:      true,
+	})
+	server.Use(middleware.Tracing())
+	server.Use(middleware.RequestID())
+	server.Use(middleware.Logger())
+	server.Use(middleware.Metrics())
+	server.Use(recover.New())
+
+	health := routes.Register(server, a)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	var scheduler *jobs.Scheduler
+	if cfg.RunJobs {
+		scheduler = a.Scheduler()
+		scheduler.Start(jobsCtx)
+	}
+
+	listenErr := make(chan error, 1)
+	go func() {
+		slog.Info("listening", "service", cfg.ServiceName, "port", cfg.ServicePort)
+		listenErr <- server.Listen(":" + cfg.ServicePort)
+	}()
+
+	select {
+	case err := <-listenErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down", "service", cfg.ServiceName, "drain_timeout", cfg.ShutdownTimeout)
+	health.Drain()
+	if err := server.ShutdownWithTimeout(cfg.ShutdownTimeout); err != nil {
+		slog.Warn("HTTP server did not drain", "error", err)
+	}
+	if scheduler != nil {
+		stopJobs()
+		drain(scheduler, cfg)
+	}
+	slog.Info("stopped", "service", cfg.ServiceName)
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/kodra-pay/compliance-service/internal/config"
+	"github.com/kodra-pay/compliance-service/internal/pii"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// startTracing installs an OpenTelemetry tracer provider with the configured exporter and the W3C
+// trace context propagator. The returned function flushes the queued spans, at most the shutdown
+// timeout.
+func startTracing(cfg *config.Config) func() {
+	// Callers' traceparent headers are continued even when this service exports nothing
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Tracing.Exporter {
+	case config.TracingStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(pii.NewScrubWriter(os.Stdout)))
+	case config.TracingOTLP:
+		exporter, err = newOTLPExporter(cfg.Tracing.OTLPEndpoint)
+	default:
+		return func() {}
+	}
+	if err != nil {
+		slog.Warn("failed to start the trace exporter, spans are not exported", "error", err)
+		return func() {}
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.Tracing.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			slog.Warn("failed to flush traces", "error", err)
+		}
+	}
+}
+
+// newOTLPExporter sends spans over OTLP/HTTP to the collector at endpoint, a base URL that
+// /v1/traces is appended to
+func newOTLPExporter(endpoint string) (sdktrace.SpanExporter, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(parsed.Host),
+		otlptracehttp.WithURLPath(strings.TrimRight(parsed.Path, "/") + "/v1/traces"),
+	}
+	if parsed.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	return otlptracehttp.New(context.Background(), opts...)
+}
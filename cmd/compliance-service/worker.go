@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/app"
+	"github.com/kodra-pay/compliance-service/internal/config"
+	"github.com/kodra-pay/compliance-service/internal/jobs"
+)
+
+// runWorker runs the background jobs until SIGINT or SIGTERM, then lets running jobs finish
+func runWorker(cfg *config.Config) error {
+	defer startTracing(cfg)()
+
+	a, err := app.New(cfg)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	scheduler := a.Scheduler()
+	scheduler.Start(ctx)
+	slog.Info("worker started", "service", cfg.ServiceName)
+	<-ctx.Done()
+
+	slog.Info("worker stopping", "service", cfg.ServiceName, "drain_timeout", cfg.ShutdownTimeout)
+	drain(scheduler, cfg)
+	return nil
+}
+
+// drain waits for the scheduler's jobs to stop, at most the shutdown timeout
+func drain(scheduler *jobs.Scheduler, cfg *config.Config) {
+	done := make(chan struct{})
+	go func() {
+		scheduler.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(cfg.ShutdownTimeout):
+		slog.Warn("background jobs did not stop in time", "drain_timeout", cfg.ShutdownTimeout)
+	}
+}
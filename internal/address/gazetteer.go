@@ -0,0 +1,123 @@
+package address
+
+import (
+	"context"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// place is a city or district known to the offline gazetteer
+type place struct {
+	Name      string
+	State     string
+	Latitude  float64
+	Longitude float64
+	aliases   []string
+}
+
+// places lists the state capitals and the major commercial cities and districts. Coordinates are
+// approximate centroids.
+var places = []place{
+	{Name: "Lagos", State: "Lagos", Latitude: 6.5244, Longitude: 3.3792},
+	{Name: "Lagos Island", State: "Lagos", Latitude: 6.4541, Longitude: 3.3947},
+	{Name: "Ikeja", State: "Lagos", Latitude: 6.6018, Longitude: 3.3515},
+	{Name: "Victoria Island", State: "Lagos", Latitude: 6.4281, Longitude: 3.4219, aliases: []string{"vi"}},
+	{Name: "Ikoyi", State: "Lagos", Latitude: 6.4549, Longitude: 3.4366},
+	{Name: "Lekki", State: "Lagos", Latitude: 6.4698, Longitude: 3.5852},
+	{Name: "Yaba", State: "Lagos", Latitude: 6.5095, Longitude: 3.3711},
+	{Name: "Surulere", State: "Lagos", Latitude: 6.5059, Longitude: 3.3509},
+	{Name: "Apapa", State: "Lagos", Latitude: 6.4474, Longitude: 3.3594},
+	{Name: "Ikorodu", State: "Lagos", Latitude: 6.6194, Longitude: 3.5105},
+	{Name: "Abuja", State: FCT, Latitude: 9.0579, Longitude: 7.4951},
+	{Name: "Garki", State: FCT, Latitude: 9.0347, Longitude: 7.4899},
+	{Name: "Wuse", State: FCT, Latitude: 9.0700, Longitude: 7.4760},
+	{Name: "Maitama", State: FCT, Latitude: 9.0882, Longitude: 7.4934},
+	{Name: "Asokoro", State: FCT, Latitude: 9.0437, Longitude: 7.5250},
+	{Name: "Gwagwalada", State: FCT, Latitude: 8.9434, Longitude: 7.0837},
+	{Name: "Port Harcourt", State: "Rivers", Latitude: 4.8156, Longitude: 7.0498, aliases: []string{"ph", "phc"}},
+	{Name: "Benin City", State: "Edo", Latitude: 6.3350, Longitude: 5.6037, aliases: []string{"benin"}},
+	{Name: "Ibadan", State: "Oyo", Latitude: 7.3775, Longitude: 3.9470},
+	{Name: "Ogbomosho", State: "Oyo", Latitude: 8.1335, Longitude: 4.2407, aliases: []string{"ogbomoso"}},
+	{Name: "Abeokuta", State: "Ogun", Latitude: 7.1475, Longitude: 3.3619},
+	{Name: "Ota", State: "Ogun", Latitude: 6.6804, Longitude: 3.2356, aliases: []string{"sango ota"}},
+	{Name: "Sagamu", State: "Ogun", Latitude: 6.8322, Longitude: 3.6319, aliases: []string{"shagamu"}},
+	{Name: "Kano", State: "Kano", Latitude: 12.0022, Longitude: 8.5920},
+	{Name: "Kaduna", State: "Kaduna", Latitude: 10.5105, Longitude: 7.4165},
+	{Name: "Zaria", State: "Kaduna", Latitude: 11.0855, Longitude: 7.7199},
+	{Name: "Onitsha", State: "Anambra", Latitude: 6.1413, Longitude: 6.8021},
+	{Name: "Nnewi", State: "Anambra", Latitude: 6.0199, Longitude: 6.9172},
+	{Name: "Awka", State: "Anambra", Latitude: 6.2104, Longitude: 7.0741},
+	{Name: "Aba", State: "Abia", Latitude: 5.1066, Longitude: 7.3667},
+	{Name: "Umuahia", State: "Abia", Latitude: 5.5320, Longitude: 7.4860},
+	{Name: "Warri", State: "Delta", Latitude: 5.5544, Longitude: 5.7932},
+	{Name: "Asaba", State: "Delta", Latitude: 6.1980, Longitude: 6.7319},
+	{Name: "Enugu", State: "Enugu", Latitude: 6.4584, Longitude: 7.5464},
+	{Name: "Owerri", State: "Imo", Latitude: 5.4836, Longitude: 7.0333},
+	{Name: "Calabar", State: "Cross River", Latitude: 4.9757, Longitude: 8.3417},
+	{Name: "Uyo", State: "Akwa Ibom", Latitude: 5.0377, Longitude: 7.9128},
+	{Name: "Jos", State: "Plateau", Latitude: 9.8965, Longitude: 8.8583},
+	{Name: "Ilorin", State: "Kwara", Latitude: 8.4966, Longitude: 4.5426},
+	{Name: "Osogbo", State: "Osun", Latitude: 7.7827, Longitude: 4.5418, aliases: []string{"oshogbo"}},
+	{Name: "Ile-Ife", State: "Osun", Latitude: 7.4905, Longitude: 4.5521, aliases: []string{"ife"}},
+	{Name: "Akure", State: "Ondo", Latitude: 7.2571, Longitude: 5.2058},
+	{Name: "Ado-Ekiti", State: "Ekiti", Latitude: 7.6211, Longitude: 5.2214},
+	{Name: "Lokoja", State: "Kogi", Latitude: 7.8023, Longitude: 6.7333},
+	{Name: "Makurdi", State: "Benue", Latitude: 7.7322, Longitude: 8.5391},
+	{Name: "Lafia", State: "Nasarawa", Latitude: 8.4939, Longitude: 8.5153},
+	{Name: "Minna", State: "Niger", Latitude: 9.5836, Longitude: 6.5463},
+	{Name: "Abakaliki", State: "Ebonyi", Latitude: 6.3249, Longitude: 8.1137},
+	{Name: "Yenagoa", State: "Bayelsa", Latitude: 4.9267, Longitude: 6.2676},
+	{Name: "Maiduguri", State: "Borno", Latitude: 11.8311, Longitude: 13.1510},
+	{Name: "Yola", State: "Adamawa", Latitude: 9.2035, Longitude: 12.4954},
+	{Name: "Bauchi", State: "Bauchi", Latitude: 10.3158, Longitude: 9.8442},
+	{Name: "Gombe", State: "Gombe", Latitude: 10.2897, Longitude: 11.1673},
+	{Name: "Jalingo", State: "Taraba", Latitude: 8.8937, Longitude: 11.3596},
+	{Name: "Damaturu", State: "Yobe", Latitude: 11.7470, Longitude: 11.9608},
+	{Name: "Dutse", State: "Jigawa", Latitude: 11.7562, Longitude: 9.3388},
+	{Name: "Katsina", State: "Katsina", Latitude: 12.9908, Longitude: 7.6018},
+	{Name: "Birnin Kebbi", State: "Kebbi", Latitude: 12.4539, Longitude: 4.1975},
+	{Name: "Sokoto", State: "Sokoto", Latitude: 13.0059, Longitude: 5.2476},
+	{Name: "Gusau", State: "Zamfara", Latitude: 12.1704, Longitude: 6.6641},
+}
+
+// placeIndex maps the lookup form of place names and aliases to their place
+var placeIndex = func() map[string]*place {
+	index := make(map[string]*place)
+	for i := range places {
+		p := &places[i]
+		index[lookupKey(p.Name)] = p
+		for _, alias := range p.aliases {
+			index[lookupKey(alias)] = p
+		}
+	}
+	return index
+}()
+
+// lookupPlace resolves a free-text city to a gazetteer place
+func lookupPlace(city string) (*place, bool) {
+	key := lookupKey(city)
+	if key == "" {
+		return nil, false
+	}
+	p, ok := placeIndex[key]
+	return p, ok
+}
+
+// Gazetteer geocodes addresses offline to the centroid of their city, or of their state capital
+// when the city is unknown. It never calls out and never fails.
+type Gazetteer struct{}
+
+func NewGazetteer() *Gazetteer {
+	return &Gazetteer{}
+}
+
+// Geocode returns the city or state centroid of the address, or nil if neither is known
+func (g *Gazetteer) Geocode(ctx context.Context, addr *models.NormalizedAddress) (*Location, error) {
+	if p, ok := lookupPlace(addr.City); ok && (addr.State == "" || addr.State == p.State) {
+		return &Location{Latitude: p.Latitude, Longitude: p.Longitude, Precision: models.GeocodePrecisionCity}, nil
+	}
+	if state, ok := LookupState(addr.State); ok {
+		return &Location{Latitude: state.Latitude, Longitude: state.Longitude, Precision: models.GeocodePrecisionState}, nil
+	}
+	return nil, nil
+}
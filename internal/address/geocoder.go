@@ -0,0 +1,107 @@
+package address
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// Location is a geocoded point and how precisely it locates the address
+type Location struct {
+	Latitude  float64
+	Longitude float64
+	Precision string // "street", "city", "state"
+}
+
+// Geocoder resolves a normalised address to coordinates. Implementations return nil without an
+// error when the address cannot be located.
+type Geocoder interface {
+	Geocode(ctx context.Context, addr *models.NormalizedAddress) (*Location, error)
+}
+
+// NominatimGeocoder geocodes through a Nominatim-compatible search API, restricted to Nigeria
+type NominatimGeocoder struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+}
+
+func NewNominatimGeocoder(baseURL, userAgent string, timeout time.Duration) *NominatimGeocoder {
+	return &NominatimGeocoder{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		userAgent: userAgent,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+type nominatimResult struct {
+	Lat string `json:"lat"`
+	Lon string `json:"lon"`
+}
+
+// Geocode looks up the full address and returns the best match
+func (g *NominatimGeocoder) Geocode(ctx context.Context, addr *models.NormalizedAddress) (*Location, error) {
+	var parts []string
+	for _, part := range []string{addr.Line, addr.City, addr.State} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	params := url.Values{}
+	params.Set("q", strings.Join(append(parts, "Nigeria"), ", "))
+	params.Set("format", "jsonv2")
+	params.Set("countrycodes", "ng")
+	params.Set("limit", "1")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("geocoder request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoder returned status %d", resp.StatusCode)
+	}
+
+	var results []nominatimResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode geocoder response: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latitude in geocoder response: %w", err)
+	}
+	lng, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude in geocoder response: %w", err)
+	}
+
+	precision := models.GeocodePrecisionStreet
+	if addr.Line == "" {
+		precision = models.GeocodePrecisionCity
+	}
+
+	return &Location{Latitude: lat, Longitude: lng, Precision: precision}, nil
+}
@@ -0,0 +1,255 @@
+package address
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// Address is a free-text address as submitted by a merchant
+type Address struct {
+	Line       string
+	City       string
+	State      string
+	PostalCode string
+}
+
+// Normalizer validates and standardises business addresses and optionally geocodes them
+type Normalizer struct {
+	provider             Geocoder   // optional external geocoder
+	gazetteer            *Gazetteer // nil when geocoding is disabled
+	virtualOfficeMarkers []string
+}
+
+// NewNormalizer creates a normalizer. The provider may be nil, in which case addresses are geocoded
+// against the offline gazetteer only; with geocode false no geocoding happens at all.
+func NewNormalizer(provider Geocoder, geocode bool, virtualOfficeMarkers []string) *Normalizer {
+	n := &Normalizer{virtualOfficeMarkers: make([]string, 0, len(virtualOfficeMarkers))}
+	for _, marker := range virtualOfficeMarkers {
+		if marker = strings.ToLower(strings.TrimSpace(marker)); marker != "" {
+			n.virtualOfficeMarkers = append(n.virtualOfficeMarkers, marker)
+		}
+	}
+	if geocode {
+		n.provider = provider
+		n.gazetteer = NewGazetteer()
+	}
+	return n
+}
+
+var (
+	poBoxPattern   = regexp.MustCompile(`(?i)\b(p\s*\.?\s*o\s*\.?\s*box|post\s+office\s+box|p\s*\.?\s*m\s*\.?\s*b\b|private\s+mail\s+bag|locked\s+bag)`)
+	poPattern      = regexp.MustCompile(`(?i)\b(p\s*\.?\s*o\s*\.?\s*box|post\s+office\s+box)\b`)
+	pmbPattern     = regexp.MustCompile(`(?i)\b(p\s*\.?\s*m\s*\.?\s*b\b\.?|private\s+mail\s+bag)`)
+	commaPattern   = regexp.MustCompile(`\s*,[\s,]*`)
+	ordinalPattern = regexp.MustCompile(`(?i)^\d+(st|nd|rd|th)$`)
+)
+
+// Normalize validates the state against the Nigerian states and the FCT, standardises the street
+// line and city, and flags PO boxes, virtual offices and cities outside the declared state. A blank
+// state is inferred from the city when the gazetteer knows it.
+func (n *Normalizer) Normalize(addr Address) (*models.NormalizedAddress, error) {
+	normalized := &models.NormalizedAddress{
+		Line:       StandardiseLine(addr.Line),
+		City:       standardiseCity(addr.City),
+		PostalCode: strings.Join(strings.Fields(addr.PostalCode), ""),
+	}
+
+	if state := strings.TrimSpace(addr.State); state != "" {
+		canonical, ok := CanonicalState(state)
+		if !ok {
+			return nil, fmt.Errorf("state %q is not a Nigerian state or the FCT", state)
+		}
+		normalized.State = canonical
+	}
+
+	if p, ok := lookupPlace(addr.City); ok {
+		if normalized.State == "" {
+			normalized.State = p.State
+		} else if normalized.State != p.State {
+			normalized.Flags = append(normalized.Flags, models.AddressFlagCityStateMismatch)
+		}
+	}
+
+	if poBoxPattern.MatchString(addr.Line) {
+		normalized.Flags = append(normalized.Flags, models.AddressFlagPOBox)
+	}
+	if n.isVirtualOffice(addr.Line) {
+		normalized.Flags = append(normalized.Flags, models.AddressFlagVirtualOffice)
+	}
+
+	normalized.Key = Key(normalized.Line, normalized.City)
+	return normalized, nil
+}
+
+// Geocode locates a normalised address through the provider, falling back to the gazetteer when no
+// provider is configured, it fails or it finds nothing. A provider error is returned after the
+// fallback so callers can log it without losing the approximate location.
+func (n *Normalizer) Geocode(ctx context.Context, addr *models.NormalizedAddress) error {
+	if n.gazetteer == nil {
+		return nil
+	}
+
+	var providerErr error
+	if n.provider != nil {
+		loc, err := n.provider.Geocode(ctx, addr)
+		if err == nil && loc != nil {
+			setLocation(addr, loc, "provider")
+			return nil
+		}
+		providerErr = err
+	}
+
+	loc, err := n.gazetteer.Geocode(ctx, addr)
+	if err == nil && loc != nil {
+		setLocation(addr, loc, "gazetteer")
+	}
+	return providerErr
+}
+
+func (n *Normalizer) isVirtualOffice(line string) bool {
+	line = strings.ToLower(line)
+	for _, marker := range n.virtualOfficeMarkers {
+		if strings.Contains(line, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func setLocation(addr *models.NormalizedAddress, loc *Location, source string) {
+	lat, lng := loc.Latitude, loc.Longitude
+	addr.Latitude = &lat
+	addr.Longitude = &lng
+	addr.GeocodePrecision = loc.Precision
+	addr.GeocodeSource = source
+}
+
+// Key reduces a street line and city to a key for exact-match comparison of addresses. Lines are
+// standardised first so that "12 Allen Ave" and "12, Allen Avenue" share a key. It returns "" for
+// a blank line.
+func Key(line, city string) string {
+	line = StandardiseLine(line)
+	if line == "" {
+		return ""
+	}
+	return lookupKey(line + standardiseCity(city))
+}
+
+// streetAbbreviations maps common abbreviations in Nigerian addresses to their expansion
+var streetAbbreviations = map[string]string{
+	"rd":    "Road",
+	"st":    "Street",
+	"str":   "Street",
+	"ave":   "Avenue",
+	"av":    "Avenue",
+	"cres":  "Crescent",
+	"crs":   "Crescent",
+	"cl":    "Close",
+	"dr":    "Drive",
+	"ln":    "Lane",
+	"blvd":  "Boulevard",
+	"hwy":   "Highway",
+	"expy":  "Expressway",
+	"expwy": "Expressway",
+	"est":   "Estate",
+	"ind":   "Industrial",
+	"opp":   "Opposite",
+	"bldg":  "Building",
+	"flr":   "Floor",
+	"fl":    "Floor",
+	"ste":   "Suite",
+	"sq":    "Square",
+	"jct":   "Junction",
+	"junc":  "Junction",
+	"rdbt":  "Roundabout",
+	"plt":   "Plot",
+}
+
+// ambiguousAbbreviations also abbreviate titles ("St. John", "Dr. Nwosu"), so they are only
+// expanded after a street name
+var ambiguousAbbreviations = map[string]bool{"st": true, "dr": true}
+
+// acronyms stay upper case
+var acronyms = map[string]bool{"gra": true, "cbd": true, "pmb": true, "fct": true, "vi": true, "po": true, "lcda": true, "lga": true}
+
+var minorWords = map[string]bool{"of": true, "and": true, "by": true, "the": true}
+
+// StandardiseLine expands street abbreviations, title-cases words and tidies punctuation and
+// whitespace, e.g. "12,allen ave  off opebi rd" becomes "12, Allen Avenue Off Opebi Road"
+func StandardiseLine(line string) string {
+	line = poPattern.ReplaceAllString(line, "PO Box")
+	line = pmbPattern.ReplaceAllString(line, "PMB ")
+	line = commaPattern.ReplaceAllString(line, ", ")
+
+	fields := strings.Fields(line)
+	words := make([]string, 0, len(fields))
+	for i, field := range fields {
+		core := strings.TrimRight(field, ",;")
+		suffix := field[len(core):]
+		if core == "" {
+			continue
+		}
+
+		abbr := strings.ToLower(strings.TrimSuffix(core, "."))
+		expansion, isAbbr := streetAbbreviations[abbr]
+		if isAbbr && ambiguousAbbreviations[abbr] && (i == 0 || startsWithDigit(fields[i-1])) {
+			isAbbr = false
+		}
+
+		switch {
+		case isAbbr:
+			core = expansion
+		case i > 0 && minorWords[strings.ToLower(core)]:
+			core = strings.ToLower(core)
+		default:
+			core = titleWord(core)
+		}
+		words = append(words, core+suffix)
+	}
+
+	return strings.Trim(strings.Join(words, " "), " ,;")
+}
+
+// standardiseCity returns the gazetteer name of a known city, or the title-cased input
+func standardiseCity(city string) string {
+	if p, ok := lookupPlace(city); ok {
+		return p.Name
+	}
+	fields := strings.Fields(city)
+	for i, field := range fields {
+		fields[i] = titleWord(field)
+	}
+	return strings.Join(fields, " ")
+}
+
+// titleWord title-cases each hyphenated part of a word, keeping acronyms and house numbers such as
+// "12B" upper case and ordinals such as "4th" lower case
+func titleWord(word string) string {
+	parts := strings.Split(word, "-")
+	for i, part := range parts {
+		lower := strings.ToLower(part)
+		if ordinalPattern.MatchString(part) {
+			parts[i] = lower
+			continue
+		}
+		if acronyms[strings.TrimSuffix(lower, ".")] || startsWithDigit(part) {
+			parts[i] = strings.ToUpper(part)
+			continue
+		}
+		runes := []rune(lower)
+		if len(runes) > 0 {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		parts[i] = string(runes)
+	}
+	return strings.Join(parts, "-")
+}
+
+func startsWithDigit(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
@@ -0,0 +1,121 @@
+package address
+
+import (
+	"sort"
+	"strings"
+)
+
+// State is a Nigerian state or the Federal Capital Territory. The coordinates are those of the
+// state capital and serve as the gazetteer fallback when nothing more precise is known.
+type State struct {
+	Name      string
+	Code      string // ISO 3166-2:NG subdivision code without the country prefix
+	Capital   string
+	Latitude  float64
+	Longitude float64
+	aliases   []string
+}
+
+// FCT is the canonical name of the Federal Capital Territory
+const FCT = "Federal Capital Territory"
+
+var states = []State{
+	{Name: "Abia", Code: "AB", Capital: "Umuahia", Latitude: 5.5320, Longitude: 7.4860},
+	{Name: "Adamawa", Code: "AD", Capital: "Yola", Latitude: 9.2035, Longitude: 12.4954},
+	{Name: "Akwa Ibom", Code: "AK", Capital: "Uyo", Latitude: 5.0377, Longitude: 7.9128},
+	{Name: "Anambra", Code: "AN", Capital: "Awka", Latitude: 6.2104, Longitude: 7.0741},
+	{Name: "Bauchi", Code: "BA", Capital: "Bauchi", Latitude: 10.3158, Longitude: 9.8442},
+	{Name: "Bayelsa", Code: "BY", Capital: "Yenagoa", Latitude: 4.9267, Longitude: 6.2676},
+	{Name: "Benue", Code: "BE", Capital: "Makurdi", Latitude: 7.7322, Longitude: 8.5391},
+	{Name: "Borno", Code: "BO", Capital: "Maiduguri", Latitude: 11.8311, Longitude: 13.1510},
+	{Name: "Cross River", Code: "CR", Capital: "Calabar", Latitude: 4.9757, Longitude: 8.3417},
+	{Name: "Delta", Code: "DE", Capital: "Asaba", Latitude: 6.1980, Longitude: 6.7319},
+	{Name: "Ebonyi", Code: "EB", Capital: "Abakaliki", Latitude: 6.3249, Longitude: 8.1137},
+	{Name: "Edo", Code: "ED", Capital: "Benin City", Latitude: 6.3350, Longitude: 5.6037},
+	{Name: "Ekiti", Code: "EK", Capital: "Ado-Ekiti", Latitude: 7.6211, Longitude: 5.2214},
+	{Name: "Enugu", Code: "EN", Capital: "Enugu", Latitude: 6.4584, Longitude: 7.5464},
+	{Name: "Gombe", Code: "GO", Capital: "Gombe", Latitude: 10.2897, Longitude: 11.1673},
+	{Name: "Imo", Code: "IM", Capital: "Owerri", Latitude: 5.4836, Longitude: 7.0333},
+	{Name: "Jigawa", Code: "JI", Capital: "Dutse", Latitude: 11.7562, Longitude: 9.3388},
+	{Name: "Kaduna", Code: "KD", Capital: "Kaduna", Latitude: 10.5105, Longitude: 7.4165},
+	{Name: "Kano", Code: "KN", Capital: "Kano", Latitude: 12.0022, Longitude: 8.5920},
+	{Name: "Katsina", Code: "KT", Capital: "Katsina", Latitude: 12.9908, Longitude: 7.6018},
+	{Name: "Kebbi", Code: "KE", Capital: "Birnin Kebbi", Latitude: 12.4539, Longitude: 4.1975},
+	{Name: "Kogi", Code: "KO", Capital: "Lokoja", Latitude: 7.8023, Longitude: 6.7333},
+	{Name: "Kwara", Code: "KW", Capital: "Ilorin", Latitude: 8.4966, Longitude: 4.5426},
+	{Name: "Lagos", Code: "LA", Capital: "Ikeja", Latitude: 6.6018, Longitude: 3.3515},
+	{Name: "Nasarawa", Code: "NA", Capital: "Lafia", Latitude: 8.4939, Longitude: 8.5153, aliases: []string{"nassarawa"}},
+	{Name: "Niger", Code: "NI", Capital: "Minna", Latitude: 9.5836, Longitude: 6.5463},
+	{Name: "Ogun", Code: "OG", Capital: "Abeokuta", Latitude: 7.1475, Longitude: 3.3619},
+	{Name: "Ondo", Code: "ON", Capital: "Akure", Latitude: 7.2571, Longitude: 5.2058},
+	{Name: "Osun", Code: "OS", Capital: "Osogbo", Latitude: 7.7827, Longitude: 4.5418},
+	{Name: "Oyo", Code: "OY", Capital: "Ibadan", Latitude: 7.3775, Longitude: 3.9470},
+	{Name: "Plateau", Code: "PL", Capital: "Jos", Latitude: 9.8965, Longitude: 8.8583},
+	{Name: "Rivers", Code: "RI", Capital: "Port Harcourt", Latitude: 4.8156, Longitude: 7.0498},
+	{Name: "Sokoto", Code: "SO", Capital: "Sokoto", Latitude: 13.0059, Longitude: 5.2476},
+	{Name: "Taraba", Code: "TA", Capital: "Jalingo", Latitude: 8.8937, Longitude: 11.3596},
+	{Name: "Yobe", Code: "YO", Capital: "Damaturu", Latitude: 11.7470, Longitude: 11.9608},
+	{Name: "Zamfara", Code: "ZA", Capital: "Gusau", Latitude: 12.1704, Longitude: 6.6641},
+	{Name: FCT, Code: "FC", Capital: "Abuja", Latitude: 9.0579, Longitude: 7.4951, aliases: []string{"fct", "abuja", "fct abuja", "abuja fct", "federal capital territory abuja"}},
+}
+
+// stateIndex maps the lookup form of state names, codes and aliases to their state
+var stateIndex = func() map[string]*State {
+	index := make(map[string]*State)
+	for i := range states {
+		state := &states[i]
+		index[lookupKey(state.Name)] = state
+		index[lookupKey(state.Code)] = state
+		for _, alias := range state.aliases {
+			index[lookupKey(alias)] = state
+		}
+	}
+	return index
+}()
+
+// LookupState resolves a free-text state to a Nigerian state or the FCT. Names are matched case
+// and punctuation insensitively, with or without a "State" suffix; ISO codes such as "LA" or
+// "NG-LA" and common aliases such as "Abuja" are accepted too.
+func LookupState(name string) (*State, bool) {
+	key := lookupKey(name)
+	if key == "" {
+		return nil, false
+	}
+	if state, ok := stateIndex[key]; ok {
+		return state, true
+	}
+	key = strings.TrimPrefix(key, "ng")
+	key = strings.TrimSuffix(key, "state")
+	state, ok := stateIndex[key]
+	return state, ok
+}
+
+// CanonicalState returns the canonical name of a state, or false if it is not a Nigerian state or
+// the FCT
+func CanonicalState(name string) (string, bool) {
+	state, ok := LookupState(name)
+	if !ok {
+		return "", false
+	}
+	return state.Name, true
+}
+
+// StateNames returns the canonical names of the Nigerian states and the FCT in alphabetical order
+func StateNames() []string {
+	names := make([]string, 0, len(states))
+	for _, state := range states {
+		names = append(names, state.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupKey lowercases a name and drops everything but letters and digits
+func lookupKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, strings.ToLower(name))
+}
@@ -0,0 +1,232 @@
+// Package app wires the repositories and services of the compliance service from config.Config.
+// The HTTP server and the background worker are built from the same App.
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/kodra-pay/compliance-service/internal/address"
+	"github.com/kodra-pay/compliance-service/internal/cache"
+	"github.com/kodra-pay/compliance-service/internal/config"
+	"github.com/kodra-pay/compliance-service/internal/encryption"
+	"github.com/kodra-pay/compliance-service/internal/jobs"
+	"github.com/kodra-pay/compliance-service/internal/ratelimit"
+	"github.com/kodra-pay/compliance-service/internal/repositories"
+	"github.com/kodra-pay/compliance-service/internal/services"
+	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+)
+
+// App is the application container: it holds the wired services. Services that only have a
+// Postgres implementation are nil when the App is built without a database.
+type App struct {
+	Config *config.Config
+	DB     *sql.DB       // nil for in-memory storage
+	Redis  *redis.Client // nil when REDIS_ADDR is not set
+
+	Idempotency repositories.IdempotencyStore
+	RateLimiter ratelimit.Limiter
+
+	KYC         *services.KYCService
+	PII         *services.PIIService
+	Risk        *services.RiskService
+	Review      *services.ReviewService
+	EDD         *services.EDDService
+	CTR         *services.CTRService
+	Graph       *services.GraphService
+	Privacy     *services.PrivacyService
+	KeyRotation *services.KeyRotationService
+}
+
+// Stores are the persistence backends the services are built on
+type Stores struct {
+	DB          *sql.DB // backs every Postgres-only repository; nil leaves those services out
+	Encryptor   *encryption.FieldEncryptor
+	Redis       *redis.Client // optional; backs the idempotency keys when set
+	KYC         repositories.KYCStore
+	Compliance  repositories.ComplianceRepository
+	Idempotency repositories.IdempotencyStore
+}
+
+// OpenDB opens the configured database with its pool settings and pings it
+func OpenDB(cfg *config.Config) (*sql.DB, error) {
+	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	db.SetMaxOpenConns(cfg.DB.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.DB.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.DB.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.DB.ConnMaxIdleTime)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+	return db, nil
+}
+
+// OpenRedis returns a client for the configured Redis, or nil when REDIS_ADDR is not set. Connections
+// are opened on first use, so an unavailable Redis does not stop the service from starting. Dials
+// and commands are bounded by the health check timeout, and a request waits at most as long for a
+// pooled connection, so a slow Redis cannot hold requests or open connections without limit.
+func OpenRedis(cfg *config.Config) *redis.Client {
+	if cfg.RedisAddr == "" {
+		return nil
+	}
+	timeout := cfg.Health.CheckTimeout
+	return redis.NewClient(&redis.Options{
+		Addr:                  cfg.RedisAddr,
+		Password:              cfg.RedisPassword,
+		DB:                    cfg.RedisDB,
+		DialTimeout:           timeout,
+		ReadTimeout:           timeout,
+		WriteTimeout:          timeout,
+		PoolTimeout:           timeout,
+		ContextTimeoutEnabled: true,
+	})
+}
+
+// New wires the services on the configured storage. On Postgres it connects to the database and
+// loads the encryption keys.
+func New(cfg *config.Config) (*App, error) {
+	if cfg.Storage == config.StorageMemory {
+		return NewInMemory(cfg), nil
+	}
+
+	keyProvider, err := encryption.NewLocalKeyProvider(cfg.Encryption.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load encryption keys: %w", err)
+	}
+	fieldEncryptor := encryption.NewFieldEncryptor(keyProvider)
+
+	db, err := OpenDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Idempotency keys are kept in Redis when it is configured, and in Postgres without it or
+	// while it is unavailable
+	redisClient := OpenRedis(cfg)
+	var idempotencyStore repositories.IdempotencyStore = repositories.NewIdempotencyRepository(db)
+	if redisClient != nil {
+		idempotencyStore = repositories.NewRedisIdempotencyStore(redisClient, idempotencyStore)
+	}
+
+	return Build(cfg, Stores{
+		DB:          db,
+		Encryptor:   fieldEncryptor,
+		Redis:       redisClient,
+		KYC:         repositories.NewKYCRepository(db, fieldEncryptor),
+		Compliance:  repositories.NewPostgresComplianceRepository(db),
+		Idempotency: idempotencyStore,
+	}), nil
+}
+
+// NewInMemory wires the KYC services on an in-memory store, without a database or encryption
+// keys. The services that need Postgres are left out, see config.StorageMemory. Redis is used if it
+// is configured.
+func NewInMemory(cfg *config.Config) *App {
+	slog.Warn("in-memory storage: reviews, PII unmasking, EDD, CTR, risk, screening, entity graph " +
+		"and privacy need Postgres; their routes answer 501 and their jobs do not run")
+
+	redisClient := OpenRedis(cfg)
+	var idempotencyStore repositories.IdempotencyStore = repositories.NewMemoryIdempotencyStore()
+	if redisClient != nil {
+		idempotencyStore = repositories.NewRedisIdempotencyStore(redisClient, idempotencyStore)
+	}
+
+	return Build(cfg, Stores{
+		Redis:       redisClient,
+		KYC:         repositories.NewMemoryKYCStore(),
+		Compliance:  repositories.NewMemoryComplianceRepository(),
+		Idempotency: idempotencyStore,
+	})
+}
+
+// Build wires the services on the given stores
+func Build(cfg *config.Config, stores Stores) *App {
+	// Address normalisation, geocoding through the configured provider if any
+	var geocoder address.Geocoder
+	if cfg.Address.GeocoderURL != "" {
+		geocoder = address.NewNominatimGeocoder(cfg.Address.GeocoderURL, cfg.Address.GeocoderUserAgent, cfg.Address.GeocoderTimeout)
+	}
+	addressNormalizer := address.NewNormalizer(geocoder, cfg.Address.Geocode, cfg.Address.VirtualOfficeMarkers)
+
+	// Every KYC store call is a span in the request's trace
+	kycStore := repositories.TraceKYCStore(stores.KYC)
+
+	a := &App{Config: cfg, DB: stores.DB, Redis: stores.Redis, Idempotency: stores.Idempotency}
+
+	// Rate limits are shared by all instances through Redis, and per instance without it or while
+	// it is unavailable
+	a.RateLimiter = ratelimit.NewMemoryLimiter()
+	if stores.Redis != nil {
+		a.RateLimiter = ratelimit.NewRedisLimiter(stores.Redis, a.RateLimiter, cfg.RateLimit.RedisTimeout)
+	}
+	a.KeyRotation = services.NewKeyRotationService(kycStore, cfg.Encryption.RotationBatch)
+
+	if db := stores.DB; db != nil {
+		txnRepo := repositories.NewTransactionRepository(db)
+		riskRepo := repositories.NewRiskRepository(db)
+		screeningRepo := repositories.NewScreeningRepository(db)
+
+		a.Risk = services.NewRiskService(riskRepo, kycStore, screeningRepo, stores.Compliance, txnRepo, cfg.Risk)
+		a.Review = services.NewReviewService(repositories.NewReviewRepository(db), riskRepo, cfg.Review)
+		a.EDD = services.NewEDDService(repositories.NewEDDRepository(db), kycStore, riskRepo, screeningRepo, cfg.EDD)
+		a.PII = services.NewPIIService(kycStore, repositories.NewPIIAccessRepository(db))
+		a.CTR = services.NewCTRService(repositories.NewCTRRepository(db), txnRepo, cfg.CTR)
+		a.Graph = services.NewGraphService(repositories.NewGraphRepository(db), kycStore, stores.Encryptor, cfg.Graph)
+		a.Privacy = services.NewPrivacyService(repositories.NewPrivacyRepository(db), cfg.Retention)
+	}
+
+	// Risk scoring, periodic reviews and EDD are optional for the KYC service
+	a.KYC = services.NewKYCService(kycStore, a.Risk, a.Review, a.EDD, addressNormalizer, cfg.KYC)
+	if stores.Redis != nil {
+		a.KYC.WithStatusCache(cache.New(stores.Redis, "kyc_status", cfg.KYC.StatusCacheTimeout))
+	}
+	return a
+}
+
+// Scheduler returns the background jobs of the wired services on their configured intervals
+func (a *App) Scheduler() *jobs.Scheduler {
+	cfg := a.Config
+	scheduler := jobs.NewScheduler()
+	scheduler.Every(cfg.Encryption.RotationInterval, jobs.Func("kyc-field-reencryption", a.KeyRotation.Reencrypt))
+	scheduler.Every(cfg.Idempotency.PurgeInterval, jobs.Func("idempotency-key-purge", a.purgeIdempotencyKeys))
+	// The jobs below need Postgres, see config.StorageMemory
+	if a.DB == nil {
+		return scheduler
+	}
+	scheduler.Every(cfg.CTR.Interval, jobs.Func("ctr-daily-batch", a.CTR.GeneratePreviousDay))
+	scheduler.Every(cfg.Risk.RescoreInterval, jobs.Func("risk-rescore", a.Risk.RescoreStale))
+	scheduler.Every(cfg.Review.Interval, jobs.Func("kyc-periodic-review", a.Review.RunCycle))
+	scheduler.Every(cfg.Retention.PurgeInterval, jobs.Func("retention-purge", a.Privacy.RunPurge))
+	scheduler.Every(cfg.Graph.SyncInterval, jobs.Func("entity-graph-sync", a.Graph.Sync))
+	return scheduler
+}
+
+// purgeIdempotencyKeys deletes expired idempotency keys from stores that do not expire them
+func (a *App) purgeIdempotencyKeys(ctx context.Context) error {
+	purged, err := a.Idempotency.PurgeExpired(ctx)
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		slog.Info("purged expired idempotency keys", "count", purged)
+	}
+	return nil
+}
+
+// Close releases the database and Redis connections
+func (a *App) Close() error {
+	if a.Redis != nil {
+		a.Redis.Close()
+	}
+	if a.DB == nil {
+		return nil
+	}
+	return a.DB.Close()
+}
@@ -0,0 +1,205 @@
+// Package cache is a read-through cache in Redis for hot lookups. The cache is an optimisation
+// only: every call is bounded by a short timeout, and after a failure Redis is bypassed for a
+// while, so callers fall back to their store without waiting on an unavailable Redis.
+//
+// Lookups are counted in the compliance_cache_requests_total metric by cache name and result.
+package cache
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/kodra-pay/compliance-service/internal/metrics"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrUnavailable is returned while Redis is bypassed after a failure
+var ErrUnavailable = errors.New("cache: redis is unavailable")
+
+// backoff is how long Redis is bypassed after a failed call
+const backoff = 5 * time.Second
+
+// loadTimeout bounds a load shared by concurrent misses. The load does not stop when the caller
+// that started it goes away, since the others are waiting for its result.
+const loadTimeout = 10 * time.Second
+
+// generationTTL is how long a key's generation is kept after it was last bumped. A load that takes
+// longer than this could cache a value read before the last Delete.
+const generationTTL = 24 * time.Hour
+
+// setScript sets KEYS[1] to ARGV[1] for ARGV[2] milliseconds if the generation in KEYS[2] is still
+// ARGV[3], the empty string standing for no generation. It returns whether the key was set.
+var setScript = redis.NewScript(`
+local generation = redis.call('GET', KEYS[2]) or ''
+if generation ~= ARGV[3] then return 0 end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1
+`)
+
+// deleteScript deletes KEYS[1] and bumps its generation in KEYS[2], which expires after ARGV[1]
+// milliseconds
+var deleteScript = redis.NewScript(`
+redis.call('DEL', KEYS[1])
+redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[1])
+return 1
+`)
+
+// Cache stores values in Redis under keys prefixed with its name. Each key has a generation, bumped
+// by Delete, so a value loaded before a Delete is not cached after it. It is safe for concurrent use.
+type Cache struct {
+	client    *redis.Client
+	name      string
+	timeout   time.Duration
+	downUntil atomic.Int64 // unix nanoseconds until which Redis is bypassed
+	flights   Group
+}
+
+// New returns a cache whose Redis keys start with name, such as kyc_status. timeout bounds each
+// Redis call.
+func New(client *redis.Client, name string, timeout time.Duration) *Cache {
+	return &Cache{client: client, name: name, timeout: timeout}
+}
+
+// Get returns the cached value for key, or found false on a miss
+func (c *Cache) Get(ctx context.Context, key string) (value []byte, found bool, err error) {
+	err = c.call(ctx, func(ctx context.Context) error {
+		value, err = c.client.Get(ctx, c.name+":"+key).Bytes()
+		return err
+	})
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set caches value for key. The TTL is shortened by up to a tenth at random, so entries written
+// together do not all expire together.
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.call(ctx, func(ctx context.Context) error {
+		return c.client.Set(ctx, c.name+":"+key, value, jitter(ttl)).Err()
+	})
+}
+
+// Delete removes the cached value for key and bumps its generation, so loads that started before
+// it do not cache what they read
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	return c.call(ctx, func(ctx context.Context) error {
+		return deleteScript.Run(ctx, c.client, []string{c.name + ":" + key, c.generationKey(key)},
+			generationTTL.Milliseconds()).Err()
+	})
+}
+
+// Load returns the value of key from the cache, or calls load on a miss and caches its result.
+// Concurrent misses for the same key in this process share one call to load, so an expired hot
+// key does not send a burst of queries to the store. The shared load runs without the caller's
+// cancellation, bounded by loadTimeout. When Redis fails the result of load is returned uncached.
+func (c *Cache) Load(ctx context.Context, key string, ttl time.Duration, load func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	value, generation, found, err := c.getWithGeneration(ctx, key)
+	switch {
+	case err != nil:
+		metrics.CacheRequests.WithLabelValues(c.name, "error").Inc()
+		c.warn(ctx, err)
+	case found:
+		metrics.CacheRequests.WithLabelValues(c.name, "hit").Inc()
+		return value, nil
+	default:
+		metrics.CacheRequests.WithLabelValues(c.name, "miss").Inc()
+	}
+	cacheable := err == nil
+
+	// A miss only joins a load that read the same generation: one that started before a Delete
+	// may return the value the Delete invalidated
+	result, err := c.flights.Do(key+"@"+generation, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if cacheable {
+			if err := c.setIfGeneration(ctx, key, value, ttl, generation); err != nil {
+				c.warn(ctx, err)
+			}
+		}
+		return value, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
+// getWithGeneration reads the value of key together with its generation, which is empty before the
+// first Delete
+func (c *Cache) getWithGeneration(ctx context.Context, key string) (value []byte, generation string, found bool, err error) {
+	var values []interface{}
+	err = c.call(ctx, func(ctx context.Context) error {
+		values, err = c.client.MGet(ctx, c.name+":"+key, c.generationKey(key)).Result()
+		return err
+	})
+	if err != nil {
+		return nil, "", false, err
+	}
+	if len(values) != 2 {
+		return nil, "", false, errors.New("cache: unexpected reply from Redis")
+	}
+	generation, _ = values[1].(string)
+	cached, found := values[0].(string)
+	return []byte(cached), generation, found, nil
+}
+
+// setIfGeneration caches value for key like Set, unless the generation of key is no longer
+// generation because it was deleted in the meantime
+func (c *Cache) setIfGeneration(ctx context.Context, key string, value []byte, ttl time.Duration, generation string) error {
+	return c.call(ctx, func(ctx context.Context) error {
+		return setScript.Run(ctx, c.client, []string{c.name + ":" + key, c.generationKey(key)},
+			value, jitter(ttl).Milliseconds(), generation).Err()
+	})
+}
+
+func (c *Cache) generationKey(key string) string {
+	return c.name + ":" + key + ":generation"
+}
+
+// jitter shortens ttl by up to a tenth at random
+func jitter(ttl time.Duration) time.Duration {
+	if jitter := int64(ttl / 10); jitter > 0 {
+		ttl -= time.Duration(rand.Int63n(jitter))
+	}
+	return ttl
+}
+
+// warn logs a Redis failure. Calls refused while Redis is bypassed are not logged, so an outage
+// logs about once per backoff period.
+func (c *Cache) warn(ctx context.Context, err error) {
+	if !errors.Is(err, ErrUnavailable) {
+		logging.FromContext(ctx).Warn("cache unavailable, reading from the store", "cache", c.name, "error", err)
+	}
+}
+
+// call runs a Redis command bounded by the cache's timeout. A failure other than a nil or error
+// reply bypasses Redis for the backoff period.
+func (c *Cache) call(ctx context.Context, command func(ctx context.Context) error) error {
+	if time.Now().UnixNano() < c.downUntil.Load() {
+		return ErrUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := command(ctx)
+	var replyErr redis.Error
+	if err != nil && !errors.As(err, &replyErr) {
+		c.downUntil.Store(time.Now().Add(backoff).UnixNano())
+	}
+	return err
+}
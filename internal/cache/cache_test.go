@@ -0,0 +1,227 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestGroup(t *testing.T) {
+	var g Group
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = g.Do("kyc:42", func() (interface{}, error) {
+				calls.Add(1)
+				<-release
+				return "approved", nil
+			})
+		}(i)
+	}
+	// Let the callers reach Do before the first call returns
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("concurrent callers made %d calls, want 1", n)
+	}
+	for i, got := range results {
+		if got != "approved" {
+			t.Errorf("caller %d got %v, want the shared result", i, got)
+		}
+	}
+
+	// A finished call is not reused
+	failed := errors.New("store down")
+	if _, err := g.Do("kyc:42", func() (interface{}, error) { return nil, failed }); err != failed {
+		t.Errorf("second call returned %v, want its own error", err)
+	}
+}
+
+func TestJitter(t *testing.T) {
+	tests := []struct {
+		ttl      time.Duration
+		min, max time.Duration
+	}{
+		{0, 0, 0},
+		{9 * time.Nanosecond, 9 * time.Nanosecond, 9 * time.Nanosecond},
+		{time.Minute, 54 * time.Second, time.Minute},
+		{time.Hour, 54 * time.Minute, time.Hour},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			if got := jitter(tt.ttl); got < tt.min || got > tt.max {
+				t.Fatalf("jitter(%s) = %s, want between %s and %s", tt.ttl, got, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+// unreachable returns a cache whose Redis refuses every connection
+func unreachable(t *testing.T) *Cache {
+	t.Helper()
+
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+	return New(client, "kyc_status", 50*time.Millisecond)
+}
+
+func TestLoadWithoutRedis(t *testing.T) {
+	c := unreachable(t)
+	ctx := context.Background()
+
+	var loads int
+	load := func(ctx context.Context) ([]byte, error) {
+		loads++
+		return []byte("approved"), nil
+	}
+	for i := 0; i < 3; i++ {
+		value, err := c.Load(ctx, "42", time.Minute, load)
+		if err != nil || string(value) != "approved" {
+			t.Fatalf("load %d = %q, %v, want the loaded value", i, value, err)
+		}
+	}
+	if loads != 3 {
+		t.Errorf("store was read %d times, want once per call while Redis is down", loads)
+	}
+
+	// Redis is bypassed after the first failure
+	if _, _, err := c.Get(ctx, "42"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("get during the backoff returned %v, want ErrUnavailable", err)
+	}
+	if err := c.Delete(ctx, "42"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("delete during the backoff returned %v, want ErrUnavailable", err)
+	}
+
+	failed := errors.New("store down")
+	if _, err := c.Load(ctx, "42", time.Minute, func(context.Context) ([]byte, error) { return nil, failed }); err != failed {
+		t.Errorf("load returned %v, want the store error", err)
+	}
+}
+
+func TestLoadOutlivesCaller(t *testing.T) {
+	c := unreachable(t)
+	c.downUntil.Store(time.Now().Add(time.Minute).UnixNano())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.Load(ctx, "42", time.Minute, func(ctx context.Context) ([]byte, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > loadTimeout {
+			return nil, fmt.Errorf("load deadline %v, want within %s", deadline, loadTimeout)
+		}
+		return []byte("approved"), nil
+	})
+	if err != nil {
+		t.Errorf("load for a cancelled caller failed: %v", err)
+	}
+}
+
+// TestCache runs against the Redis in TEST_REDIS_ADDR
+func TestCache(t *testing.T) {
+	addr := os.Getenv("TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("TEST_REDIS_ADDR is not set")
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	t.Cleanup(func() { client.Close() })
+
+	ctx := context.Background()
+	c := New(client, fmt.Sprintf("test_%d", time.Now().UnixNano()), time.Second)
+	loader := func(value string, loads *atomic.Int32) func(context.Context) ([]byte, error) {
+		return func(context.Context) ([]byte, error) {
+			loads.Add(1)
+			return []byte(value), nil
+		}
+	}
+
+	t.Run("miss then hit", func(t *testing.T) {
+		var loads atomic.Int32
+		for i := 0; i < 2; i++ {
+			value, err := c.Load(ctx, "hit", time.Minute, loader("pending", &loads))
+			if err != nil || string(value) != "pending" {
+				t.Fatalf("load %d = %q, %v", i, value, err)
+			}
+		}
+		if n := loads.Load(); n != 1 {
+			t.Errorf("store was read %d times, want once", n)
+		}
+		if ttl := client.PTTL(ctx, c.name+":hit").Val(); ttl <= 53*time.Second || ttl > time.Minute {
+			t.Errorf("cached TTL = %s, want the jittered minute", ttl)
+		}
+	})
+
+	t.Run("delete invalidates", func(t *testing.T) {
+		var loads atomic.Int32
+		if _, err := c.Load(ctx, "delete", time.Minute, loader("pending", &loads)); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Delete(ctx, "delete"); err != nil {
+			t.Fatal(err)
+		}
+		if _, found, err := c.Get(ctx, "delete"); found || err != nil {
+			t.Fatalf("get after delete found = %v, %v, want a miss", found, err)
+		}
+		value, err := c.Load(ctx, "delete", time.Minute, loader("approved", &loads))
+		if err != nil || string(value) != "approved" {
+			t.Errorf("load after delete = %q, %v, want the new value", value, err)
+		}
+		if value, _, _ := c.Get(ctx, "delete"); string(value) != "approved" {
+			t.Errorf("cached value after reload = %q, want approved", value)
+		}
+	})
+
+	t.Run("delete during a load", func(t *testing.T) {
+		started, release := make(chan struct{}), make(chan struct{})
+		done := make(chan []byte)
+		go func() {
+			value, _ := c.Load(ctx, "race", time.Minute, func(context.Context) ([]byte, error) {
+				close(started)
+				<-release
+				return []byte("pending"), nil
+			})
+			done <- value
+		}()
+		<-started
+		if err := c.Delete(ctx, "race"); err != nil {
+			t.Fatal(err)
+		}
+
+		// A miss after the delete reads the store again instead of joining the stale load
+		var loads atomic.Int32
+		value, err := c.Load(ctx, "race", time.Minute, loader("approved", &loads))
+		if err != nil || string(value) != "approved" || loads.Load() != 1 {
+			t.Errorf("load after delete = %q, %v with %d reads, want approved from its own read", value, err, loads.Load())
+		}
+
+		close(release)
+		if stale := <-done; string(stale) != "pending" {
+			t.Errorf("stale load returned %q, want its own value", stale)
+		}
+		if value, _, _ := c.Get(ctx, "race"); string(value) != "approved" {
+			t.Errorf("cached value = %q, want approved, not the value read before the delete", value)
+		}
+	})
+
+	t.Cleanup(func() {
+		for _, key := range []string{"hit", "delete", "race"} {
+			client.Del(ctx, c.name+":"+key, c.generationKey(key))
+		}
+	})
+}
@@ -1,48 +1,547 @@
 package config
 
 import (
-	"log"
-	"os"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// Storage backends.
+//
+// StorageMemory serves the KYC submission, status, decision and person routes for tests and local
+// development; nothing is persisted. Periodic reviews, PII unmasking, EDD, CTR reports, risk
+// scoring, screening, the entity graph and privacy requests need Postgres: their routes answer 501
+// and their jobs do not run. KYC approvals are not checked against EDD or risk, and declared persons
+// only need to be identity-verified, not screened clear.
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+)
+
+// Trace exporters
+const (
+	TracingNone   = "none"
+	TracingStdout = "stdout" // one JSON line per span, for local development
+	TracingOTLP   = "otlp"   // OTLP/HTTP to an OpenTelemetry collector
+)
+
+// Config is the effective configuration of the service. Every setting can be given in the
+// environment or in a config file of KEY=VALUE lines; the environment wins.
 type Config struct {
-	ServicePort string
-	DatabaseURL string
-	RedisAddr   string
+	ServiceName   string
+	ServicePort   string
+	RunJobs       bool   // serve also runs the background jobs, without a separate worker
+	Storage       string // StoragePostgres or StorageMemory
+	DatabaseURL   string
+	DB            DBConfig
+	HTTP          HTTPConfig
+	Health        HealthConfig
+	Log           LogConfig
+	Tracing       TracingConfig
+	RedisAddr     string // optional; without it idempotency keys are kept in Postgres
 	RedisPassword string
-	RedisDB int
+	RedisDB       int
+	Idempotency   IdempotencyConfig
+	RateLimit     RateLimitConfig
+	CTR           CTRConfig
+	Risk          RiskConfig
+	Review        ReviewConfig
+	EDD           EDDConfig
+	KYC           KYCConfig
+	Auth          AuthConfig
+	Encryption    EncryptionConfig
+	Retention     RetentionConfig
+	Graph         GraphConfig
+	Address       AddressConfig
+
+	ShutdownTimeout time.Duration // how long serve and worker wait for requests and jobs to drain
+
+	invalid []string // keys whose values could not be parsed, reported by Validate
+}
+
+// DBConfig holds the database connection pool settings. Zero means unlimited, as in database/sql.
+type DBConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// HTTPConfig holds the HTTP server settings
+type HTTPConfig struct {
+	MaxBodyBytes int // cap on any request body; routes may set lower caps
+
+	// Behind a proxy, ProxyHeader names the header carrying the client IP, such as X-Real-IP.
+	// It is only read from TrustedProxies (IPs or CIDR ranges), so clients cannot spoof it.
+	ProxyHeader    string
+	TrustedProxies []string
+}
+
+// HealthConfig holds the readiness probe settings
+type HealthConfig struct {
+	CheckTimeout time.Duration // per dependency check
+}
+
+// LogConfig holds the structured logging settings
+type LogConfig struct {
+	Level  string // debug, info, warn or error
+	Format string // json or text
+}
+
+// TracingConfig holds the distributed tracing settings
+type TracingConfig struct {
+	Exporter     string  // TracingNone, TracingStdout or TracingOTLP
+	OTLPEndpoint string  // collector base URL, /v1/traces is appended
+	SampleRatio  float64 // share of new traces recorded; traces from callers keep their decision
+}
+
+// IdempotencyConfig holds the Idempotency-Key settings
+type IdempotencyConfig struct {
+	TTL           time.Duration // how long a completed response is replayed for its key
+	LockTimeout   time.Duration // how long a key stays claimed by a request that never completes
+	PurgeInterval time.Duration // how often expired keys are deleted from Postgres
+}
+
+// RateLimitConfig holds the rate and body size limits of the KYC write endpoints. Rates are token
+// buckets: a caller may send Burst requests at once, refilled at the given rate per minute.
+type RateLimitConfig struct {
+	Enabled            bool
+	MerchantPerMinute  float64 // per merchant, or per user for staff callers
+	MerchantBurst      int
+	IPPerMinute        float64
+	IPBurst            int
+	SubmitMaxBodyBytes int // cap on KYC submission bodies
+	WriteMaxBodyBytes  int // cap on the other KYC write bodies, such as status updates
+
+	// Redis calls taking longer than RedisTimeout fall back to per-instance limits
+	RedisTimeout time.Duration
+}
+
+// CTRConfig holds the Currency Transaction Report settings. Thresholds are in minor units (kobo).
+type CTRConfig struct {
+	IndividualThreshold int64
+	CorporateThreshold  int64
+	Currency            string
+	Channels            []string
+	ReportingEntityID   string
+	Interval            time.Duration
+}
+
+// RiskConfig holds the risk scoring model settings
+type RiskConfig struct {
+	Weights              map[string]float64 // factor name -> relative weight
+	HighRiskCategories   []string
+	MediumRiskCategories []string
+	HighRiskStates       []string
+	MediumTierThreshold  float64
+	HighTierThreshold    float64
+	HighVolumeThreshold  int64  // 30-day transaction volume in minor units of Currency
+	Currency             string // only transactions in this currency count towards the volume
+	RescoreInterval      time.Duration
+	MaxScoreAge          time.Duration
+}
+
+// ReviewConfig holds the periodic KYC review cycle per risk tier
+type ReviewConfig struct {
+	HighRiskMonths   int
+	MediumRiskMonths int
+	LowRiskMonths    int
+	OpenBefore       time.Duration // how long before the due date a review task is opened
+	EscalateAfter    time.Duration // grace period after the due date before escalation
+	Interval         time.Duration
+}
+
+// EDDConfig holds the enhanced due diligence settings
+type EDDConfig struct {
+	Categories    []string // business categories that always require EDD
+	Items         []string // checklist items created for every EDD case
+	ApproverRoles []string // roles allowed to sign off an EDD checklist
+}
+
+// KYCConfig holds the KYC submission rules
+type KYCConfig struct {
+	UBOThreshold       float64  // ownership percentage at or above which a shareholder must be identified as a UBO
+	FourEyes           bool     // status changes must be confirmed by a second reviewer
+	BusinessCategories []string // accepted business_category values
+	MerchantServiceURL string   // merchant-service base URL, notified of KYC status changes
+
+	// KYC status lookups are cached in Redis, when it is configured, for StatusCacheTTL. Redis
+	// calls taking longer than StatusCacheTimeout fall back to the database.
+	StatusCacheTTL     time.Duration
+	StatusCacheTimeout time.Duration
+}
+
+// AuthConfig holds the JWT validation settings
+type AuthConfig struct {
+	JWTSecret   string
+	JWTIssuer   string
+	JWTAudience string
+}
+
+// EncryptionConfig holds the field-level encryption settings
+type EncryptionConfig struct {
+	KeyFile          string // local keyfile with the key-encryption keys and the blind index key
+	RotationInterval time.Duration
+	RotationBatch    int
+}
+
+// RetentionConfig holds the data retention policy per entity. KYC submissions and their documents
+// are kept for the given months after the merchant relationship ends, KYC records after their last
+// update and alerts after they were raised.
+type RetentionConfig struct {
+	KYCSubmissionMonths int
+	DocumentMonths      int
+	KYCRecordMonths     int
+	AlertMonths         int
+	PurgeInterval       time.Duration
+	PurgeBatch          int
 }
 
-func LoadConfig() *Config {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080" // Default port
+// GraphConfig holds the entity graph settings
+type GraphConfig struct {
+	SyncInterval time.Duration
+	SyncBatch    int
+	DefaultDepth int
+	MaxDepth     int
+	MaxNodes     int // nodes returned per neighbourhood query
+}
+
+// AddressConfig holds the business address normalisation settings. Without a geocoder URL
+// addresses are geocoded against the offline gazetteer only.
+type AddressConfig struct {
+	Geocode              bool
+	GeocoderURL          string // Nominatim-compatible search endpoint
+	GeocoderUserAgent    string
+	GeocoderTimeout      time.Duration
+	VirtualOfficeMarkers []string
+}
+
+// Load builds the configuration from the defaults, the optional config file and the environment,
+// in increasing order of precedence. The result is not validated; call Validate before use.
+func Load(file string) (*Config, error) {
+	values, err := readFile(file)
+	if err != nil {
+		return nil, err
 	}
+	fileValues = values
+	invalidKeys = nil
 
-	dbURL := os.Getenv("DATABASE_URL")
+	dbURL := getEnv("DATABASE_URL", "")
 	if dbURL == "" {
-		log.Fatal("DATABASE_URL environment variable is not set")
+		// POSTGRES_URL is the name used by older deployments
+		dbURL = getEnv("POSTGRES_URL", "")
 	}
 
-	redisAddr := os.Getenv("REDIS_ADDR")
-	if redisAddr == "" {
-		redisAddr = "localhost:6379" // Default Redis address
+	return &Config{
+		ServiceName:   getEnv("SERVICE_NAME", "compliance-service"),
+		ServicePort:   getEnv("PORT", "7015"),
+		RunJobs:       getEnvBool("SERVE_RUN_JOBS", true),
+		Storage:       getEnv("STORAGE", StoragePostgres),
+		DatabaseURL:   dbURL,
+		DB:            LoadDBConfig(),
+		HTTP:          LoadHTTPConfig(),
+		Health:        LoadHealthConfig(),
+		Log:           LoadLogConfig(),
+		Tracing:       LoadTracingConfig(),
+		RedisAddr:     getEnv("REDIS_ADDR", ""),
+		RedisPassword: getEnv("REDIS_PASSWORD", ""),
+		RedisDB:       int(getEnvInt64("REDIS_DB", 0)),
+		Idempotency:   LoadIdempotencyConfig(),
+		RateLimit:     LoadRateLimitConfig(),
+		CTR:           LoadCTRConfig(),
+		Risk:          LoadRiskConfig(),
+		Review:        LoadReviewConfig(),
+		EDD:           LoadEDDConfig(),
+		KYC:           LoadKYCConfig(),
+		Auth:          LoadAuthConfig(),
+		Encryption:    LoadEncryptionConfig(),
+		Retention:     LoadRetentionConfig(),
+		Graph:         LoadGraphConfig(),
+		Address:       LoadAddressConfig(),
+
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		invalid:         invalidKeys,
+	}, nil
+}
+
+// LoadDBConfig reads the connection pool settings from the environment
+func LoadDBConfig() DBConfig {
+	return DBConfig{
+		MaxOpenConns:    int(getEnvInt64("DB_MAX_OPEN_CONNS", 25)),
+		MaxIdleConns:    int(getEnvInt64("DB_MAX_IDLE_CONNS", 10)),
+		ConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+		ConnMaxIdleTime: getEnvDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+	}
+}
+
+// LoadHTTPConfig reads the HTTP server settings from the environment
+func LoadHTTPConfig() HTTPConfig {
+	return HTTPConfig{
+		MaxBodyBytes:   int(getEnvInt64("HTTP_MAX_BODY_BYTES", 1<<20)),
+		ProxyHeader:    getEnv("HTTP_PROXY_HEADER", ""),
+		TrustedProxies: getEnvList("HTTP_TRUSTED_PROXIES", nil),
 	}
+}
+
+// LoadHealthConfig reads the readiness probe settings from the environment
+func LoadHealthConfig() HealthConfig {
+	return HealthConfig{
+		CheckTimeout: getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+	}
+}
 
-	redisPassword := os.Getenv("REDIS_PASSWORD")
-	
-	redisDBStr := os.Getenv("REDIS_DB")
-	redisDB, err := strconv.Atoi(redisDBStr)
+// LoadLogConfig reads the structured logging settings from the environment
+func LoadLogConfig() LogConfig {
+	return LogConfig{
+		Level:  getEnv("LOG_LEVEL", "info"),
+		Format: getEnv("LOG_FORMAT", "json"),
+	}
+}
+
+// LoadTracingConfig reads the tracing settings from the environment
+func LoadTracingConfig() TracingConfig {
+	return TracingConfig{
+		Exporter:     getEnv("TRACING_EXPORTER", TracingNone),
+		OTLPEndpoint: getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318"),
+		SampleRatio:  getEnvFloat("TRACING_SAMPLE_RATIO", 1),
+	}
+}
+
+// LoadIdempotencyConfig reads the Idempotency-Key settings from the environment
+func LoadIdempotencyConfig() IdempotencyConfig {
+	return IdempotencyConfig{
+		TTL:           getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		LockTimeout:   getEnvDuration("IDEMPOTENCY_LOCK_TIMEOUT", time.Minute),
+		PurgeInterval: getEnvDuration("IDEMPOTENCY_PURGE_INTERVAL", time.Hour),
+	}
+}
+
+// LoadRateLimitConfig reads the KYC endpoint limits from the environment
+func LoadRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Enabled:            getEnvBool("RATE_LIMIT_ENABLED", true),
+		MerchantPerMinute:  getEnvFloat("RATE_LIMIT_MERCHANT_PER_MINUTE", 1),
+		MerchantBurst:      int(getEnvInt64("RATE_LIMIT_MERCHANT_BURST", 5)),
+		IPPerMinute:        getEnvFloat("RATE_LIMIT_IP_PER_MINUTE", 5),
+		IPBurst:            int(getEnvInt64("RATE_LIMIT_IP_BURST", 20)),
+		SubmitMaxBodyBytes: int(getEnvInt64("KYC_SUBMIT_MAX_BODY_BYTES", 256<<10)),
+		WriteMaxBodyBytes:  int(getEnvInt64("KYC_WRITE_MAX_BODY_BYTES", 16<<10)),
+		RedisTimeout:       getEnvDuration("RATE_LIMIT_REDIS_TIMEOUT", 100*time.Millisecond),
+	}
+}
+
+// LoadCTRConfig reads the CTR settings from the environment.
+// Defaults follow the NFIU thresholds of NGN 5m for individuals and NGN 10m for corporates.
+func LoadCTRConfig() CTRConfig {
+	return CTRConfig{
+		IndividualThreshold: getEnvInt64("CTR_INDIVIDUAL_THRESHOLD", 500_000_000),
+		CorporateThreshold:  getEnvInt64("CTR_CORPORATE_THRESHOLD", 1_000_000_000),
+		Currency:            getEnv("CTR_CURRENCY", "NGN"),
+		Channels:            getEnvList("CTR_CHANNELS", []string{"cash", "transfer"}),
+		ReportingEntityID:   getEnv("CTR_REPORTING_ENTITY_ID", "0"),
+		Interval:            getEnvDuration("CTR_SCHEDULE_INTERVAL", time.Hour),
+	}
+}
+
+// LoadRiskConfig reads the risk scoring settings from the environment.
+// Factor weights can be overridden individually with RISK_WEIGHT_<FACTOR>.
+func LoadRiskConfig() RiskConfig {
+	defaultWeights := map[string]float64{
+		"business_category":     25,
+		"business_type":         5,
+		"state":                 10,
+		"incorporation_age":     10,
+		"screening":             25,
+		"alert_history":         15,
+		"transaction_behaviour": 10,
+	}
+	weights := make(map[string]float64, len(defaultWeights))
+	for factor, weight := range defaultWeights {
+		weights[factor] = getEnvFloat("RISK_WEIGHT_"+strings.ToUpper(factor), weight)
+	}
+
+	return RiskConfig{
+		Weights: weights,
+		HighRiskCategories: getEnvList("RISK_HIGH_CATEGORIES", []string{
+			"crypto", "gaming", "gambling", "betting", "money_transfer", "remittance", "forex", "precious_metals",
+		}),
+		MediumRiskCategories: getEnvList("RISK_MEDIUM_CATEGORIES", []string{
+			"real_estate", "travel", "charity", "pharmacy", "automotive",
+		}),
+		HighRiskStates:      getEnvList("RISK_HIGH_STATES", nil),
+		MediumTierThreshold: getEnvFloat("RISK_MEDIUM_TIER_THRESHOLD", 40),
+		HighTierThreshold:   getEnvFloat("RISK_HIGH_TIER_THRESHOLD", 70),
+		HighVolumeThreshold: getEnvInt64("RISK_HIGH_VOLUME_THRESHOLD", 10_000_000_000),
+		Currency:            getEnv("RISK_CURRENCY", getEnv("CTR_CURRENCY", "NGN")),
+		RescoreInterval:     getEnvDuration("RISK_RESCORE_INTERVAL", time.Hour),
+		MaxScoreAge:         getEnvDuration("RISK_MAX_SCORE_AGE", 24*time.Hour),
+	}
+}
+
+// LoadReviewConfig reads the periodic review settings from the environment
+func LoadReviewConfig() ReviewConfig {
+	return ReviewConfig{
+		HighRiskMonths:   int(getEnvInt64("REVIEW_HIGH_RISK_MONTHS", 12)),
+		MediumRiskMonths: int(getEnvInt64("REVIEW_MEDIUM_RISK_MONTHS", 24)),
+		LowRiskMonths:    int(getEnvInt64("REVIEW_LOW_RISK_MONTHS", 36)),
+		OpenBefore:       getEnvDuration("REVIEW_OPEN_BEFORE", 30*24*time.Hour),
+		EscalateAfter:    getEnvDuration("REVIEW_ESCALATE_AFTER", 0),
+		Interval:         getEnvDuration("REVIEW_SCHEDULE_INTERVAL", time.Hour),
+	}
+}
+
+// LoadEDDConfig reads the enhanced due diligence settings from the environment
+func LoadEDDConfig() EDDConfig {
+	return EDDConfig{
+		Categories: getEnvList("EDD_CATEGORIES", []string{
+			"crypto", "gaming", "gambling", "betting", "money_transfer", "remittance",
+		}),
+		Items:         getEnvList("EDD_ITEMS", []string{"source_of_funds", "beneficial_ownership"}),
+		ApproverRoles: getEnvList("EDD_APPROVER_ROLES", []string{"senior_reviewer", "mlro"}),
+	}
+}
+
+// LoadKYCConfig reads the KYC submission rules from the environment
+func LoadKYCConfig() KYCConfig {
+	return KYCConfig{
+		UBOThreshold: getEnvFloat("KYC_UBO_THRESHOLD", 5),
+		FourEyes:     getEnvBool("KYC_FOUR_EYES", false),
+		BusinessCategories: getEnvList("KYC_BUSINESS_CATEGORIES", []string{
+			"retail", "ecommerce", "food_and_beverage", "hospitality", "education", "healthcare",
+			"pharmacy", "logistics", "transport", "technology", "telecommunications",
+			"professional_services", "agriculture", "manufacturing", "construction", "real_estate",
+			"travel", "entertainment", "fashion", "automotive", "charity", "religious_organisation",
+			"financial_services", "money_transfer", "remittance", "forex", "crypto", "gaming",
+			"gambling", "betting", "precious_metals", "other",
+		}),
+		MerchantServiceURL: getEnv("MERCHANT_SERVICE_URL", "http://merchant-service:7002"),
+		StatusCacheTTL:     getEnvDuration("KYC_STATUS_CACHE_TTL", time.Minute),
+		StatusCacheTimeout: getEnvDuration("KYC_STATUS_CACHE_TIMEOUT", 100*time.Millisecond),
+	}
+}
+
+// LoadAuthConfig reads the JWT validation settings from the environment.
+// Requests are rejected when JWT_SECRET is not set.
+func LoadAuthConfig() AuthConfig {
+	return AuthConfig{
+		JWTSecret:   getEnv("JWT_SECRET", ""),
+		JWTIssuer:   getEnv("JWT_ISSUER", ""),
+		JWTAudience: getEnv("JWT_AUDIENCE", ""),
+	}
+}
+
+// LoadEncryptionConfig reads the field-level encryption settings from the environment
+func LoadEncryptionConfig() EncryptionConfig {
+	return EncryptionConfig{
+		KeyFile:          getEnv("ENCRYPTION_KEY_FILE", ""),
+		RotationInterval: getEnvDuration("ENCRYPTION_ROTATION_INTERVAL", time.Hour),
+		RotationBatch:    int(getEnvInt64("ENCRYPTION_ROTATION_BATCH", 100)),
+	}
+}
+
+// LoadRetentionConfig reads the data retention policy from the environment. The defaults follow the
+// five year record keeping requirement for KYC and transaction monitoring data.
+func LoadRetentionConfig() RetentionConfig {
+	return RetentionConfig{
+		KYCSubmissionMonths: int(getEnvInt64("RETENTION_KYC_SUBMISSION_MONTHS", 60)),
+		DocumentMonths:      int(getEnvInt64("RETENTION_DOCUMENT_MONTHS", 60)),
+		KYCRecordMonths:     int(getEnvInt64("RETENTION_KYC_RECORD_MONTHS", 60)),
+		AlertMonths:         int(getEnvInt64("RETENTION_ALERT_MONTHS", 60)),
+		PurgeInterval:       getEnvDuration("RETENTION_PURGE_INTERVAL", 24*time.Hour),
+		PurgeBatch:          int(getEnvInt64("RETENTION_PURGE_BATCH", 100)),
+	}
+}
+
+// LoadGraphConfig reads the entity graph settings from the environment
+func LoadGraphConfig() GraphConfig {
+	return GraphConfig{
+		SyncInterval: getEnvDuration("GRAPH_SYNC_INTERVAL", 15*time.Minute),
+		SyncBatch:    int(getEnvInt64("GRAPH_SYNC_BATCH", 200)),
+		DefaultDepth: int(getEnvInt64("GRAPH_DEFAULT_DEPTH", 2)),
+		MaxDepth:     int(getEnvInt64("GRAPH_MAX_DEPTH", 4)),
+		MaxNodes:     int(getEnvInt64("GRAPH_MAX_NODES", 500)),
+	}
+}
+
+// LoadAddressConfig reads the address normalisation settings from the environment
+func LoadAddressConfig() AddressConfig {
+	return AddressConfig{
+		Geocode:           getEnvBool("ADDRESS_GEOCODE", true),
+		GeocoderURL:       getEnv("ADDRESS_GEOCODER_URL", ""),
+		GeocoderUserAgent: getEnv("ADDRESS_GEOCODER_USER_AGENT", "kodra-compliance-service"),
+		GeocoderTimeout:   getEnvDuration("ADDRESS_GEOCODER_TIMEOUT", 5*time.Second),
+		VirtualOfficeMarkers: getEnvList("ADDRESS_VIRTUAL_OFFICE_MARKERS", []string{
+			"virtual office", "regus", "coworking", "co-working", "shared office",
+			"business centre", "business center", "mail drop", "mailbox", "c/o",
+		}),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value := lookup(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	if lookup(key) == "" {
+		return fallback
+	}
+	value, err := strconv.ParseInt(lookup(key), 10, 64)
 	if err != nil {
-		redisDB = 0 // Default Redis DB
+		invalidKeys = append(invalidKeys, key)
+		return fallback
 	}
+	return value
+}
 
-	return &Config{
-		ServicePort: port,
-		DatabaseURL: dbURL,
-		RedisAddr:   redisAddr,
-		RedisPassword: redisPassword,
-		RedisDB: redisDB,
+func getEnvFloat(key string, fallback float64) float64 {
+	if lookup(key) == "" {
+		return fallback
+	}
+	value, err := strconv.ParseFloat(lookup(key), 64)
+	if err != nil {
+		invalidKeys = append(invalidKeys, key)
+		return fallback
 	}
-}
\ No newline at end of file
+	return value
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if lookup(key) == "" {
+		return fallback
+	}
+	value, err := strconv.ParseBool(lookup(key))
+	if err != nil {
+		invalidKeys = append(invalidKeys, key)
+		return fallback
+	}
+	return value
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if lookup(key) == "" {
+		return fallback
+	}
+	value, err := time.ParseDuration(lookup(key))
+	if err != nil {
+		invalidKeys = append(invalidKeys, key)
+		return fallback
+	}
+	return value
+}
+
+func getEnvList(key string, fallback []string) []string {
+	value := lookup(key)
+	if value == "" {
+		return fallback
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	fileValues  map[string]string // settings read from the config file by Load
+	invalidKeys []string          // settings that were set but could not be parsed
+)
+
+// lookup returns a setting from the environment, falling back to the config file
+func lookup(key string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fileValues[key]
+}
+
+// readFile parses a config file of KEY=VALUE lines, the same keys as the environment. Blank lines
+// and lines starting with # are skipped and values may be quoted. An empty path reads nothing.
+func readFile(path string) (map[string]string, error) {
+	values := make(map[string]string)
+	if path == "" {
+		return values, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open config file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("config file %s line %d: expected KEY=VALUE", path, n)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+	return values, nil
+}
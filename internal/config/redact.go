@@ -0,0 +1,74 @@
+package config
+
+import (
+	"log/slog"
+	"net/url"
+	"reflect"
+	"regexp"
+	"time"
+)
+
+const redacted = "REDACTED"
+
+// dsnPassword matches the password of a key=value connection string
+var dsnPassword = regexp.MustCompile(`(password\s*=\s*)('[^']*'|\S+)`)
+
+// Redacted returns a copy of the configuration with passwords and secrets masked, safe to log
+func (c *Config) Redacted() Config {
+	out := *c
+	out.invalid = nil
+	out.DatabaseURL = redactURL(c.DatabaseURL)
+	out.Address.GeocoderURL = redactURL(c.Address.GeocoderURL)
+	if out.RedisPassword != "" {
+		out.RedisPassword = redacted
+	}
+	if out.Auth.JWTSecret != "" {
+		out.Auth.JWTSecret = redacted
+	}
+	return out
+}
+
+// LogValue logs the redacted configuration with durations as text, such as 30s. In nanoseconds
+// they would be hard to read and long enough for the PII scrubber to mask as phone numbers.
+func (c *Config) LogValue() slog.Value {
+	return slog.AnyValue(plain(reflect.ValueOf(c.Redacted())))
+}
+
+// plain converts structs to maps of their exported fields and durations to strings
+func plain(v reflect.Value) interface{} {
+	switch {
+	case v.Type() == reflect.TypeOf(time.Duration(0)):
+		return time.Duration(v.Int()).String()
+	case v.Kind() == reflect.Struct:
+		out := make(map[string]interface{}, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.IsExported() {
+				out[field.Name] = plain(v.Field(i))
+			}
+		}
+		return out
+	}
+	return v.Interface()
+}
+
+// redactURL masks the password of a URL or key=value connection string
+func redactURL(raw string) string {
+	if raw == "" {
+		return raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme == "" {
+		return dsnPassword.ReplaceAllString(raw, "${1}"+redacted)
+	}
+	if _, ok := parsed.User.Password(); ok {
+		parsed.User = url.UserPassword(parsed.User.Username(), redacted)
+	}
+	query := parsed.Query()
+	for _, key := range []string{"password", "key", "api_key", "token"} {
+		if query.Has(key) {
+			query.Set(key, redacted)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
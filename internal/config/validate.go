@@ -0,0 +1,132 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate checks the settings the service cannot start without and the ranges of the rest. It
+// reports every problem at once.
+func (c *Config) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	for _, key := range c.invalid {
+		problems = append(problems, fmt.Sprintf("%s has an invalid value", key))
+	}
+
+	switch c.Storage {
+	case StoragePostgres:
+		check(c.DatabaseURL != "", "DATABASE_URL is required")
+		check(c.Encryption.KeyFile != "", "ENCRYPTION_KEY_FILE is required")
+	case StorageMemory:
+		// A deployment switched to memory would silently stop persisting KYC data
+		check(c.DatabaseURL == "", "DATABASE_URL must not be set with STORAGE=%s, which does not use the database", StorageMemory)
+	default:
+		problems = append(problems, fmt.Sprintf("STORAGE must be %s or %s, got %q", StoragePostgres, StorageMemory, c.Storage))
+	}
+	if c.DatabaseURL != "" {
+		_, err := url.Parse(c.DatabaseURL)
+		check(err == nil, "DATABASE_URL is not a valid URL")
+	}
+	var level slog.Level
+	check(level.UnmarshalText([]byte(c.Log.Level)) == nil, "LOG_LEVEL must be debug, info, warn or error, got %q", c.Log.Level)
+	check(c.Log.Format == "json" || c.Log.Format == "text", "LOG_FORMAT must be json or text, got %q", c.Log.Format)
+	switch c.Tracing.Exporter {
+	case TracingNone, TracingStdout:
+	case TracingOTLP:
+		parsed, err := url.Parse(c.Tracing.OTLPEndpoint)
+		check(err == nil && parsed.Host != "", "OTEL_EXPORTER_OTLP_ENDPOINT is not a valid URL")
+	default:
+		problems = append(problems, fmt.Sprintf("TRACING_EXPORTER must be %s, %s or %s, got %q",
+			TracingNone, TracingStdout, TracingOTLP, c.Tracing.Exporter))
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "TRACING_SAMPLE_RATIO must be between 0 and 1")
+	port, err := strconv.Atoi(c.ServicePort)
+	check(err == nil && port > 0 && port < 65536, "PORT must be a port number, got %q", c.ServicePort)
+
+	check(c.DB.MaxOpenConns >= 0 && c.DB.MaxIdleConns >= 0, "DB_MAX_OPEN_CONNS and DB_MAX_IDLE_CONNS must not be negative")
+	check(c.DB.MaxOpenConns == 0 || c.DB.MaxIdleConns <= c.DB.MaxOpenConns, "DB_MAX_IDLE_CONNS must not exceed DB_MAX_OPEN_CONNS")
+
+	intervals := map[string]time.Duration{
+		"SHUTDOWN_TIMEOUT":             c.ShutdownTimeout,
+		"HEALTH_CHECK_TIMEOUT":         c.Health.CheckTimeout,
+		"IDEMPOTENCY_TTL":              c.Idempotency.TTL,
+		"IDEMPOTENCY_LOCK_TIMEOUT":     c.Idempotency.LockTimeout,
+		"IDEMPOTENCY_PURGE_INTERVAL":   c.Idempotency.PurgeInterval,
+		"KYC_STATUS_CACHE_TTL":         c.KYC.StatusCacheTTL,
+		"KYC_STATUS_CACHE_TIMEOUT":     c.KYC.StatusCacheTimeout,
+		"RATE_LIMIT_REDIS_TIMEOUT":     c.RateLimit.RedisTimeout,
+		"CTR_SCHEDULE_INTERVAL":        c.CTR.Interval,
+		"RISK_RESCORE_INTERVAL":        c.Risk.RescoreInterval,
+		"REVIEW_SCHEDULE_INTERVAL":     c.Review.Interval,
+		"ENCRYPTION_ROTATION_INTERVAL": c.Encryption.RotationInterval,
+		"RETENTION_PURGE_INTERVAL":     c.Retention.PurgeInterval,
+		"GRAPH_SYNC_INTERVAL":          c.Graph.SyncInterval,
+	}
+	for key, interval := range intervals {
+		check(interval > 0, "%s must be positive", key)
+	}
+
+	counts := map[string]int{
+		"HTTP_MAX_BODY_BYTES":             c.HTTP.MaxBodyBytes,
+		"KYC_SUBMIT_MAX_BODY_BYTES":       c.RateLimit.SubmitMaxBodyBytes,
+		"KYC_WRITE_MAX_BODY_BYTES":        c.RateLimit.WriteMaxBodyBytes,
+		"RATE_LIMIT_MERCHANT_BURST":       c.RateLimit.MerchantBurst,
+		"RATE_LIMIT_IP_BURST":             c.RateLimit.IPBurst,
+		"ENCRYPTION_ROTATION_BATCH":       c.Encryption.RotationBatch,
+		"RETENTION_PURGE_BATCH":           c.Retention.PurgeBatch,
+		"GRAPH_SYNC_BATCH":                c.Graph.SyncBatch,
+		"GRAPH_MAX_NODES":                 c.Graph.MaxNodes,
+		"REVIEW_HIGH_RISK_MONTHS":         c.Review.HighRiskMonths,
+		"REVIEW_MEDIUM_RISK_MONTHS":       c.Review.MediumRiskMonths,
+		"REVIEW_LOW_RISK_MONTHS":          c.Review.LowRiskMonths,
+		"RETENTION_KYC_SUBMISSION_MONTHS": c.Retention.KYCSubmissionMonths,
+		"RETENTION_DOCUMENT_MONTHS":       c.Retention.DocumentMonths,
+		"RETENTION_KYC_RECORD_MONTHS":     c.Retention.KYCRecordMonths,
+		"RETENTION_ALERT_MONTHS":          c.Retention.AlertMonths,
+	}
+	for key, count := range counts {
+		check(count > 0, "%s must be positive", key)
+	}
+
+	check(c.RateLimit.MerchantPerMinute > 0 && c.RateLimit.IPPerMinute > 0,
+		"RATE_LIMIT_MERCHANT_PER_MINUTE and RATE_LIMIT_IP_PER_MINUTE must be positive")
+	check(c.HTTP.ProxyHeader == "" || len(c.HTTP.TrustedProxies) > 0,
+		"HTTP_TRUSTED_PROXIES is required with HTTP_PROXY_HEADER")
+	for _, proxy := range c.HTTP.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(net.ParseIP(proxy) != nil || cidrErr == nil, "HTTP_TRUSTED_PROXIES has an invalid IP or CIDR range %q", proxy)
+	}
+	check(c.CTR.IndividualThreshold > 0 && c.CTR.CorporateThreshold > 0, "CTR thresholds must be positive")
+	check(c.Risk.MediumTierThreshold < c.Risk.HighTierThreshold,
+		"RISK_MEDIUM_TIER_THRESHOLD must be below RISK_HIGH_TIER_THRESHOLD")
+	check(c.KYC.UBOThreshold > 0 && c.KYC.UBOThreshold <= 100, "KYC_UBO_THRESHOLD must be a percentage")
+	check(len(c.KYC.BusinessCategories) > 0, "KYC_BUSINESS_CATEGORIES must not be empty")
+	check(c.Graph.DefaultDepth > 0 && c.Graph.DefaultDepth <= c.Graph.MaxDepth,
+		"GRAPH_DEFAULT_DEPTH must be between 1 and GRAPH_MAX_DEPTH")
+	parsed, err := url.Parse(c.KYC.MerchantServiceURL)
+	check(err == nil && parsed.Host != "", "MERCHANT_SERVICE_URL is not a valid URL")
+	if c.Address.GeocoderURL != "" {
+		parsed, err := url.Parse(c.Address.GeocoderURL)
+		check(err == nil && parsed.Host != "", "ADDRESS_GEOCODER_URL is not a valid URL")
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	// map iteration order is random, keep the report stable
+	sort.Strings(problems)
+	return errors.New("invalid configuration: " + strings.Join(problems, "; "))
+}
@@ -0,0 +1,35 @@
+package dto
+
+// MonitoredTransactionRequest represents a transaction pushed into the monitoring store
+type MonitoredTransactionRequest struct {
+	Reference           string `json:"reference"`
+	CustomerID          int    `json:"customer_id"`
+	CustomerType        string `json:"customer_type"` // "individual" or "corporate"
+	CustomerName        string `json:"customer_name,omitempty"`
+	Channel             string `json:"channel"`   // "cash" or "transfer"
+	Direction           string `json:"direction"` // "credit" or "debit"
+	Amount              int64  `json:"amount"`    // minor units (kobo)
+	Currency            string `json:"currency,omitempty"`
+	CounterpartyName    string `json:"counterparty_name,omitempty"`
+	CounterpartyAccount string `json:"counterparty_account,omitempty"`
+	OccurredAt          string `json:"occurred_at"` // RFC 3339 format
+}
+
+// CTRGenerateRequest represents a request to generate a CTR batch on demand
+type CTRGenerateRequest struct {
+	ReportDate string `json:"report_date"` // YYYY-MM-DD
+}
+
+// CTRBatchResponse represents the generation metadata of a CTR batch
+type CTRBatchResponse struct {
+	ID                  int    `json:"id"`
+	ReportDate          string `json:"report_date"`
+	Status              string `json:"status"`
+	Currency            string `json:"currency"`
+	IndividualThreshold int64  `json:"individual_threshold"`
+	CorporateThreshold  int64  `json:"corporate_threshold"`
+	RecordCount         int    `json:"record_count"`
+	TotalAmount         int64  `json:"total_amount"`
+	GeneratedBy         string `json:"generated_by"`
+	GeneratedAt         string `json:"generated_at"`
+}
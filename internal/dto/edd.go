@@ -0,0 +1,17 @@
+package dto
+
+// EDDItemCompleteRequest represents a reviewer completing an EDD checklist item.
+// CompletedBy is taken from the caller's token.
+type EDDItemCompleteRequest struct {
+	CompletedBy int    `json:"-"`
+	Evidence    string `json:"evidence"` // document reference or URL
+	Notes       string `json:"notes,omitempty"`
+}
+
+// EDDSignOffRequest represents the senior management sign-off of an EDD checklist.
+// ApproverID and ApproverRole are taken from the caller's token.
+type EDDSignOffRequest struct {
+	ApproverID   int    `json:"-"`
+	ApproverRole string `json:"-"`
+	Notes        string `json:"notes,omitempty"`
+}
@@ -2,37 +2,60 @@ package dto
 
 // KYCSubmissionRequest represents the request to submit KYC
 type KYCSubmissionRequest struct {
-	MerchantID        int               `json:"merchant_id"`
-	BusinessType      string            `json:"business_type"` // "registered" or "startup"
-	BusinessName      string            `json:"business_name"`
-	CACNumber         string            `json:"cac_number,omitempty"`
-	TINNumber         string            `json:"tin_number,omitempty"`
-	BusinessAddress   string            `json:"business_address"`
-	City              string            `json:"city"`
-	State             string            `json:"state"`
-	PostalCode        string            `json:"postal_code,omitempty"`
-	IncorporationDate string            `json:"incorporation_date,omitempty"` // ISO 8601 format
-	BusinessCategory  string            `json:"business_category"`
-	DirectorName      string            `json:"director_name"`
-	DirectorBVN       string            `json:"director_bvn"`
-	DirectorPhone     string            `json:"director_phone"`
-	DirectorEmail     string            `json:"director_email"`
-	Documents         map[string]string `json:"documents"` // document_type -> file_path/url
+	MerchantID        int                `json:"merchant_id" validate:"required"`
+	BusinessType      string             `json:"business_type" validate:"oneof=registered startup"`
+	BusinessName      string             `json:"business_name" validate:"required,max=255"`
+	CACNumber         string             `json:"cac_number,omitempty" validate:"cac"`
+	TINNumber         string             `json:"tin_number,omitempty"`
+	BusinessAddress   string             `json:"business_address" validate:"max=500"`
+	City              string             `json:"city" validate:"max=100"`
+	State             string             `json:"state" validate:"state"`
+	PostalCode        string             `json:"postal_code,omitempty" validate:"max=20"`
+	IncorporationDate string             `json:"incorporation_date,omitempty" validate:"past"` // YYYY-MM-DD
+	BusinessCategory  string             `json:"business_category" validate:"enum=business_category"`
+	DirectorName      string             `json:"director_name" validate:"max=255"`
+	DirectorBVN       string             `json:"director_bvn" validate:"bvn"`
+	DirectorPhone     string             `json:"director_phone" validate:"phone"`
+	DirectorEmail     string             `json:"director_email" validate:"email"`
+	Documents         map[string]string  `json:"documents"` // document_type -> file_path/url
+	Directors         []KYCPersonRequest `json:"directors,omitempty"`
+	Shareholders      []KYCPersonRequest `json:"shareholders,omitempty"`
+	BeneficialOwners  []KYCPersonRequest `json:"beneficial_owners,omitempty"`
+}
+
+// KYCPersonRequest represents a director, shareholder or beneficial owner on a KYC submission.
+// The legacy single-director fields on KYCSubmissionRequest are still accepted.
+type KYCPersonRequest struct {
+	FullName            string   `json:"full_name" validate:"required,max=255"`
+	BVN                 string   `json:"bvn,omitempty" validate:"bvn"`
+	Phone               string   `json:"phone,omitempty" validate:"phone"`
+	Email               string   `json:"email,omitempty" validate:"email"`
+	DateOfBirth         string   `json:"date_of_birth,omitempty" validate:"past"` // YYYY-MM-DD
+	Nationality         string   `json:"nationality,omitempty" validate:"max=100"`
+	OwnershipPercentage *float64 `json:"ownership_percentage,omitempty" validate:"min=0,max=100"`
+}
+
+// KYCPersonIdentityRequest represents the outcome of verifying a person's identity
+type KYCPersonIdentityRequest struct {
+	Status    string `json:"status"` // "verified" or "failed"
+	Reference string `json:"reference,omitempty"`
 }
 
 // KYCSubmissionResponse represents the response after KYC submission
 type KYCSubmissionResponse struct {
-	SubmissionID int `json:"submission_id"`
+	SubmissionID int    `json:"submission_id"`
 	Status       string `json:"status"`
 	Message      string `json:"message"`
+	EDDRequired  bool   `json:"edd_required,omitempty"`
 }
 
-// KYCStatusUpdateRequest represents a request to update KYC status (admin only)
+// KYCStatusUpdateRequest represents a request to update KYC status (admin only).
+// ReviewerID is taken from the caller's token, never from the request body.
 type KYCStatusUpdateRequest struct {
-	MerchantID  int    `json:"merchant_id"`
-	Status      string `json:"status"` // "approved", "rejected", or "pending"
-	ReviewerID  int    `json:"reviewer_id"`
-	ReviewNotes string `json:"review_notes,omitempty"`
+	MerchantID  int    `json:"merchant_id" validate:"required"`
+	Status      string `json:"status" validate:"required,oneof=approved rejected pending"`
+	ReviewerID  int    `json:"-"`
+	ReviewNotes string `json:"review_notes,omitempty" validate:"max=2000"`
 }
 
 // KYCStatusResponse represents the KYC status for a merchant
@@ -50,3 +73,28 @@ type KYCListResponse struct {
 	Submissions []KYCStatusResponse `json:"submissions"`
 	Total       int                 `json:"total"`
 }
+
+// KYCDecisionCheckRequest represents a second reviewer confirming or overturning a proposed decision.
+// CheckerID is taken from the caller's token.
+type KYCDecisionCheckRequest struct {
+	Status      string `json:"status,omitempty"` // required when overturning
+	ReviewNotes string `json:"review_notes,omitempty"`
+	CheckerID   int    `json:"-"`
+}
+
+// RelatedMerchantsResponse lists the submissions of other merchants that share identifiers
+// with a submission
+type RelatedMerchantsResponse struct {
+	SubmissionID     int               `json:"submission_id"`
+	FlaggedForReview bool              `json:"flagged_for_review"`
+	FlagReason       string            `json:"flag_reason,omitempty"`
+	Related          []RelatedMerchant `json:"related"`
+	Total            int               `json:"total"`
+}
+
+// RelatedMerchant is a submission of another merchant and the identifiers it shares
+type RelatedMerchant struct {
+	MerchantID   int      `json:"merchant_id"`
+	SubmissionID int      `json:"submission_id"`
+	LinkTypes    []string `json:"link_types"` // "bvn", "cac", "tin", "address", "phone", "email"
+}
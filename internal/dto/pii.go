@@ -0,0 +1,33 @@
+package dto
+
+// PIIUnmaskRequest asks to reveal the masked personal data of a KYC submission.
+// The actor fields are taken from the caller's token and request.
+type PIIUnmaskRequest struct {
+	Reason    string `json:"reason"`
+	ActorID   int    `json:"-"`
+	ActorRole string `json:"-"`
+	IP        string `json:"-"`
+	RequestID string `json:"-"`
+}
+
+// PIIUnmaskResponse carries the unmasked personal data of a KYC submission
+type PIIUnmaskResponse struct {
+	AccessID      int             `json:"access_id"`
+	SubmissionID  int             `json:"submission_id"`
+	MerchantID    int             `json:"merchant_id"`
+	TINNumber     string          `json:"tin_number,omitempty"`
+	DirectorBVN   string          `json:"director_bvn,omitempty"`
+	DirectorPhone string          `json:"director_phone,omitempty"`
+	DirectorEmail string          `json:"director_email,omitempty"`
+	Persons       []PIIPersonData `json:"persons"`
+}
+
+// PIIPersonData is the unmasked personal data of a declared person
+type PIIPersonData struct {
+	PersonID int    `json:"person_id"`
+	Role     string `json:"role"`
+	FullName string `json:"full_name"`
+	BVN      string `json:"bvn,omitempty"`
+	Phone    string `json:"phone,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
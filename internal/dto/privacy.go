@@ -0,0 +1,26 @@
+package dto
+
+import "github.com/kodra-pay/compliance-service/internal/models"
+
+// ErasureRequestCreate represents a right-to-erasure request for a data subject.
+// RequestedBy is taken from the caller's token.
+type ErasureRequestCreate struct {
+	SubjectType string `json:"subject_type"` // "merchant" or "user"
+	SubjectID   int    `json:"subject_id"`
+	Reason      string `json:"reason,omitempty"`
+	RequestedBy int    `json:"-"`
+}
+
+// ErasureRequestListResponse represents a list of erasure requests
+type ErasureRequestListResponse struct {
+	Requests []models.ErasureRequest `json:"requests"`
+	Total    int                     `json:"total"`
+}
+
+// LegalHoldRequest places a legal hold on a data subject. PlacedBy is taken from the caller's token.
+type LegalHoldRequest struct {
+	SubjectType string `json:"subject_type"` // "merchant" or "user"
+	SubjectID   int    `json:"subject_id"`
+	Reason      string `json:"reason"`
+	PlacedBy    int    `json:"-"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+import "github.com/kodra-pay/compliance-service/internal/models"
+
+// KYCReviewCompleteRequest represents a reviewer closing a periodic KYC review.
+// ReviewerID is taken from the caller's token.
+type KYCReviewCompleteRequest struct {
+	ReviewerID  int    `json:"-"`
+	ReviewNotes string `json:"review_notes,omitempty"`
+}
+
+// KYCReviewListResponse represents a list of periodic KYC reviews
+type KYCReviewListResponse struct {
+	Reviews []models.KYCReview `json:"reviews"`
+	Total   int                `json:"total"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+import "github.com/kodra-pay/compliance-service/internal/models"
+
+// ScreeningResultRequest represents a screening outcome reported by the screening provider
+type ScreeningResultRequest struct {
+	MerchantID  int     `json:"merchant_id"`
+	PersonID    int     `json:"person_id,omitempty"` // declared KYC person that was screened
+	SubjectName string  `json:"subject_name"`
+	SubjectType string  `json:"subject_type"` // "business", "director", ...
+	ListType    string  `json:"list_type"`    // "sanctions", "pep", "adverse_media"
+	ListName    string  `json:"list_name,omitempty"`
+	MatchScore  float64 `json:"match_score"`
+	Status      string  `json:"status"` // "clear", "potential_match", "confirmed_match", "false_positive"
+}
+
+// MerchantRiskResponse represents a merchant's current risk score and its history
+type MerchantRiskResponse struct {
+	MerchantID int                `json:"merchant_id"`
+	Current    *models.RiskScore  `json:"current"`
+	History    []models.RiskScore `json:"history"`
+}
@@ -0,0 +1,227 @@
+package encryption_test
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kodra-pay/compliance-service/internal/encryption"
+)
+
+func newKey(t *testing.T) string {
+	t.Helper()
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// writeKeyfile writes a keyfile with the given key versions and returns its path
+func writeKeyfile(t *testing.T, current int, keys map[string]string, indexKey string) string {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]interface{}{"current_version": current, "keys": keys, "index_key": indexKey})
+	if err != nil {
+		t.Fatalf("encode keyfile: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write keyfile: %v", err)
+	}
+	return path
+}
+
+func newEncryptor(t *testing.T, current int, keys map[string]string, indexKey string) *encryption.FieldEncryptor {
+	t.Helper()
+
+	provider, err := encryption.NewLocalKeyProvider(writeKeyfile(t, current, keys, indexKey))
+	if err != nil {
+		t.Fatalf("load keyfile: %v", err)
+	}
+	return encryption.NewFieldEncryptor(provider)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	enc := newEncryptor(t, 1, map[string]string{"1": newKey(t)}, newKey(t))
+
+	for _, plaintext := range []string{"22123456789", "+2348012345678", "ada.obi@example.com", "ñ é 日本"} {
+		sealed, err := enc.Encrypt(ctx, plaintext)
+		if err != nil {
+			t.Fatalf("encrypt %q: %v", plaintext, err)
+		}
+		if !encryption.IsEncrypted(sealed) || !strings.HasPrefix(sealed, "enc:v1:1:") || strings.Contains(sealed, plaintext) {
+			t.Errorf("encrypt %q returned %q, want a version 1 envelope without the plaintext", plaintext, sealed)
+		}
+		again, err := enc.Encrypt(ctx, plaintext)
+		if err == nil && again == sealed {
+			t.Errorf("encrypting %q twice gave the same envelope", plaintext)
+		}
+
+		opened, err := enc.Decrypt(ctx, sealed)
+		if err != nil || opened != plaintext {
+			t.Errorf("decrypt of %q = %q, %v, want the plaintext", plaintext, opened, err)
+		}
+	}
+
+	// Empty values stay empty and values written before encryption pass through
+	if sealed, err := enc.Encrypt(ctx, ""); sealed != "" || err != nil {
+		t.Errorf("encrypt of an empty value = %q, %v, want empty", sealed, err)
+	}
+	if opened, err := enc.Decrypt(ctx, "22123456789"); opened != "22123456789" || err != nil {
+		t.Errorf("decrypt of a plain value = %q, %v, want it unchanged", opened, err)
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	ctx := context.Background()
+	enc := newEncryptor(t, 1, map[string]string{"1": newKey(t)}, newKey(t))
+	sealed, err := enc.Encrypt(ctx, "22123456789")
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	parts := strings.Split(sealed, ":")
+	ciphertext, _ := base64.RawStdEncoding.DecodeString(parts[4])
+	ciphertext[len(ciphertext)-1] ^= 0xff
+	tampered := strings.Join(append(parts[:4:4], base64.RawStdEncoding.EncodeToString(ciphertext)), ":")
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"missing parts", "enc:v1:1:abc"},
+		{"bad version", "enc:v1:one:" + parts[3] + ":" + parts[4]},
+		{"unknown version", "enc:v1:9:" + parts[3] + ":" + parts[4]},
+		{"bad data key encoding", "enc:v1:1:!!!:" + parts[4]},
+		{"bad ciphertext encoding", "enc:v1:1:" + parts[3] + ":!!!"},
+		{"tampered ciphertext", tampered},
+		{"wrapped key swapped", "enc:v1:1:" + parts[4] + ":" + parts[3]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if opened, err := enc.Decrypt(ctx, tt.value); err == nil {
+				t.Errorf("decrypt of %q = %q, want an error", tt.value, opened)
+			}
+		})
+	}
+}
+
+func TestRotation(t *testing.T) {
+	ctx := context.Background()
+	v1, v2, indexKey := newKey(t), newKey(t), newKey(t)
+
+	old := newEncryptor(t, 1, map[string]string{"1": v1}, indexKey)
+	sealed, err := old.Encrypt(ctx, "22123456789")
+	if err != nil {
+		t.Fatalf("encrypt under version 1: %v", err)
+	}
+
+	// After rotation the old version stays readable until values are re-encrypted
+	rotated := newEncryptor(t, 2, map[string]string{"1": v1, "2": v2}, indexKey)
+	if rotated.CurrentVersion() != 2 {
+		t.Fatalf("current version = %d, want 2", rotated.CurrentVersion())
+	}
+	opened, err := rotated.Decrypt(ctx, sealed)
+	if err != nil || opened != "22123456789" {
+		t.Fatalf("decrypt of a version 1 value after rotation = %q, %v", opened, err)
+	}
+	resealed, err := rotated.Encrypt(ctx, opened)
+	if err != nil || !strings.HasPrefix(resealed, "enc:v1:2:") {
+		t.Fatalf("re-encrypt = %q, %v, want a version 2 envelope", resealed, err)
+	}
+
+	// Once version 1 is retired only re-encrypted values can be read
+	retired := newEncryptor(t, 2, map[string]string{"2": v2}, indexKey)
+	if _, err := retired.Decrypt(ctx, sealed); err == nil {
+		t.Errorf("decrypt of a version 1 value succeeded without its key")
+	}
+	if opened, err := retired.Decrypt(ctx, resealed); err != nil || opened != "22123456789" {
+		t.Errorf("decrypt of the re-encrypted value = %q, %v", opened, err)
+	}
+
+	// Blind indexes do not depend on the key version
+	before, _ := old.BlindIndex(ctx, encryption.IndexBVN, "22123456789")
+	after, _ := retired.BlindIndex(ctx, encryption.IndexBVN, "22123456789")
+	if before == "" || before != after {
+		t.Errorf("blind index changed with rotation: %q, %q", before, after)
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	ctx := context.Background()
+	enc := newEncryptor(t, 1, map[string]string{"1": newKey(t)}, newKey(t))
+	index := func(kind encryption.IndexKind, value string) string {
+		t.Helper()
+		idx, err := enc.BlindIndex(ctx, kind, value)
+		if err != nil {
+			t.Fatalf("blind index of %q: %v", value, err)
+		}
+		return idx
+	}
+
+	same := []struct {
+		kind encryption.IndexKind
+		a, b string
+	}{
+		{encryption.IndexBVN, "22123456789", " 221-2345-6789 "},
+		{encryption.IndexPhone, "08012345678", "+234 801 234 5678"},
+		{encryption.IndexEmail, "Ada.Obi@Example.com ", "ada.obi@example.com"},
+		{encryption.IndexTIN, "12345678-0001", "12345678 - 0001"},
+	}
+	for _, tt := range same {
+		if a, b := index(tt.kind, tt.a), index(tt.kind, tt.b); a != b {
+			t.Errorf("%s indexes of %q and %q differ", tt.kind, tt.a, tt.b)
+		}
+	}
+
+	if index(encryption.IndexBVN, "22123456789") == index(encryption.IndexBVN, "22123456780") {
+		t.Errorf("different BVNs share an index")
+	}
+	if index(encryption.IndexBVN, "2348012345678") == index(encryption.IndexPhone, "2348012345678") {
+		t.Errorf("equal values of different kinds share an index")
+	}
+	if idx := index(encryption.IndexPhone, " - "); idx != "" {
+		t.Errorf("a value without digits has phone index %q, want none", idx)
+	}
+
+	other := newEncryptor(t, 1, map[string]string{"1": newKey(t)}, newKey(t))
+	if idx, _ := other.BlindIndex(ctx, encryption.IndexBVN, "22123456789"); idx == index(encryption.IndexBVN, "22123456789") {
+		t.Errorf("indexes under different index keys are equal")
+	}
+}
+
+func TestNewLocalKeyProviderErrors(t *testing.T) {
+	key := newKey(t)
+	short := base64.StdEncoding.EncodeToString([]byte("too short"))
+
+	tests := []struct {
+		name     string
+		current  int
+		keys     map[string]string
+		indexKey string
+	}{
+		{"missing current version", 2, map[string]string{"1": key}, key},
+		{"invalid version", 1, map[string]string{"1": key, "v2": key}, key},
+		{"short key", 1, map[string]string{"1": short}, key},
+		{"key not base64", 1, map[string]string{"1": "not base64!"}, key},
+		{"missing index key", 1, map[string]string{"1": key}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := encryption.NewLocalKeyProvider(writeKeyfile(t, tt.current, tt.keys, tt.indexKey)); err == nil {
+				t.Errorf("loading the keyfile succeeded, want an error")
+			}
+		})
+	}
+
+	if _, err := encryption.NewLocalKeyProvider(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Errorf("loading a missing keyfile succeeded, want an error")
+	}
+}
@@ -0,0 +1,166 @@
+package encryption
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// envelopePrefix marks an encrypted value: enc:v1:<kek version>:<wrapped data key>:<ciphertext>
+const envelopePrefix = "enc:v1:"
+
+// FieldEncryptor encrypts individual column values with envelope encryption. Every value gets
+// a fresh data key which is wrapped by the provider's current key-encryption key.
+type FieldEncryptor struct {
+	keys KeyProvider
+}
+
+func NewFieldEncryptor(keys KeyProvider) *FieldEncryptor {
+	return &FieldEncryptor{keys: keys}
+}
+
+// CurrentVersion returns the key version new values are encrypted under
+func (e *FieldEncryptor) CurrentVersion() int {
+	return e.keys.CurrentVersion()
+}
+
+// Encrypt returns the envelope for plaintext. Empty values stay empty.
+func (e *FieldEncryptor) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	version := e.keys.CurrentVersion()
+	wrapped, err := e.keys.WrapKey(ctx, version, dataKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(aead, []byte(plaintext))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt value: %w", err)
+	}
+
+	return envelopePrefix + strconv.Itoa(version) + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Decrypt opens an envelope. Values written before encryption was enabled are returned as-is.
+func (e *FieldEncryptor) Decrypt(ctx context.Context, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(value, envelopePrefix), ":", 3)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+	version, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "", fmt.Errorf("malformed key version: %w", err)
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed data key: %w", err)
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("malformed ciphertext: %w", err)
+	}
+
+	dataKey, err := e.keys.UnwrapKey(ctx, version, wrapped)
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(aead, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// BlindIndex returns a keyed hash of the normalised value so exact-match lookups work without
+// decrypting. Empty values have no index.
+func (e *FieldEncryptor) BlindIndex(ctx context.Context, kind IndexKind, value string) (string, error) {
+	normalized := kind.Normalize(value)
+	if normalized == "" {
+		return "", nil
+	}
+
+	key, err := e.keys.IndexKey(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get index key: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(string(kind) + ":" + normalized))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// IsEncrypted reports whether value is an envelope produced by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix)
+}
+
+// IndexKind selects how a value is normalised before it is blind-indexed. The kind is also mixed
+// into the hash so equal values of different kinds do not share an index.
+type IndexKind string
+
+const (
+	IndexBVN   IndexKind = "bvn"
+	IndexPhone IndexKind = "phone"
+	IndexEmail IndexKind = "email"
+	IndexTIN   IndexKind = "tin"
+)
+
+// Normalize reduces a value to the form that is blind-indexed, so equal indexes mean equal
+// normalised values
+func (k IndexKind) Normalize(value string) string {
+	switch k {
+	case IndexBVN:
+		return digitsOnly(value)
+	case IndexPhone:
+		// Local Nigerian numbers (080...) and international numbers (+23480...) index the same
+		digits := digitsOnly(value)
+		if len(digits) == 11 && strings.HasPrefix(digits, "0") {
+			digits = "234" + digits[1:]
+		}
+		return digits
+	case IndexEmail:
+		return strings.ToLower(strings.TrimSpace(value))
+	default:
+		return strings.ToUpper(strings.Join(strings.Fields(value), ""))
+	}
+}
+
+func digitsOnly(value string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, value)
+}
@@ -0,0 +1,146 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// KeyProvider manages the key-encryption keys (KEKs) that wrap per-value data keys.
+// The interface mirrors a KMS so a managed key service can replace the local keyfile.
+type KeyProvider interface {
+	// CurrentVersion returns the KEK version used to wrap new data keys
+	CurrentVersion() int
+	// WrapKey encrypts a data key under the KEK of the given version
+	WrapKey(ctx context.Context, version int, dataKey []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key that was wrapped under the KEK of the given version
+	UnwrapKey(ctx context.Context, version int, wrapped []byte) ([]byte, error)
+	// IndexKey returns the HMAC key used to compute blind indexes
+	IndexKey(ctx context.Context) ([]byte, error)
+}
+
+// keyFile is the on-disk format of the local keyfile. Keys are base64-encoded 32-byte values.
+//
+//	{"current_version": 2, "keys": {"1": "...", "2": "..."}, "index_key": "..."}
+type keyFile struct {
+	CurrentVersion int               `json:"current_version"`
+	Keys           map[string]string `json:"keys"`
+	IndexKey       string            `json:"index_key"`
+}
+
+// LocalKeyProvider wraps data keys with AES-256-GCM KEKs loaded from a local keyfile
+type LocalKeyProvider struct {
+	current  int
+	keks     map[int]cipher.AEAD
+	indexKey []byte
+}
+
+// NewLocalKeyProvider loads the KEKs from a keyfile. Retired key versions must stay in the file
+// until the re-encryption job has moved all data to the current version.
+func NewLocalKeyProvider(path string) (*LocalKeyProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyfile: %w", err)
+	}
+
+	var kf keyFile
+	if err := json.Unmarshal(data, &kf); err != nil {
+		return nil, fmt.Errorf("failed to parse keyfile: %w", err)
+	}
+
+	provider := &LocalKeyProvider{current: kf.CurrentVersion, keks: make(map[int]cipher.AEAD)}
+	for versionStr, encoded := range kf.Keys {
+		version, err := strconv.Atoi(versionStr)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid key version %q", versionStr)
+		}
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key version %d: %w", version, err)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		provider.keks[version] = aead
+	}
+
+	if _, ok := provider.keks[provider.current]; !ok {
+		return nil, fmt.Errorf("keyfile does not contain current key version %d", provider.current)
+	}
+
+	if provider.indexKey, err = decodeKey(kf.IndexKey); err != nil {
+		return nil, fmt.Errorf("invalid index key: %w", err)
+	}
+
+	return provider, nil
+}
+
+func (p *LocalKeyProvider) CurrentVersion() int {
+	return p.current
+}
+
+func (p *LocalKeyProvider) WrapKey(_ context.Context, version int, dataKey []byte) ([]byte, error) {
+	aead, ok := p.keks[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown key version %d", version)
+	}
+	return seal(aead, dataKey)
+}
+
+func (p *LocalKeyProvider) UnwrapKey(_ context.Context, version int, wrapped []byte) ([]byte, error) {
+	aead, ok := p.keks[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown key version %d", version)
+	}
+	return open(aead, wrapped)
+}
+
+func (p *LocalKeyProvider) IndexKey(_ context.Context) ([]byte, error) {
+	return p.indexKey, nil
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, errors.New("key must be 32 bytes")
+	}
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal encrypts plaintext and prefixes the random nonce
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts a nonce-prefixed ciphertext produced by seal
+func open(aead cipher.AEAD, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, body := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, body, nil)
+}
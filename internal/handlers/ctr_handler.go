@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/middleware"
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/kodra-pay/compliance-service/internal/services"
+)
+
+type CTRHandler struct {
+	service *services.CTRService
+}
+
+func NewCTRHandler(service *services.CTRService) *CTRHandler {
+	return &CTRHandler{service: service}
+}
+
+// RecordTransaction ingests a transaction into the monitoring store
+func (h *CTRHandler) RecordTransaction(c *fiber.Ctx) error {
+	var req dto.MonitoredTransactionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	txn, err := h.service.RecordTransaction(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(txn)
+}
+
+// GenerateCTR generates a CTR batch for the requested day
+func (h *CTRHandler) GenerateCTR(c *fiber.Ctx) error {
+	var req dto.CTRGenerateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	reportDate, err := time.Parse("2006-01-02", req.ReportDate)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "report_date must be in YYYY-MM-DD format")
+	}
+
+	generatedBy := "api"
+	if principal := middleware.PrincipalFrom(c); principal != nil {
+		generatedBy = fmt.Sprintf("user:%d", principal.UserID)
+	}
+
+	batch, err := h.service.Generate(c.UserContext(), reportDate, generatedBy)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(ctrBatchResponse(batch))
+}
+
+// GetCTR downloads a CTR batch as goAML XML (default) or CSV, or returns its metadata as JSON
+func (h *CTRHandler) GetCTR(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid CTR batch ID")
+	}
+
+	batch, err := h.service.Get(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+	if batch == nil {
+		return fiber.NewError(fiber.StatusNotFound, "CTR batch not found")
+	}
+
+	filename := fmt.Sprintf("ctr-%s-%d", batch.ReportDate.Format("20060102"), batch.ID)
+
+	switch c.Query("format", "xml") {
+	case "xml":
+		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationXMLCharsetUTF8)
+		c.Attachment(filename + ".xml")
+		return c.SendString(batch.GoAMLXML)
+	case "csv":
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		c.Attachment(filename + ".csv")
+		return c.SendString(batch.CSVPayload)
+	case "json":
+		return c.JSON(fiber.Map{
+			"batch":   ctrBatchResponse(batch),
+			"entries": batch.Entries,
+		})
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "format must be 'xml', 'csv' or 'json'")
+	}
+}
+
+func ctrBatchResponse(batch *models.CTRBatch) dto.CTRBatchResponse {
+	return dto.CTRBatchResponse{
+		ID:                  batch.ID,
+		ReportDate:          batch.ReportDate.Format("2006-01-02"),
+		Status:              batch.Status,
+		Currency:            batch.Currency,
+		IndividualThreshold: batch.IndividualThreshold,
+		CorporateThreshold:  batch.CorporateThreshold,
+		RecordCount:         batch.RecordCount,
+		TotalAmount:         batch.TotalAmount,
+		GeneratedBy:         batch.GeneratedBy,
+		GeneratedAt:         batch.GeneratedAt.Format(time.RFC3339),
+	}
+}
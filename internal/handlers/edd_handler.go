@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/middleware"
+	"github.com/kodra-pay/compliance-service/internal/services"
+)
+
+type EDDHandler struct {
+	service *services.EDDService
+}
+
+func NewEDDHandler(service *services.EDDService) *EDDHandler {
+	return &EDDHandler{service: service}
+}
+
+// GetChecklist returns the enhanced due diligence checklist of a submission
+func (h *EDDHandler) GetChecklist(c *fiber.Ctx) error {
+	submissionID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid submission ID")
+	}
+
+	checklist, err := h.service.Get(c.UserContext(), submissionID)
+	if err != nil {
+		return err
+	}
+	if checklist == nil {
+		return fiber.NewError(fiber.StatusNotFound, "no EDD checklist found for submission")
+	}
+
+	return c.JSON(checklist)
+}
+
+// CompleteItem records the evidence for an EDD checklist item
+func (h *EDDHandler) CompleteItem(c *fiber.Ctx) error {
+	submissionID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid submission ID")
+	}
+
+	var req dto.EDDItemCompleteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	principal := middleware.PrincipalFrom(c)
+	if principal == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+	req.CompletedBy = principal.UserID
+
+	checklist, err := h.service.CompleteItem(c.UserContext(), submissionID, c.Params("item"), req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(checklist)
+}
+
+// SignOff records the senior management sign-off of an EDD checklist
+func (h *EDDHandler) SignOff(c *fiber.Ctx) error {
+	submissionID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid submission ID")
+	}
+
+	var req dto.EDDSignOffRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	principal := middleware.PrincipalFrom(c)
+	if principal == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+	req.ApproverID = principal.UserID
+	req.ApproverRole = principal.Role
+
+	checklist, err := h.service.SignOff(c.UserContext(), submissionID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(checklist)
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/kodra-pay/compliance-service/internal/middleware"
+	"github.com/kodra-pay/compliance-service/internal/services"
+	"github.com/kodra-pay/compliance-service/internal/validation"
+)
+
+// MIMEProblemJSON is the media type of RFC 7807 problem details
+const MIMEProblemJSON = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body. RequestID and Errors are extension members.
+type Problem struct {
+	Type      string                  `json:"type"`
+	Title     string                  `json:"title"`
+	Status    int                     `json:"status"`
+	Detail    string                  `json:"detail,omitempty"`
+	Instance  string                  `json:"instance,omitempty"`
+	Code      string                  `json:"code,omitempty"`
+	RequestID string                  `json:"request_id,omitempty"`
+	Errors    []validation.FieldError `json:"errors,omitempty"`
+}
+
+// kindStatus maps domain error kinds to HTTP status codes
+var kindStatus = map[services.ErrorKind]int{
+	services.KindNotFound:   fiber.StatusNotFound,
+	services.KindConflict:   fiber.StatusConflict,
+	services.KindValidation: fiber.StatusUnprocessableEntity,
+	services.KindForbidden:  fiber.StatusForbidden,
+	services.KindUpstream:   fiber.StatusBadGateway,
+}
+
+// ErrorHandler renders every error returned by a handler or middleware as problem+json. Domain
+// errors are mapped by kind; any other error is an internal failure, logged with the request ID
+// and reported without its details.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	problem := Problem{
+		Type:      "about:blank",
+		Instance:  c.OriginalURL(),
+		RequestID: middleware.RequestIDFrom(c),
+	}
+
+	var fiberErr *fiber.Error
+	var fieldErrs validation.Errors
+	var domainErr *services.Error
+	switch {
+	case errors.As(err, &fieldErrs):
+		problem.Status = fiber.StatusUnprocessableEntity
+		problem.Code = string(services.KindValidation)
+		problem.Detail = "request validation failed"
+		problem.Errors = fieldErrs
+	case errors.As(err, &domainErr):
+		problem.Status = kindStatus[domainErr.Kind]
+		problem.Code = string(domainErr.Kind)
+		problem.Detail = domainErr.Message
+		if domainErr.Kind == services.KindUpstream {
+			logging.FromContext(c.UserContext()).Error("request failed", "error", err)
+		}
+	case errors.As(err, &fiberErr):
+		problem.Status = fiberErr.Code
+		problem.Detail = fiberErr.Message
+	default:
+		problem.Status = fiber.StatusInternalServerError
+		problem.Detail = "an internal error occurred"
+		logging.FromContext(c.UserContext()).Error("request failed", "error", err)
+	}
+
+	if problem.Status == 0 {
+		problem.Status = fiber.StatusInternalServerError
+	}
+	problem.Title = http.StatusText(problem.Status)
+
+	// JSON sets application/json, so the problem media type is set afterwards
+	if err := c.Status(problem.Status).JSON(problem); err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, MIMEProblemJSON)
+	return nil
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kodra-pay/compliance-service/internal/services"
+)
+
+type GraphHandler struct {
+	service *services.GraphService
+}
+
+func NewGraphHandler(service *services.GraphService) *GraphHandler {
+	return &GraphHandler{service: service}
+}
+
+// GetEntity returns the neighbourhood of an entity as JSON or GraphML
+func (h *GraphHandler) GetEntity(c *fiber.Ctx) error {
+	nodeType := c.Params("type")
+	id := c.Params("id")
+
+	format := c.Query("format", "json")
+	if format != "json" && format != "graphml" {
+		return fiber.NewError(fiber.StatusBadRequest, "format must be 'json' or 'graphml'")
+	}
+
+	graph, err := h.service.GetNeighbourhood(c.UserContext(), nodeType, id, c.QueryInt("depth", 0))
+	if err != nil {
+		return err
+	}
+	if graph == nil {
+		return fiber.NewError(fiber.StatusNotFound, "entity not found in graph")
+	}
+
+	if format == "graphml" {
+		payload, err := h.service.GraphML(graph)
+		if err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentType, "application/graphml+xml; charset=utf-8")
+		c.Attachment(fmt.Sprintf("graph-%s-%d.graphml", graph.Root.Type, graph.Root.ID))
+		return c.Send(payload)
+	}
+
+	return c.JSON(graph)
+}
@@ -1,19 +1,109 @@
 package handlers
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HealthCheck is a readiness check of one dependency. A failing critical check makes the service
+// unready; a failing non-critical check only marks it degraded.
+type HealthCheck struct {
+	Name     string
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+// checkResult is the outcome of one check in the /readyz response
+type checkResult struct {
+	Status     string `json:"status"` // "ok" or "failing"
+	Critical   bool   `json:"critical"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
 
 type HealthHandler struct {
-	Service string
+	Service  string
+	timeout  time.Duration
+	checks   []HealthCheck
+	draining atomic.Bool
 }
 
-func NewHealthHandler(service string) *HealthHandler {
-	return &HealthHandler{Service: service}
+func NewHealthHandler(service string, timeout time.Duration, checks ...HealthCheck) *HealthHandler {
+	return &HealthHandler{Service: service, timeout: timeout, checks: checks}
 }
 
+// Register mounts /livez and /readyz. /health is kept as an alias of /livez for existing probes.
 func (h *HealthHandler) Register(r fiber.Router) {
 	r.Get("/health", h.Health)
+	r.Get("/livez", h.Health)
+	r.Get("/readyz", h.Ready)
+}
+
+// Drain makes /readyz fail so load balancers stop routing to the service while it shuts down
+func (h *HealthHandler) Drain() {
+	h.draining.Store(true)
 }
 
+// Health reports that the process is up. It checks no dependencies, so a database outage does not
+// get the service restarted.
 func (h *HealthHandler) Health(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"status": "ok", "service": h.Service})
 }
+
+// Ready runs every check concurrently and reports each result. It responds 503 while draining or
+// when a critical check fails.
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	if h.draining.Load() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"status":  "draining",
+			"service": h.Service,
+		})
+	}
+
+	results := make(map[string]checkResult, len(h.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func(check HealthCheck) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(c.UserContext(), h.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check.Check(ctx)
+			result := checkResult{Status: "ok", Critical: check.Critical, DurationMS: time.Since(start).Milliseconds()}
+			if err != nil {
+				result.Status = "failing"
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			results[check.Name] = result
+			mu.Unlock()
+		}(check)
+	}
+	wg.Wait()
+
+	status, code := "ok", fiber.StatusOK
+	for _, result := range results {
+		if result.Status == "ok" {
+			continue
+		}
+		if result.Critical {
+			status, code = "unavailable", fiber.StatusServiceUnavailable
+			break
+		}
+		status = "degraded"
+	}
+
+	return c.Status(code).JSON(fiber.Map{
+		"status":  status,
+		"service": h.Service,
+		"checks":  results,
+	})
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DatabaseCheck pings the database
+func DatabaseCheck(db *sql.DB) HealthCheck {
+	return HealthCheck{
+		Name:     "database",
+		Critical: true,
+		Check:    db.PingContext,
+	}
+}
+
+// RedisCheck pings Redis. It is not critical: while Redis is down idempotency keys, rate limits
+// and the status cache fall back to Postgres or the instance's memory.
+func RedisCheck(client *redis.Client) HealthCheck {
+	return HealthCheck{
+		Name:     "redis",
+		Critical: false,
+		Check: func(ctx context.Context) error {
+			return client.Ping(ctx).Err()
+		},
+	}
+}
+
+// ReachabilityCheck opens a TCP connection to the host of a dependency's URL. It does not call
+// the dependency's API, so it works for services that expose no health endpoint.
+func ReachabilityCheck(name, rawURL string, critical bool) HealthCheck {
+	return HealthCheck{
+		Name:     name,
+		Critical: critical,
+		Check: func(ctx context.Context) error {
+			address, err := dialAddress(rawURL)
+			if err != nil {
+				return err
+			}
+			var dialer net.Dialer
+			conn, err := dialer.DialContext(ctx, "tcp", address)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		},
+	}
+}
+
+// dialAddress returns the host:port of a URL, with the scheme's default port
+func dialAddress(rawURL string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" {
+		return "", fmt.Errorf("invalid URL %q", rawURL)
+	}
+	port := parsed.Port()
+	if port == "" {
+		port = "80"
+		if parsed.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(parsed.Hostname(), port), nil
+}
@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"context"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/kodra-pay/compliance-service/internal/middleware"
+	"github.com/kodra-pay/compliance-service/internal/models"
 	"github.com/kodra-pay/compliance-service/internal/services"
 )
 
@@ -21,9 +26,20 @@ func (h *KYCHandler) SubmitKYC(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
 	}
 
-	response, err := h.service.Submit(c.Context(), req)
+	// Merchants can only submit KYC for themselves
+	if principal := middleware.PrincipalFrom(c); principal != nil && principal.IsMerchant() {
+		if req.MerchantID == 0 {
+			req.MerchantID = principal.MerchantID
+		}
+		if !principal.CanAccessMerchant(req.MerchantID) {
+			return fiber.NewError(fiber.StatusForbidden, "merchants can only submit their own KYC")
+		}
+	}
+	logging.SetMerchant(c.UserContext(), req.MerchantID)
+
+	response, err := h.service.Submit(c.UserContext(), req)
 	if err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(response)
@@ -35,18 +51,19 @@ func (h *KYCHandler) GetKYCStatus(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid merchant ID")
 	}
+	logging.SetMerchant(c.UserContext(), merchantID)
+
+	if principal := middleware.PrincipalFrom(c); principal != nil && !principal.CanAccessMerchant(merchantID) {
+		return fiber.NewError(fiber.StatusForbidden, "merchants can only read their own KYC status")
+	}
 
-	status, err := h.service.GetLatest(c.Context(), merchantID)
+	status, err := h.service.GetLatest(c.UserContext(), merchantID)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "failed to get KYC status")
+		return err
 	}
 
 	if status == nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"merchant_id": merchantID,
-			"status":      "not_started",
-			"message":     "No KYC submission found",
-		})
+		return fiber.NewError(fiber.StatusNotFound, "no KYC submission found, KYC has not been started")
 	}
 
 	return c.JSON(status)
@@ -59,15 +76,25 @@ func (h *KYCHandler) UpdateKYCStatus(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
 	}
 
-	if req.MerchantID == 0 { // int check
-		return fiber.NewError(fiber.StatusBadRequest, "merchant_id is required")
+	// The reviewer is always the authenticated caller
+	principal := middleware.PrincipalFrom(c)
+	if principal == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
 	}
-	if req.Status == "" {
-		return fiber.NewError(fiber.StatusBadRequest, "status is required")
+	req.ReviewerID = principal.UserID
+	logging.SetMerchant(c.UserContext(), req.MerchantID)
+
+	decision, err := h.service.UpdateStatus(c.UserContext(), req)
+	if err != nil {
+		return err
 	}
 
-	if err := h.service.UpdateStatus(c.Context(), req); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	// In four-eyes mode the change waits for a second reviewer
+	if decision != nil {
+		return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+			"decision": decision,
+			"message":  "KYC decision proposed and awaiting confirmation by a second reviewer",
+		})
 	}
 
 	return c.JSON(fiber.Map{
@@ -86,9 +113,9 @@ func (h *KYCHandler) ListPendingKYC(c *fiber.Ctx) error {
 		limit = limitParam
 	}
 
-	result, err := h.service.ListByStatus(c.Context(), "pending", limit)
+	result, err := h.service.ListByStatus(c.UserContext(), "pending", limit)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "failed to list pending KYC submissions")
+		return err
 	}
 
 	return c.JSON(result)
@@ -103,10 +130,108 @@ func (h *KYCHandler) ListKYCByStatus(c *fiber.Ctx) error {
 		limit = 100
 	}
 
-	result, err := h.service.ListByStatus(c.Context(), status, limit)
+	result, err := h.service.ListByStatus(c.UserContext(), status, limit)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "failed to list KYC submissions")
+		return err
 	}
 
 	return c.JSON(result)
 }
+
+// ListSubmissionPersons lists the directors, shareholders and beneficial owners of a submission
+func (h *KYCHandler) ListSubmissionPersons(c *fiber.Ctx) error {
+	submissionID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid submission ID")
+	}
+
+	persons, err := h.service.ListPersons(c.UserContext(), submissionID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"persons": persons, "total": len(persons)})
+}
+
+// GetRelatedMerchants lists the merchants sharing identifiers with a submission
+func (h *KYCHandler) GetRelatedMerchants(c *fiber.Ctx) error {
+	submissionID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid submission ID")
+	}
+
+	related, err := h.service.GetRelated(c.UserContext(), submissionID)
+	if err != nil {
+		return err
+	}
+	if related == nil {
+		return fiber.NewError(fiber.StatusNotFound, "KYC submission not found")
+	}
+
+	return c.JSON(related)
+}
+
+// RecordPersonIdentity records the identity verification outcome of a declared person
+func (h *KYCHandler) RecordPersonIdentity(c *fiber.Ctx) error {
+	personID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid person ID")
+	}
+
+	var req dto.KYCPersonIdentityRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	person, err := h.service.RecordPersonIdentity(c.UserContext(), personID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(person)
+}
+
+// ListPendingDecisions lists KYC decisions awaiting a second reviewer
+func (h *KYCHandler) ListPendingDecisions(c *fiber.Ctx) error {
+	decisions, err := h.service.ListPendingDecisions(c.UserContext(), c.QueryInt("limit", 100))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{"decisions": decisions, "total": len(decisions)})
+}
+
+// ConfirmDecision applies a proposed KYC decision as the second reviewer
+func (h *KYCHandler) ConfirmDecision(c *fiber.Ctx) error {
+	return h.checkDecision(c, h.service.ConfirmDecision)
+}
+
+// OverturnDecision replaces a proposed KYC decision with the second reviewer's status
+func (h *KYCHandler) OverturnDecision(c *fiber.Ctx) error {
+	return h.checkDecision(c, h.service.OverturnDecision)
+}
+
+func (h *KYCHandler) checkDecision(c *fiber.Ctx, check func(context.Context, int, dto.KYCDecisionCheckRequest) (*models.KYCDecision, error)) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid decision ID")
+	}
+
+	var req dto.KYCDecisionCheckRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	principal := middleware.PrincipalFrom(c)
+	if principal == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+	req.CheckerID = principal.UserID
+
+	decision, err := check(c.UserContext(), id, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(decision)
+}
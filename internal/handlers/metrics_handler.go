@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsHandler serves the default Prometheus registry
+type MetricsHandler struct {
+	timeout    time.Duration
+	collectors []func(ctx context.Context) error
+	serve      fiber.Handler
+}
+
+// NewMetricsHandler creates the metrics handler. The collectors run before every scrape to
+// refresh gauges that are read from the store, such as the KYC backlog.
+func NewMetricsHandler(timeout time.Duration, collectors ...func(ctx context.Context) error) *MetricsHandler {
+	return &MetricsHandler{
+		timeout:    timeout,
+		collectors: collectors,
+		serve:      adaptor.HTTPHandler(promhttp.Handler()),
+	}
+}
+
+// Metrics writes every metric. A failing collector is logged and leaves its gauges at their last
+// value, so a database outage does not fail the scrape.
+func (h *MetricsHandler) Metrics(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), h.timeout)
+	defer cancel()
+	for _, collect := range h.collectors {
+		if err := collect(ctx); err != nil {
+			logging.FromContext(ctx).Warn("failed to collect metrics", "error", err)
+		}
+	}
+
+	return h.serve(c)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/middleware"
+	"github.com/kodra-pay/compliance-service/internal/services"
+)
+
+type PIIHandler struct {
+	service *services.PIIService
+}
+
+func NewPIIHandler(service *services.PIIService) *PIIHandler {
+	return &PIIHandler{service: service}
+}
+
+// UnmaskSubmission reveals the masked personal data of a KYC submission and audits the access
+func (h *PIIHandler) UnmaskSubmission(c *fiber.Ctx) error {
+	submissionID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid submission ID")
+	}
+
+	var req dto.PIIUnmaskRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	principal := middleware.PrincipalFrom(c)
+	if principal == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+	req.ActorID = principal.UserID
+	req.ActorRole = principal.Role
+	req.IP = c.IP()
+	req.RequestID = middleware.RequestIDFrom(c)
+
+	response, err := h.service.UnmaskSubmission(c.UserContext(), submissionID, req)
+	if err != nil {
+		return err
+	}
+	if response == nil {
+		return fiber.NewError(fiber.StatusNotFound, "KYC submission not found")
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(response)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/middleware"
+	"github.com/kodra-pay/compliance-service/internal/services"
+)
+
+type PrivacyHandler struct {
+	service *services.PrivacyService
+}
+
+func NewPrivacyHandler(service *services.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{service: service}
+}
+
+// CreateErasureRequest records a right-to-erasure request
+func (h *PrivacyHandler) CreateErasureRequest(c *fiber.Ctx) error {
+	var req dto.ErasureRequestCreate
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	principal := middleware.PrincipalFrom(c)
+	if principal == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+	req.RequestedBy = principal.UserID
+
+	request, err := h.service.CreateErasureRequest(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(request)
+}
+
+// GetErasureRequest returns the status of an erasure request
+func (h *PrivacyHandler) GetErasureRequest(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid erasure request ID")
+	}
+
+	request, err := h.service.GetErasureRequest(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+	if request == nil {
+		return fiber.NewError(fiber.StatusNotFound, "erasure request not found")
+	}
+
+	return c.JSON(request)
+}
+
+// ListErasureRequests lists erasure requests, the open ones unless a status is given
+func (h *PrivacyHandler) ListErasureRequests(c *fiber.Ctx) error {
+	result, err := h.service.ListErasureRequests(c.UserContext(), c.Query("status"), c.QueryInt("limit", 100))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(result)
+}
+
+// EndMerchantRelationship records that a merchant was offboarded, starting its retention period
+func (h *PrivacyHandler) EndMerchantRelationship(c *fiber.Ctx) error {
+	merchantID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid merchant ID")
+	}
+
+	if err := h.service.EndMerchantRelationship(c.UserContext(), merchantID); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"merchant_id": merchantID,
+		"message":     "Merchant relationship ended, retention period started",
+	})
+}
+
+// PlaceLegalHold places a legal hold on a data subject
+func (h *PrivacyHandler) PlaceLegalHold(c *fiber.Ctx) error {
+	var req dto.LegalHoldRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	principal := middleware.PrincipalFrom(c)
+	if principal == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+	req.PlacedBy = principal.UserID
+
+	hold, err := h.service.PlaceLegalHold(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(hold)
+}
+
+// ReleaseLegalHold releases a legal hold
+func (h *PrivacyHandler) ReleaseLegalHold(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid legal hold ID")
+	}
+
+	principal := middleware.PrincipalFrom(c)
+	if principal == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+
+	hold, err := h.service.ReleaseLegalHold(c.UserContext(), id, principal.UserID)
+	if err != nil {
+		return err
+	}
+	if hold == nil {
+		return fiber.NewError(fiber.StatusNotFound, "no active legal hold found")
+	}
+
+	return c.JSON(hold)
+}
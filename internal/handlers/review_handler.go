@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/middleware"
+	"github.com/kodra-pay/compliance-service/internal/services"
+)
+
+type ReviewHandler struct {
+	service *services.ReviewService
+}
+
+func NewReviewHandler(service *services.ReviewService) *ReviewHandler {
+	return &ReviewHandler{service: service}
+}
+
+// ListDueReviews lists open and escalated periodic KYC reviews
+func (h *ReviewHandler) ListDueReviews(c *fiber.Ctx) error {
+	result, err := h.service.ListDue(c.UserContext(), c.QueryInt("limit", 100))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(result)
+}
+
+// CompleteReview closes a periodic KYC review and schedules the next one
+func (h *ReviewHandler) CompleteReview(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid review ID")
+	}
+
+	var req dto.KYCReviewCompleteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	principal := middleware.PrincipalFrom(c)
+	if principal == nil {
+		return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+	}
+	req.ReviewerID = principal.UserID
+
+	review, err := h.service.Complete(c.UserContext(), id, req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(review)
+}
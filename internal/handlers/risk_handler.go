@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/services"
+)
+
+type RiskHandler struct {
+	service *services.RiskService
+}
+
+func NewRiskHandler(service *services.RiskService) *RiskHandler {
+	return &RiskHandler{service: service}
+}
+
+// GetMerchantRisk returns the current risk score of a merchant with its history
+func (h *RiskHandler) GetMerchantRisk(c *fiber.Ctx) error {
+	merchantID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid merchant ID")
+	}
+
+	risk, err := h.service.GetMerchantRisk(c.UserContext(), merchantID, c.QueryInt("limit", 20))
+	if err != nil {
+		return err
+	}
+	if risk == nil {
+		return fiber.NewError(fiber.StatusNotFound, "no risk score found for merchant")
+	}
+
+	return c.JSON(risk)
+}
+
+// RecalculateMerchantRisk forces a rescore of a merchant
+func (h *RiskHandler) RecalculateMerchantRisk(c *fiber.Ctx) error {
+	merchantID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid merchant ID")
+	}
+
+	score, err := h.service.Recalculate(c.UserContext(), merchantID, "manual")
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(score)
+}
+
+// RecordScreeningResult stores a screening outcome reported by the screening provider
+func (h *RiskHandler) RecordScreeningResult(c *fiber.Ctx) error {
+	var req dto.ScreeningResultRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.service.RecordScreeningResult(c.UserContext(), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(result)
+}
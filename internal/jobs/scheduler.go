@@ -0,0 +1,91 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Job is a unit of background work run periodically by the Scheduler
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+type funcJob struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Func adapts a function into a Job
+func Func(name string, fn func(ctx context.Context) error) Job {
+	return &funcJob{name: name, fn: fn}
+}
+
+func (j *funcJob) Name() string                  { return j.name }
+func (j *funcJob) Run(ctx context.Context) error { return j.fn(ctx) }
+
+type scheduledJob struct {
+	job      Job
+	interval time.Duration
+}
+
+// Scheduler runs registered jobs on fixed intervals until its context is cancelled
+type Scheduler struct {
+	jobs    []scheduledJob
+	running sync.WaitGroup
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers a job to run immediately on start and then once per interval
+func (s *Scheduler) Every(interval time.Duration, job Job) {
+	s.jobs = append(s.jobs, scheduledJob{job: job, interval: interval})
+}
+
+// Start launches a goroutine per registered job
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, sj := range s.jobs {
+		s.running.Add(1)
+		go s.loop(ctx, sj)
+	}
+}
+
+// Wait blocks until every job has stopped after the context passed to Start is cancelled. A run in
+// progress is finished first, so Wait drains the scheduler.
+func (s *Scheduler) Wait() {
+	s.running.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, sj scheduledJob) {
+	defer s.running.Done()
+
+	ticker := time.NewTicker(sj.interval)
+	defer ticker.Stop()
+
+	for {
+		s.run(ctx, sj.job)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("job panicked", "job", job.Name(), "panic", r)
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		slog.Error("job failed", "job", job.Name(), "duration", time.Since(start), "error", err)
+	}
+}
@@ -0,0 +1,117 @@
+// Package logging configures the service's slog logger and carries request fields in the context,
+// so every line logged while serving a request names the request, route, actor and merchant:
+//
+//	logging.FromContext(ctx).Warn("failed to sync merchant KYC status", "error", err)
+//
+// Fields are filled in as the request passes through the middleware: the request ID first, the
+// actor and merchant once the caller is authenticated, and the route once it is matched.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Log formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// New returns a logger writing records at or above level to w in the given format
+func New(w io.Writer, level slog.Level, format string) *slog.Logger {
+	options := &slog.HandlerOptions{Level: level, ReplaceAttr: durationText}
+	if format == FormatText {
+		return slog.New(slog.NewTextHandler(w, options))
+	}
+	return slog.New(slog.NewJSONHandler(w, options))
+}
+
+// durationText writes durations as text, such as 1.5s, rather than in nanoseconds
+func durationText(groups []string, attr slog.Attr) slog.Attr {
+	if attr.Value.Kind() == slog.KindDuration {
+		return slog.String(attr.Key, attr.Value.Duration().String())
+	}
+	return attr
+}
+
+// ParseLevel parses debug, info, warn or error
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(strings.TrimSpace(value)))
+	return level, err
+}
+
+// fields are the request attributes added to every line. They are set by different middleware
+// and read by whatever logs, possibly from another goroutine.
+type fields struct {
+	mu         sync.Mutex
+	requestID  string
+	route      string
+	actor      string
+	merchantID int
+}
+
+type fieldsKey struct{}
+
+// NewContext returns a context that carries the request's log fields, starting with its ID
+func NewContext(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, fieldsKey{}, &fields{requestID: requestID})
+}
+
+func update(ctx context.Context, set func(f *fields)) {
+	f, ok := ctx.Value(fieldsKey{}).(*fields)
+	if !ok {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	set(f)
+}
+
+// SetRoute records the matched route pattern, such as /kyc/status/:merchant_id
+func SetRoute(ctx context.Context, route string) {
+	update(ctx, func(f *fields) { f.route = route })
+}
+
+// SetActor records who is making the request, such as user:42
+func SetActor(ctx context.Context, actor string) {
+	update(ctx, func(f *fields) { f.actor = actor })
+}
+
+// SetMerchant records the merchant the request is about
+func SetMerchant(ctx context.Context, merchantID int) {
+	update(ctx, func(f *fields) { f.merchantID = merchantID })
+}
+
+// FromContext returns the default logger with the request fields and trace ID in ctx. Outside a
+// request, such as in background jobs, it is the default logger.
+func FromContext(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+
+	if f, ok := ctx.Value(fieldsKey{}).(*fields); ok {
+		f.mu.Lock()
+		attrs := []any{slog.String("request_id", f.requestID)}
+		if f.route != "" {
+			attrs = append(attrs, slog.String("route", f.route))
+		}
+		if f.actor != "" {
+			attrs = append(attrs, slog.String("actor", f.actor))
+		}
+		if f.merchantID != 0 {
+			attrs = append(attrs, slog.Int("merchant_id", f.merchantID))
+		}
+		f.mu.Unlock()
+		logger = logger.With(attrs...)
+	}
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		logger = logger.With(slog.String("trace_id", sc.TraceID().String()))
+	}
+	return logger
+}
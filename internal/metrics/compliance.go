@@ -0,0 +1,77 @@
+// Package metrics defines the service's Prometheus metrics. They are registered in the default
+// Prometheus registry, which /metrics serves, when the package is initialised.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// HTTPRequestDuration is the latency of every request by route pattern and response status.
+	// Its _count series is the request count, so it also serves as the status breakdown.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "compliance_http_request_duration_seconds",
+		Help:    "Latency of HTTP requests by method, route pattern and response status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// CacheRequests counts read-through cache lookups. result is "hit", "miss" or "error" when
+	// Redis failed or is bypassed and the store was read instead.
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "compliance_cache_requests_total",
+		Help: "Read-through cache lookups, by cache and result.",
+	}, []string{"cache", "result"})
+
+	// RateLimitedRequests counts requests refused with 429, by route pattern and the limit hit:
+	// "ip" or "caller"
+	RateLimitedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "compliance_rate_limited_requests_total",
+		Help: "Requests refused by rate limits, by route pattern and limit.",
+	}, []string{"route", "scope"})
+
+	// KYCSubmissions counts submissions entering each status: "pending" when submitted, then
+	// "approved" or "rejected" when a review is applied
+	KYCSubmissions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "compliance_kyc_submissions_total",
+		Help: "KYC submissions by the status they entered.",
+	}, []string{"status"})
+
+	// KYCReviewTurnaround is the time from submission to an applied review decision
+	KYCReviewTurnaround = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "compliance_kyc_review_turnaround_seconds",
+		Help:    "Time from KYC submission to an applied review decision, by decision.",
+		Buckets: prometheus.ExponentialBuckets(15*60, 2, 12), // 15 minutes to about 21 days
+	}, []string{"status"})
+
+	// KYCPendingSubmissions and KYCPendingOldestAge describe the review backlog. They are read
+	// from the store on every scrape.
+	KYCPendingSubmissions = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "compliance_kyc_pending_submissions",
+		Help: "KYC submissions waiting for review.",
+	})
+	KYCPendingOldestAge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "compliance_kyc_pending_oldest_age_seconds",
+		Help: "Age of the oldest KYC submission waiting for review, 0 when none is pending.",
+	})
+
+	// MerchantSyncFailures counts failed KYC status updates to the merchant service. reason is
+	// "request" when the call failed and "status" when it returned an unexpected status.
+	MerchantSyncFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "compliance_merchant_sync_failures_total",
+		Help: "Failed KYC status updates to the merchant service, by reason.",
+	}, []string{"reason"})
+
+	// MonitoringAlerts counts transaction monitoring alerts created per rule
+	MonitoringAlerts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "compliance_monitoring_alerts_total",
+		Help: "Transaction monitoring alerts created, by rule and severity.",
+	}, []string{"rule", "severity"})
+
+	// ScreeningResults counts recorded screening results. The hit rate is the share with status
+	// potential_match or confirmed_match.
+	ScreeningResults = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "compliance_screening_results_total",
+		Help: "Sanctions, PEP and adverse media screening results, by list type and status.",
+	}, []string{"list_type", "status"})
+)
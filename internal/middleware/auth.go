@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kodra-pay/compliance-service/internal/logging"
+)
+
+const principalKey = "principal"
+
+// Principal is the authenticated caller extracted from the bearer token
+type Principal struct {
+	UserID     int
+	Role       string
+	MerchantID int // set for merchant callers only
+}
+
+// IsMerchant reports whether the caller is a merchant restricted to its own data
+func (p *Principal) IsMerchant() bool {
+	return p.Role == RoleMerchant
+}
+
+// CanAccessMerchant reports whether the caller may read data belonging to the merchant
+func (p *Principal) CanAccessMerchant(merchantID int) bool {
+	return !p.IsMerchant() || p.MerchantID == merchantID
+}
+
+// Claims are the JWT claims issued to compliance-service callers
+type Claims struct {
+	jwt.RegisteredClaims
+	Role       string `json:"role"`
+	MerchantID int    `json:"merchant_id,omitempty"`
+}
+
+// AuthConfig configures JWT validation
+type AuthConfig struct {
+	Secret   string
+	Issuer   string
+	Audience string
+}
+
+// Authenticate validates the HS256 bearer token and stores the caller's Principal on the context
+func Authenticate(cfg AuthConfig) fiber.Handler {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	parser := jwt.NewParser(opts...)
+
+	return func(c *fiber.Ctx) error {
+		if cfg.Secret == "" {
+			return fiber.NewError(fiber.StatusUnauthorized, "authentication is not configured")
+		}
+
+		header := c.Get(fiber.HeaderAuthorization)
+		tokenString, found := strings.CutPrefix(header, "Bearer ")
+		if !found || tokenString == "" {
+			return fiber.NewError(fiber.StatusUnauthorized, "missing bearer token")
+		}
+
+		var claims Claims
+		if _, err := parser.ParseWithClaims(tokenString, &claims, func(*jwt.Token) (interface{}, error) {
+			return []byte(cfg.Secret), nil
+		}); err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "invalid token")
+		}
+
+		principal, err := principalFromClaims(&claims)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+		}
+
+		c.Locals(principalKey, principal)
+		logging.SetActor(c.UserContext(), fmt.Sprintf("user:%d", principal.UserID))
+		if principal.MerchantID != 0 {
+			logging.SetMerchant(c.UserContext(), principal.MerchantID)
+		}
+		return c.Next()
+	}
+}
+
+// Require rejects callers whose role does not grant every listed permission
+func Require(perms ...Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := PrincipalFrom(c)
+		if principal == nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "authentication required")
+		}
+		for _, perm := range perms {
+			if !HasPermission(principal.Role, perm) {
+				return fiber.NewError(fiber.StatusForbidden, "missing permission "+string(perm))
+			}
+		}
+		// Require is registered on each route, so the route here is the handler's pattern
+		logging.SetRoute(c.UserContext(), c.Route().Path)
+		return c.Next()
+	}
+}
+
+// PrincipalFrom returns the authenticated caller, or nil when the request is unauthenticated
+func PrincipalFrom(c *fiber.Ctx) *Principal {
+	principal, _ := c.Locals(principalKey).(*Principal)
+	return principal
+}
+
+func principalFromClaims(claims *Claims) (*Principal, error) {
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil || userID <= 0 {
+		return nil, errors.New("token subject must be a numeric user ID")
+	}
+	if _, ok := rolePermissions[claims.Role]; !ok {
+		return nil, errors.New("token role is not recognised")
+	}
+	if claims.Role == RoleMerchant && claims.MerchantID == 0 {
+		return nil, errors.New("merchant token is missing merchant_id")
+	}
+
+	return &Principal{UserID: userID, Role: claims.Role, MerchantID: claims.MerchantID}, nil
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit rejects requests with a body larger than maxBytes with 413. The server caps every body
+// at HTTP_MAX_BODY_BYTES while reading it; BodyLimit sets a lower cap for a route.
+func BodyLimit(maxBytes int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if len(c.Body()) > maxBytes {
+			return fiber.NewError(fiber.StatusRequestEntityTooLarge,
+				fmt.Sprintf("request body must not exceed %d bytes", maxBytes))
+		}
+		return c.Next()
+	}
+}
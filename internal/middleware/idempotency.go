@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/kodra-pay/compliance-service/internal/repositories"
+)
+
+// Idempotency headers
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyConfig configures Idempotency
+type IdempotencyConfig struct {
+	Store       repositories.IdempotencyStore
+	TTL         time.Duration // how long the response is replayed
+	LockTimeout time.Duration // how long the key stays claimed if the request never completes
+}
+
+// Idempotency makes retries of a route safe for requests with an Idempotency-Key header. The first
+// request with a key claims it and its response is stored; a retry with the same key and body gets
+// the stored response with Idempotent-Replayed: true. The same key with a different body is
+// rejected with 422, and a retry while the first request is still running with 409. Responses that
+// ask the client to retry, server errors, 408, 409 and 429, are not stored and release the key, so
+// the retry is handled again. Requests without the header are not affected.
+//
+// Keys are scoped to the caller and route, so callers cannot see each other's responses. It runs
+// after Authenticate, on the route, and hands errors from the handler to the app's error handler
+// like Metrics so the stored response is the one sent.
+func Idempotency(cfg IdempotencyConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return c.Next()
+		}
+		if !validIdempotencyKey(key) {
+			return fiber.NewError(fiber.StatusBadRequest,
+				fmt.Sprintf("%s must be 1 to %d printable ASCII characters", HeaderIdempotencyKey, maxIdempotencyKeyLength))
+		}
+
+		userID := 0
+		if principal := PrincipalFrom(c); principal != nil {
+			userID = principal.UserID
+		}
+		// Sprintf copies the values Fiber backs with reused buffers
+		scopedKey := fmt.Sprintf("%d:%s %s:%s", userID, c.Method(), c.Route().Path, key)
+		requestHash := hashRequest(c)
+
+		ctx := c.UserContext()
+		existing, err := cfg.Store.Reserve(ctx, scopedKey, requestHash, cfg.LockTimeout)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return replay(c, existing, requestHash)
+		}
+
+		// A panic leaves the key claimed until the lock timeout unless it is released here
+		completed := false
+		defer func() {
+			if !completed {
+				_ = cfg.Store.Release(ctx, scopedKey, requestHash)
+			}
+		}()
+
+		if err := c.Next(); err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		if retryable(status) {
+			return nil
+		}
+		completed = true
+		err = cfg.Store.Complete(ctx, &models.IdempotentRequest{
+			Key:         scopedKey,
+			RequestHash: requestHash,
+			StatusCode:  status,
+			ContentType: utils.CopyString(string(c.Response().Header.ContentType())),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}, cfg.TTL)
+		if err != nil {
+			// The response is sent regardless; a retry will be handled again
+			logging.FromContext(ctx).Warn("failed to store idempotent response", "error", err)
+			_ = cfg.Store.Release(ctx, scopedKey, requestHash)
+		}
+		return nil
+	}
+}
+
+// replay answers a request whose key is already held
+func replay(c *fiber.Ctx, existing *models.IdempotentRequest, requestHash string) error {
+	switch {
+	case existing.RequestHash != requestHash:
+		return fiber.NewError(fiber.StatusUnprocessableEntity,
+			HeaderIdempotencyKey+" was already used for a different request")
+	case !existing.Completed:
+		return fiber.NewError(fiber.StatusConflict,
+			"a request with this "+HeaderIdempotencyKey+" is still being processed")
+	}
+
+	c.Set(HeaderIdempotentReplayed, "true")
+	if existing.ContentType != "" {
+		c.Set(fiber.HeaderContentType, existing.ContentType)
+	}
+	return c.Status(existing.StatusCode).Send(existing.Body)
+}
+
+// retryable reports whether a response status tells the client to try the request again
+func retryable(status int) bool {
+	switch status {
+	case fiber.StatusRequestTimeout, fiber.StatusConflict, fiber.StatusTooManyRequests:
+		return true
+	}
+	return status >= fiber.StatusInternalServerError
+}
+
+// hashRequest is the SHA-256 of the method, path and body
+func hashRequest(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method() + "\n" + c.Path() + "\n"))
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLength {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kodra-pay/compliance-service/internal/logging"
+)
+
+// Logger writes one access log line per request with the request's log fields. It runs after
+// RequestID, and hands errors to the app's error handler like Metrics so the logged status is the
+// one sent to the client.
+func Logger() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		if err := c.Next(); err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		ctx := c.UserContext()
+		logging.SetRoute(ctx, c.Route().Path)
+
+		status := c.Response().StatusCode()
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logging.FromContext(ctx).Log(ctx, level, "request",
+			slog.String("method", c.Method()),
+			slog.String("path", c.Path()),
+			slog.Int("status", status),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("ip", c.IP()),
+		)
+		return nil
+	}
+}
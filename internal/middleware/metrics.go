@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kodra-pay/compliance-service/internal/metrics"
+)
+
+// Metrics records the latency and status of every request by route pattern. Like the Fiber logger
+// it hands errors from the rest of the chain to the app's error handler, so the recorded status is
+// the one sent to the client.
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		if err := c.Next(); err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		// The route pattern rather than the path keeps IDs out of the label values
+		status := strconv.Itoa(c.Response().StatusCode())
+		metrics.HTTPRequestDuration.WithLabelValues(c.Method(), c.Route().Path, status).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
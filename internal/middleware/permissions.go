@@ -0,0 +1,64 @@
+package middleware
+
+// Roles carried in the JWT "role" claim
+const (
+	RoleMerchant       = "merchant"
+	RoleReviewer       = "reviewer"
+	RoleSeniorReviewer = "senior_reviewer"
+	RoleMLRO           = "mlro"
+	RoleAuditor        = "auditor"
+	RoleService        = "service"
+)
+
+// Permission is an action a route can require from the caller
+type Permission string
+
+const (
+	PermKYCSubmit       Permission = "kyc:submit"
+	PermKYCRead         Permission = "kyc:read" // merchants are limited to their own records
+	PermKYCReadAll      Permission = "kyc:read_all"
+	PermKYCReview       Permission = "kyc:review"
+	PermKYCVerifyPerson Permission = "kyc:verify_person"
+	PermPIIUnmask       Permission = "pii:unmask" // reveal masked BVNs, phone numbers, emails and TINs
+	PermEDDComplete     Permission = "edd:complete"
+	PermEDDSignOff      Permission = "edd:signoff"
+	PermRiskRead        Permission = "risk:read"
+	PermRiskRecalculate Permission = "risk:recalculate"
+	PermScreeningWrite  Permission = "screening:write"
+	PermMonitoringWrite Permission = "monitoring:write"
+	PermReportsRead     Permission = "reports:read"
+	PermReportsGenerate Permission = "reports:generate"
+	PermGraphRead       Permission = "graph:read"
+	PermPrivacyRead     Permission = "privacy:read"
+	PermPrivacyErasure  Permission = "privacy:erasure"
+	PermPrivacyHold     Permission = "privacy:legal_hold"
+)
+
+var reviewerPermissions = []Permission{
+	PermKYCRead, PermKYCReadAll, PermKYCReview, PermKYCVerifyPerson, PermEDDComplete, PermRiskRead, PermReportsRead,
+}
+
+// rolePermissions maps each role to the permissions it grants
+var rolePermissions = map[string][]Permission{
+	RoleMerchant:       {PermKYCSubmit, PermKYCRead},
+	RoleReviewer:       reviewerPermissions,
+	RoleSeniorReviewer: append(append([]Permission{}, reviewerPermissions...), PermEDDSignOff, PermGraphRead),
+	RoleMLRO: append(append([]Permission{}, reviewerPermissions...),
+		PermEDDSignOff, PermRiskRecalculate, PermReportsGenerate, PermPIIUnmask, PermGraphRead,
+		PermPrivacyRead, PermPrivacyErasure, PermPrivacyHold),
+	RoleAuditor: {PermKYCRead, PermKYCReadAll, PermRiskRead, PermReportsRead, PermGraphRead, PermPrivacyRead},
+	RoleService: {
+		PermKYCSubmit, PermKYCRead, PermKYCReadAll, PermKYCVerifyPerson, PermRiskRead,
+		PermScreeningWrite, PermMonitoringWrite, PermPrivacyRead, PermPrivacyErasure,
+	},
+}
+
+// HasPermission reports whether the role grants the permission
+func HasPermission(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/kodra-pay/compliance-service/internal/metrics"
+	"github.com/kodra-pay/compliance-service/internal/ratelimit"
+)
+
+// RateLimitConfig configures RateLimit
+type RateLimitConfig struct {
+	Limiter  ratelimit.Limiter
+	Merchant ratelimit.Limit // per merchant; staff callers are limited per user
+	IP       ratelimit.Limit // per client IP
+}
+
+// rateLimitCheck is one bucket a request takes a token from
+type rateLimitCheck struct {
+	scope string // "ip" or "caller", the metric label
+	key   string
+	limit ratelimit.Limit
+}
+
+// RateLimit limits a route per client IP and per caller. Refused requests get 429 with a
+// Retry-After header in seconds. Buckets are per route, so a caller's submissions do not use up
+// its other requests. It runs after Authenticate, on the route.
+//
+// If the limiter fails the request is let through: the limits protect against abuse, and a failing
+// limiter should not take the route down with it.
+func RateLimit(cfg RateLimitConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Sprintf copies the values Fiber backs with reused buffers
+		route := c.Route().Path
+		checks := []rateLimitCheck{{scope: "ip", key: fmt.Sprintf("%s:ip:%s", route, c.IP()), limit: cfg.IP}}
+		if principal := PrincipalFrom(c); principal != nil {
+			caller := fmt.Sprintf("%s:user:%d", route, principal.UserID)
+			if principal.IsMerchant() {
+				caller = fmt.Sprintf("%s:merchant:%d", route, principal.MerchantID)
+			}
+			checks = append(checks, rateLimitCheck{scope: "caller", key: caller, limit: cfg.Merchant})
+		}
+
+		for _, check := range checks {
+			result, err := cfg.Limiter.Allow(c.UserContext(), check.key, check.limit)
+			if err != nil {
+				logging.FromContext(c.UserContext()).Warn("rate limiter failed, request not limited", "error", err)
+				continue
+			}
+			if result.Allowed {
+				continue
+			}
+
+			metrics.RateLimitedRequests.WithLabelValues(route, check.scope).Inc()
+			retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+			return fiber.NewError(fiber.StatusTooManyRequests,
+				fmt.Sprintf("too many requests, retry after %d seconds", retryAfter))
+		}
+		return c.Next()
+	}
+}
@@ -1,19 +1,34 @@
 package middleware
 
 import (
-	"fmt"
-	"time"
-
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/google/uuid"
+	"github.com/kodra-pay/compliance-service/internal/logging"
 )
 
+const requestIDKey = "request_id"
+
+// RequestID propagates the caller's X-Request-ID header, or assigns a UUID, and echoes it in the
+// response. It also starts the request's log fields, so every line logged for the request carries
+// the ID.
 func RequestID() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		requestID := c.Get("X-Request-ID")
+		// The header value is backed by a buffer Fiber reuses, and the ID outlives the request
+		// in log fields
+		requestID := utils.CopyString(c.Get("X-Request-ID"))
 		if requestID == "" {
-			requestID = fmt.Sprintf("%d", time.Now().UnixNano())
+			requestID = uuid.NewString()
 		}
+		c.Locals(requestIDKey, requestID)
 		c.Set("X-Request-ID", requestID)
+		c.SetUserContext(logging.NewContext(c.UserContext(), requestID))
 		return c.Next()
 	}
 }
+
+// RequestIDFrom returns the request ID assigned by RequestID, or "" if the middleware did not run
+func RequestIDFrom(c *fiber.Ctx) string {
+	requestID, _ := c.Locals(requestIDKey).(string)
+	return requestID
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/kodra-pay/compliance-service/internal/middleware")
+
+// Tracing starts a server span for every request, continuing the caller's trace when it sends a
+// traceparent header. Handlers pass c.UserContext() on so store and merchant-service calls become
+// child spans. Errors from the rest of the chain go to the app's error handler here, as in Metrics,
+// so the span records the status sent to the client.
+func Tracing() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), requestCarrier{c})
+		// Fiber reuses the request's buffers, so values kept on the span are copied
+		method := utils.CopyString(c.Method())
+		ctx, span := tracer.Start(ctx, method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", method),
+				attribute.String("url.path", utils.CopyString(c.Path())),
+			))
+		defer span.End()
+		c.SetUserContext(ctx)
+
+		if err := c.Next(); err != nil {
+			if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		// The route is only known once the router has matched it
+		route := c.Route().Path
+		status := c.Response().StatusCode()
+		span.SetName(method + " " + route)
+		span.SetAttributes(
+			attribute.String("http.route", route),
+			attribute.Int("http.response.status_code", status),
+			attribute.String("request.id", utils.CopyString(RequestIDFrom(c))),
+		)
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, "HTTP "+strconv.Itoa(status))
+		}
+		return nil
+	}
+}
+
+// requestCarrier reads the trace context from the request headers
+type requestCarrier struct {
+	c *fiber.Ctx
+}
+
+func (r requestCarrier) Get(key string) string {
+	// The trace state keeps parts of the value, which Fiber backs with a reused buffer
+	return utils.CopyString(r.c.Get(key))
+}
+
+func (r requestCarrier) Set(key, value string) {
+	r.c.Request().Header.Set(key, value)
+}
+
+func (r requestCarrier) Keys() []string {
+	var keys []string
+	r.c.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
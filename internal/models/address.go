@@ -0,0 +1,31 @@
+package models
+
+// Address flags raised during normalisation
+const (
+	AddressFlagPOBox             = "po_box"
+	AddressFlagVirtualOffice     = "virtual_office"
+	AddressFlagCityStateMismatch = "city_state_mismatch"
+)
+
+// Geocode precisions, from most to least precise
+const (
+	GeocodePrecisionStreet = "street"
+	GeocodePrecisionCity   = "city"
+	GeocodePrecisionState  = "state"
+)
+
+// NormalizedAddress is the standardised form of a business address: the state is one of the
+// Nigerian states or the FCT, street abbreviations are expanded and the key is used for
+// duplicate detection across merchants.
+type NormalizedAddress struct {
+	Line             string   `json:"line"`
+	City             string   `json:"city"`
+	State            string   `json:"state"`
+	PostalCode       string   `json:"postal_code,omitempty"`
+	Key              string   `json:"key"`
+	Flags            []string `json:"flags,omitempty"` // "po_box", "virtual_office", "city_state_mismatch"
+	Latitude         *float64 `json:"latitude,omitempty"`
+	Longitude        *float64 `json:"longitude,omitempty"`
+	GeocodePrecision string   `json:"geocode_precision,omitempty"` // "street", "city", "state"
+	GeocodeSource    string   `json:"geocode_source,omitempty"`    // "provider" or "gazetteer"
+}
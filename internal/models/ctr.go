@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// CTRBatch represents a generated Currency Transaction Report batch for a single day
+type CTRBatch struct {
+	ID                  int             `json:"id"`
+	ReportDate          time.Time       `json:"report_date"`
+	Status              string          `json:"status"` // "generated", "submitted"
+	IndividualThreshold int64           `json:"individual_threshold"`
+	CorporateThreshold  int64           `json:"corporate_threshold"`
+	Currency            string          `json:"currency"`
+	RecordCount         int             `json:"record_count"`
+	TotalAmount         int64           `json:"total_amount"`
+	GoAMLXML            string          `json:"-"`
+	CSVPayload          string          `json:"-"`
+	GeneratedBy         string          `json:"generated_by"` // "scheduler" or the requesting actor
+	GeneratedAt         time.Time       `json:"generated_at"`
+	Entries             []CTRBatchEntry `json:"entries,omitempty"`
+}
+
+// CTRBatchEntry is a single customer whose daily total breached the reporting threshold
+type CTRBatchEntry struct {
+	ID               int    `json:"id"`
+	BatchID          int    `json:"batch_id"`
+	CustomerID       int    `json:"customer_id"`
+	CustomerType     string `json:"customer_type"`
+	CustomerName     string `json:"customer_name,omitempty"`
+	TransactionCount int    `json:"transaction_count"`
+	TotalAmount      int64  `json:"total_amount"`
+	Threshold        int64  `json:"threshold"`
+}
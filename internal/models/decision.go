@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// KYC decision statuses
+const (
+	DecisionStatusProposed   = "proposed"
+	DecisionStatusConfirmed  = "confirmed"
+	DecisionStatusOverturned = "overturned"
+)
+
+// KYCDecision is a maker-checker proposal to change the status of a KYC submission.
+// The maker proposes a status and a different checker confirms or overturns it.
+type KYCDecision struct {
+	ID             int        `json:"id"`
+	SubmissionID   int        `json:"submission_id"`
+	MerchantID     int        `json:"merchant_id"`
+	ProposedStatus string     `json:"proposed_status"`
+	ProposedBy     int        `json:"proposed_by"`
+	ProposalNotes  *string    `json:"proposal_notes,omitempty"`
+	ProposedAt     time.Time  `json:"proposed_at"`
+	Status         string     `json:"status"` // "proposed", "confirmed", "overturned"
+	FinalStatus    *string    `json:"final_status,omitempty"`
+	CheckedBy      *int       `json:"checked_by,omitempty"`
+	CheckNotes     *string    `json:"check_notes,omitempty"`
+	CheckedAt      *time.Time `json:"checked_at,omitempty"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// EDD checklist statuses
+const (
+	EDDStatusOpen            = "open"
+	EDDStatusAwaitingSignOff = "awaiting_signoff"
+	EDDStatusSignedOff       = "signed_off"
+	EDDItemStatusPending     = "pending"
+	EDDItemStatusCompleted   = "completed"
+)
+
+// EDDChecklist is the enhanced due diligence required before a high-risk submission can be approved
+type EDDChecklist struct {
+	ID            int        `json:"id"`
+	SubmissionID  int        `json:"submission_id"`
+	MerchantID    int        `json:"merchant_id"`
+	Reasons       []string   `json:"reasons"`
+	Status        string     `json:"status"` // "open", "awaiting_signoff", "signed_off"
+	Items         []EDDItem  `json:"items"`
+	SignedOffBy   *int       `json:"signed_off_by,omitempty"`
+	SignedOffRole *string    `json:"signed_off_role,omitempty"`
+	SignOffNotes  *string    `json:"signoff_notes,omitempty"`
+	SignedOffAt   *time.Time `json:"signed_off_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// EDDItem is a single piece of enhanced due diligence evidence
+type EDDItem struct {
+	ID          int        `json:"id"`
+	ChecklistID int        `json:"checklist_id"`
+	ItemType    string     `json:"item_type"` // "source_of_funds", "beneficial_ownership", ...
+	Status      string     `json:"status"`    // "pending", "completed"
+	Evidence    *string    `json:"evidence,omitempty"`
+	Notes       *string    `json:"notes,omitempty"`
+	CompletedBy *int       `json:"completed_by,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// Complete reports whether every item in the checklist has been completed
+func (c *EDDChecklist) Complete() bool {
+	for _, item := range c.Items {
+		if item.Status != EDDItemStatusCompleted {
+			return false
+		}
+	}
+	return true
+}
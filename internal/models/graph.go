@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Entity graph node types
+const (
+	NodeTypeMerchant    = "merchant"
+	NodeTypePerson      = "person"
+	NodeTypeBVN         = "bvn"
+	NodeTypePhone       = "phone"
+	NodeTypeEmail       = "email"
+	NodeTypeAddress     = "address"
+	NodeTypeBankAccount = "bank_account"
+	NodeTypeTransaction = "transaction"
+)
+
+// Entity graph edge types. Person edges use the person's role ("director", "shareholder",
+// "beneficial_owner").
+const (
+	EdgeTypeHasBVN       = "has_bvn"
+	EdgeTypeHasPhone     = "has_phone"
+	EdgeTypeHasEmail     = "has_email"
+	EdgeTypeLocatedAt    = "located_at"
+	EdgeTypeAlerted      = "alerted"
+	EdgeTypeCounterparty = "counterparty"
+)
+
+// GraphNode is an entity in the investigation graph
+type GraphNode struct {
+	ID         int               `json:"id"`
+	Type       string            `json:"type"`
+	Key        string            `json:"key"`
+	Label      string            `json:"label"`
+	Properties map[string]string `json:"properties,omitempty"`
+	Depth      int               `json:"depth"` // hops from the requested entity
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// GraphEdge links two graph nodes
+type GraphEdge struct {
+	ID         int               `json:"id"`
+	FromNodeID int               `json:"from"`
+	ToNodeID   int               `json:"to"`
+	Type       string            `json:"type"`
+	Source     string            `json:"source"` // row the edge was derived from, e.g. "kyc_submission:42"
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// EntityGraph is the neighbourhood of an entity up to a given depth
+type EntityGraph struct {
+	Root      GraphNode   `json:"root"`
+	Depth     int         `json:"depth"`
+	Nodes     []GraphNode `json:"nodes"`
+	Edges     []GraphEdge `json:"edges"`
+	Truncated bool        `json:"truncated"` // the node limit was reached before the full depth
+}
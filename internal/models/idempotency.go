@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// IdempotentRequest is a request sent with an Idempotency-Key header. The key is claimed while the
+// request is handled; once it completes the response is stored and replayed for retries.
+type IdempotentRequest struct {
+	Key         string    `json:"key"`
+	RequestHash string    `json:"request_hash"` // SHA-256 of the method, path and body
+	Completed   bool      `json:"completed"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	ContentType string    `json:"content_type,omitempty"`
+	Body        []byte    `json:"body,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
@@ -4,27 +4,56 @@ import "time"
 
 // KYCSubmission represents a KYC submission from a merchant
 type KYCSubmission struct {
-	ID                int               `json:"id"`
-	MerchantID        int               `json:"merchant_id"`
-	BusinessType      string            `json:"business_type"`      // "registered" or "startup"
-	BusinessName      string            `json:"business_name"`
-	CACNumber         string            `json:"cac_number,omitempty"`
-	TINNumber         string            `json:"tin_number,omitempty"`
-	BusinessAddress   string            `json:"business_address"`
-	City              string            `json:"city"`
-	State             string            `json:"state"`
-	PostalCode        string            `json:"postal_code,omitempty"`
-	IncorporationDate *time.Time        `json:"incorporation_date,omitempty"`
-	BusinessCategory  string            `json:"business_category"`
-	DirectorName      string            `json:"director_name"`
-	DirectorBVN       string            `json:"director_bvn"`
-	DirectorPhone     string            `json:"director_phone"`
-	DirectorEmail     string            `json:"director_email"`
-	Documents         map[string]string `json:"documents"` // document_type -> file_path/url
-	Status            string            `json:"status"`    // "pending", "approved", "rejected"
-	ReviewerID        *int              `json:"reviewer_id,omitempty"`
-	ReviewNotes       *string           `json:"review_notes,omitempty"`
-	ReviewedAt        *time.Time        `json:"reviewed_at,omitempty"`
-	CreatedAt         time.Time         `json:"created_at"`
-	UpdatedAt         time.Time         `json:"updated_at"`
+	ID                int                `json:"id"`
+	MerchantID        int                `json:"merchant_id"`
+	BusinessType      string             `json:"business_type"` // "registered" or "startup"
+	BusinessName      string             `json:"business_name"`
+	CACNumber         string             `json:"cac_number,omitempty"`
+	TINNumber         string             `json:"tin_number,omitempty"`
+	BusinessAddress   string             `json:"business_address"`
+	City              string             `json:"city"`
+	State             string             `json:"state"`
+	PostalCode        string             `json:"postal_code,omitempty"`
+	Address           *NormalizedAddress `json:"normalized_address,omitempty"`
+	IncorporationDate *time.Time         `json:"incorporation_date,omitempty"`
+	BusinessCategory  string             `json:"business_category"`
+	DirectorName      string             `json:"director_name"`
+	DirectorBVN       string             `json:"director_bvn"`
+	DirectorPhone     string             `json:"director_phone"`
+	DirectorEmail     string             `json:"director_email"`
+	Documents         map[string]string  `json:"documents"` // document_type -> file_path/url
+	Persons           []KYCPerson        `json:"persons,omitempty"`
+	Status            string             `json:"status"` // "pending", "approved", "rejected"
+	ReviewerID        *int               `json:"reviewer_id,omitempty"`
+	ReviewNotes       *string            `json:"review_notes,omitempty"`
+	ReviewedAt        *time.Time         `json:"reviewed_at,omitempty"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+}
+
+// KYC person roles
+const (
+	PersonRoleDirector        = "director"
+	PersonRoleShareholder     = "shareholder"
+	PersonRoleBeneficialOwner = "beneficial_owner"
+)
+
+// KYCPerson is a director, shareholder or beneficial owner declared on a KYC submission.
+// Each person is identity-verified and screened individually.
+type KYCPerson struct {
+	ID                  int        `json:"id"`
+	SubmissionID        int        `json:"submission_id"`
+	Role                string     `json:"role"` // "director", "shareholder", "beneficial_owner"
+	FullName            string     `json:"full_name"`
+	BVN                 string     `json:"bvn,omitempty"`
+	Phone               string     `json:"phone,omitempty"`
+	Email               string     `json:"email,omitempty"`
+	DateOfBirth         *time.Time `json:"date_of_birth,omitempty"`
+	Nationality         string     `json:"nationality,omitempty"`
+	OwnershipPercentage *float64   `json:"ownership_percentage,omitempty"`
+	IdentityStatus      string     `json:"identity_status"` // "pending", "verified", "failed"
+	IdentityReference   *string    `json:"identity_reference,omitempty"`
+	ScreeningStatus     string     `json:"screening_status"` // "pending", "clear", "potential_match", "confirmed_match"
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
 }
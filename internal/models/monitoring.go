@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// MonitoredTransaction represents a transaction captured by the transaction monitoring store.
+// Amounts are held in minor currency units (kobo for NGN).
+type MonitoredTransaction struct {
+	ID                  int       `json:"id"`
+	Reference           string    `json:"reference"`
+	CustomerID          int       `json:"customer_id"`
+	CustomerType        string    `json:"customer_type"` // "individual" or "corporate"
+	CustomerName        string    `json:"customer_name,omitempty"`
+	Channel             string    `json:"channel"`   // "cash" or "transfer"
+	Direction           string    `json:"direction"` // "credit" or "debit"
+	Amount              int64     `json:"amount"`
+	Currency            string    `json:"currency"`
+	CounterpartyName    string    `json:"counterparty_name,omitempty"`
+	CounterpartyAccount string    `json:"counterparty_account,omitempty"`
+	OccurredAt          time.Time `json:"occurred_at"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// CustomerDailyTotal is the aggregate of a customer's transactions for a single day
+type CustomerDailyTotal struct {
+	CustomerID       int    `json:"customer_id"`
+	CustomerType     string `json:"customer_type"`
+	CustomerName     string `json:"customer_name,omitempty"`
+	TransactionCount int    `json:"transaction_count"`
+	TotalAmount      int64  `json:"total_amount"`
+}
+
+// TransactionSummary summarises a customer's transaction behaviour over a period
+type TransactionSummary struct {
+	TransactionCount int   `json:"transaction_count"`
+	TotalAmount      int64 `json:"total_amount"`
+	CashAmount       int64 `json:"cash_amount"`
+}
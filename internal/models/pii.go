@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// PIIAccess records a caller revealing masked personal data
+type PIIAccess struct {
+	ID        int       `json:"id"`
+	ActorID   int       `json:"actor_id"`
+	ActorRole string    `json:"actor_role"`
+	Entity    string    `json:"entity"` // "kyc_submission"
+	EntityID  int       `json:"entity_id"`
+	Fields    []string  `json:"fields"`
+	Reason    string    `json:"reason"`
+	IP        string    `json:"ip,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
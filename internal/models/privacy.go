@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Data subject types covered by retention and erasure
+const (
+	SubjectTypeMerchant = "merchant" // KYC submissions, declared persons and documents
+	SubjectTypeUser     = "user"     // KYC records and transaction monitoring alerts
+)
+
+// Erasure request statuses
+const (
+	ErasureStatusReceived  = "received"
+	ErasureStatusDeferred  = "deferred" // the mandated retention period has not ended yet
+	ErasureStatusOnHold    = "on_hold"  // a legal hold applies to the subject
+	ErasureStatusCompleted = "completed"
+)
+
+// ErasureRequest is a data subject's request to have their personal data erased. Data still under
+// its retention period or a legal hold is erased once the period ends or the hold is released.
+type ErasureRequest struct {
+	ID          int        `json:"id"`
+	SubjectType string     `json:"subject_type"`
+	SubjectID   int        `json:"subject_id"`
+	Reason      *string    `json:"reason,omitempty"`
+	RequestedBy int        `json:"requested_by"`
+	Status      string     `json:"status"` // "received", "deferred", "on_hold", "completed"
+	RetainUntil *time.Time `json:"retain_until,omitempty"`
+	StatusNotes *string    `json:"status_notes,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// LegalHold suspends retention purges and erasure for a data subject until it is released
+type LegalHold struct {
+	ID          int        `json:"id"`
+	SubjectType string     `json:"subject_type"`
+	SubjectID   int        `json:"subject_id"`
+	Reason      string     `json:"reason"`
+	PlacedBy    int        `json:"placed_by"`
+	PlacedAt    time.Time  `json:"placed_at"`
+	ReleasedBy  *int       `json:"released_by,omitempty"`
+	ReleasedAt  *time.Time `json:"released_at,omitempty"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Identifiers that link the submissions of different merchants
+const (
+	LinkTypeBVN     = "bvn"
+	LinkTypeCAC     = "cac"
+	LinkTypeTIN     = "tin"
+	LinkTypeAddress = "address"
+	LinkTypePhone   = "phone"
+	LinkTypeEmail   = "email"
+)
+
+// MerchantRelationship is an edge of the related-merchant graph: two submissions of different
+// merchants that share an identifier. Each edge is stored once, from the newer submission.
+type MerchantRelationship struct {
+	ID                  int       `json:"id"`
+	SubmissionID        int       `json:"submission_id"`
+	MerchantID          int       `json:"merchant_id"`
+	RelatedSubmissionID int       `json:"related_submission_id"`
+	RelatedMerchantID   int       `json:"related_merchant_id"`
+	LinkType            string    `json:"link_type"` // "bvn", "cac", "tin", "address", "phone", "email"
+	CreatedAt           time.Time `json:"created_at"`
+}
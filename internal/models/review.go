@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// KYC review statuses
+const (
+	ReviewStatusScheduled = "scheduled"
+	ReviewStatusOpen      = "open"
+	ReviewStatusEscalated = "escalated"
+	ReviewStatusCompleted = "completed"
+)
+
+// KYCReview is a periodic due diligence review of an approved merchant
+type KYCReview struct {
+	ID             int        `json:"id"`
+	MerchantID     int        `json:"merchant_id"`
+	SubmissionID   int        `json:"submission_id"`
+	RiskTier       string     `json:"risk_tier"`
+	Status         string     `json:"status"` // "scheduled", "open", "escalated", "completed"
+	LastReviewedAt time.Time  `json:"last_reviewed_at"`
+	DueAt          time.Time  `json:"due_at"`
+	OpenedAt       *time.Time `json:"opened_at,omitempty"`
+	EscalatedAt    *time.Time `json:"escalated_at,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	ReviewerID     *int       `json:"reviewer_id,omitempty"`
+	ReviewNotes    *string    `json:"review_notes,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// ApprovedMerchant identifies a merchant's latest approved KYC submission
+type ApprovedMerchant struct {
+	MerchantID   int
+	SubmissionID int
+	ApprovedAt   time.Time
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Risk tiers
+const (
+	RiskTierLow    = "low"
+	RiskTierMedium = "medium"
+	RiskTierHigh   = "high"
+)
+
+// RiskScore is a point-in-time risk assessment of a merchant
+type RiskScore struct {
+	ID         int          `json:"id"`
+	MerchantID int          `json:"merchant_id"`
+	Score      float64      `json:"score"` // 0-100
+	Tier       string       `json:"tier"`  // "low", "medium", "high"
+	Factors    []RiskFactor `json:"factors"`
+	Trigger    string       `json:"trigger"` // event that caused the recalculation
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// RiskFactor is a single weighted component of a risk score
+type RiskFactor struct {
+	Name         string  `json:"name"`
+	Value        string  `json:"value"`
+	Score        float64 `json:"score"` // 0-100
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+}
+
+// ScreeningResult is the outcome of screening a subject against a watchlist
+type ScreeningResult struct {
+	ID          int       `json:"id"`
+	MerchantID  int       `json:"merchant_id"`
+	PersonID    *int      `json:"person_id,omitempty"` // set when the subject is a declared KYC person
+	SubjectName string    `json:"subject_name"`
+	SubjectType string    `json:"subject_type"` // "business", "director", ...
+	ListType    string    `json:"list_type"`    // "sanctions", "pep", "adverse_media"
+	ListName    string    `json:"list_name,omitempty"`
+	MatchScore  float64   `json:"match_score"`
+	Status      string    `json:"status"` // "clear", "potential_match", "confirmed_match", "false_positive"
+	CreatedAt   time.Time `json:"created_at"`
+}
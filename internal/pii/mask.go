@@ -0,0 +1,47 @@
+// Package pii masks personal data in API responses and scrubs it from log output
+package pii
+
+import "strings"
+
+const maskRun = "****"
+
+// MaskBVN keeps the first and last three digits of a BVN, e.g. 221****890
+func MaskBVN(value string) string {
+	return maskMiddle(value, 3, 3)
+}
+
+// MaskPhone keeps the first and last three digits of a phone number, e.g. 080****678
+func MaskPhone(value string) string {
+	return maskMiddle(value, 3, 3)
+}
+
+// MaskTIN keeps the last four characters of a TIN
+func MaskTIN(value string) string {
+	return maskMiddle(value, 0, 4)
+}
+
+// MaskAccountNumber keeps the last four digits of a bank account number
+func MaskAccountNumber(value string) string {
+	return maskMiddle(value, 0, 4)
+}
+
+// MaskEmail keeps the first character of the local part and the domain, e.g. j****@example.com
+func MaskEmail(value string) string {
+	at := strings.LastIndex(value, "@")
+	if at <= 0 {
+		return maskMiddle(value, 0, 0)
+	}
+	return value[:1] + maskRun + value[at:]
+}
+
+// maskMiddle replaces everything but the first keepStart and last keepEnd characters. Values too
+// short to keep anything are masked completely so short inputs never leak in full.
+func maskMiddle(value string, keepStart, keepEnd int) string {
+	if value == "" {
+		return ""
+	}
+	if len(value) <= keepStart+keepEnd+2 {
+		return maskRun
+	}
+	return value[:keepStart] + maskRun + value[len(value)-keepEnd:]
+}
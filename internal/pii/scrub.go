@@ -0,0 +1,38 @@
+package pii
+
+import (
+	"io"
+	"regexp"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// TINs are eight digits, a dash and a four digit suffix
+	tinPattern = regexp.MustCompile(`\b\d{8}-\d{4}\b`)
+	// BVNs and local phone numbers are 11 digits, international Nigerian numbers 13
+	numberPattern = regexp.MustCompile(`\+?\b\d{11,13}\b`)
+)
+
+// Scrub masks BVNs, phone numbers, email addresses and TINs found in free text
+func Scrub(text string) string {
+	text = emailPattern.ReplaceAllStringFunc(text, MaskEmail)
+	text = tinPattern.ReplaceAllStringFunc(text, MaskTIN)
+	return numberPattern.ReplaceAllStringFunc(text, MaskBVN)
+}
+
+// ScrubWriter scrubs PII from everything written through it. Log packages issue one Write per
+// entry, so patterns are not split across writes.
+type ScrubWriter struct {
+	out io.Writer
+}
+
+func NewScrubWriter(out io.Writer) *ScrubWriter {
+	return &ScrubWriter{out: out}
+}
+
+func (w *ScrubWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.out, Scrub(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
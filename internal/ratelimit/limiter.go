@@ -0,0 +1,101 @@
+// Package ratelimit implements token bucket rate limits. A bucket holds up to Burst tokens and is
+// refilled at a steady rate; each request takes a token and is refused when the bucket is empty.
+//
+// RedisLimiter shares buckets between instances and falls back to a MemoryLimiter, which counts
+// per instance, while Redis is unavailable.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket's refill rate and size
+type Limit struct {
+	PerSecond float64
+	Burst     int
+}
+
+// PerMinute returns a limit refilled at perMinute tokens a minute
+func PerMinute(perMinute float64, burst int) Limit {
+	return Limit{PerSecond: perMinute / 60, Burst: burst}
+}
+
+// Result is the outcome of taking a token
+type Result struct {
+	Allowed    bool
+	Remaining  int           // whole tokens left in the bucket
+	RetryAfter time.Duration // when refused, how long until a token is available
+}
+
+// Limiter takes tokens from buckets identified by key
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// sweepInterval is how often MemoryLimiter drops buckets that have refilled completely
+const sweepInterval = time.Minute
+
+// MemoryLimiter keeps buckets in memory, so limits apply per instance. It is safe for concurrent
+// use.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time // when the bucket will be full again, after which it can be dropped
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{buckets: make(map[string]*bucket), lastSweep: time.Now(), now: time.Now}
+}
+
+func (l *MemoryLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*limit.PerSecond)
+	b.updated = now
+
+	result := Result{}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsDuration((1 - b.tokens) / limit.PerSecond)
+	}
+	result.Remaining = int(b.tokens)
+	b.full = now.Add(secondsDuration((float64(limit.Burst) - b.tokens) / limit.PerSecond))
+	return result, nil
+}
+
+// sweep drops the buckets that are full again; a new bucket starts full, so nothing is lost
+func (l *MemoryLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if !now.Before(b.full) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
@@ -0,0 +1,158 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// step takes a token after advancing the clock
+type step struct {
+	advance    time.Duration
+	allowed    bool
+	remaining  int
+	retryAfter time.Duration
+}
+
+// newClockedLimiter returns a MemoryLimiter that reads the time from *now
+func newClockedLimiter(now *time.Time) *MemoryLimiter {
+	l := NewMemoryLimiter()
+	l.now = func() time.Time { return *now }
+	l.lastSweep = *now
+	return l
+}
+
+func TestMemoryLimiter(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit Limit
+		steps []step
+	}{
+		{"burst then refused", Limit{PerSecond: 1, Burst: 2}, []step{
+			{0, true, 1, 0},
+			{0, true, 0, 0},
+			{0, false, 0, time.Second},
+			{500 * time.Millisecond, false, 0, 500 * time.Millisecond},
+			{500 * time.Millisecond, true, 0, 0},
+		}},
+		{"refill is capped at the burst", Limit{PerSecond: 1, Burst: 2}, []step{
+			{0, true, 1, 0},
+			{time.Hour, true, 1, 0},
+			{0, true, 0, 0},
+			{0, false, 0, time.Second},
+		}},
+		{"per minute", PerMinute(6, 1), []step{
+			{0, true, 0, 0},
+			{time.Second, false, 0, 9 * time.Second},
+			{9 * time.Second, true, 0, 0},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+			l := newClockedLimiter(&now)
+
+			for i, s := range tt.steps {
+				now = now.Add(s.advance)
+				got, err := l.Allow(context.Background(), "merchant:42", tt.limit)
+				if err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+				want := Result{Allowed: s.allowed, Remaining: s.remaining, RetryAfter: s.retryAfter}
+				if got != want {
+					t.Errorf("step %d: got %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryLimiterKeysAndSweep(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	l := newClockedLimiter(&now)
+	limit := Limit{PerSecond: 1, Burst: 1}
+
+	for _, key := range []string{"merchant:42", "merchant:43"} {
+		if got, _ := l.Allow(context.Background(), key, limit); !got.Allowed {
+			t.Errorf("first request for %s was refused", key)
+		}
+	}
+	if got, _ := l.Allow(context.Background(), "merchant:42", limit); got.Allowed {
+		t.Errorf("second request for merchant:42 was allowed")
+	}
+
+	// Buckets that refilled are dropped on the next sweep
+	now = now.Add(sweepInterval)
+	if got, _ := l.Allow(context.Background(), "merchant:44", limit); !got.Allowed {
+		t.Errorf("first request for merchant:44 was refused")
+	}
+	if _, ok := l.buckets["merchant:42"]; ok {
+		t.Errorf("full bucket merchant:42 was not swept")
+	}
+	if _, ok := l.buckets["merchant:44"]; !ok {
+		t.Errorf("bucket merchant:44 was swept while in use")
+	}
+}
+
+func TestRedisLimiterFallback(t *testing.T) {
+	// Nothing listens on port 1, so every call fails
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	defer client.Close()
+
+	l := NewRedisLimiter(client, NewMemoryLimiter(), 50*time.Millisecond)
+	limit := Limit{PerSecond: 0.001, Burst: 2}
+
+	want := []bool{true, true, false}
+	for i, allowed := range want {
+		got, err := l.Allow(context.Background(), "merchant:42", limit)
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		if got.Allowed != allowed {
+			t.Errorf("request %d: allowed = %v, want %v from the fallback bucket", i, got.Allowed, allowed)
+		}
+	}
+	if l.downUntil.Load() <= time.Now().UnixNano() {
+		t.Errorf("limiter is not backing off from the failed Redis")
+	}
+}
+
+// TestRedisLimiter runs the token bucket script against the Redis in TEST_REDIS_ADDR
+func TestRedisLimiter(t *testing.T) {
+	addr := os.Getenv("TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("TEST_REDIS_ADDR is not set")
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+
+	ctx := context.Background()
+	key := fmt.Sprintf("test:%d", time.Now().UnixNano())
+	defer client.Del(ctx, "ratelimit:"+key)
+
+	l := NewRedisLimiter(client, NewMemoryLimiter(), time.Second)
+	limit := Limit{PerSecond: 10, Burst: 2}
+
+	for i, want := range []Result{{Allowed: true, Remaining: 1}, {Allowed: true, Remaining: 0}} {
+		got, err := l.Allow(ctx, key, limit)
+		if err != nil || got != want {
+			t.Fatalf("request %d: got %+v, %v, want %+v", i, got, err, want)
+		}
+	}
+	refused, err := l.Allow(ctx, key, limit)
+	if err != nil || refused.Allowed || refused.RetryAfter <= 0 || refused.RetryAfter > 100*time.Millisecond {
+		t.Fatalf("request over the burst: got %+v, %v, want refused with Retry-After up to 100ms", refused, err)
+	}
+
+	time.Sleep(refused.RetryAfter + 20*time.Millisecond)
+	if got, err := l.Allow(ctx, key, limit); err != nil || !got.Allowed {
+		t.Errorf("request after Retry-After: got %+v, %v, want allowed", got, err)
+	}
+	if l.downUntil.Load() != 0 {
+		t.Errorf("limiter fell back although Redis is up")
+	}
+}
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/redis/go-redis/v9"
+)
+
+// backoff is how long the fallback limiter is used after a failed Redis call
+const backoff = 5 * time.Second
+
+// tokenBucketScript refills and takes a token from the bucket in KEYS[1], a hash of the token count
+// and the time it was last updated. Time is read from Redis so instances with skewed clocks agree.
+// ARGV[1] is the refill rate in tokens per millisecond and ARGV[2] the bucket size. It returns
+// whether a token was taken, the whole tokens left and, when refused, the milliseconds until one
+// is available.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1]) or burst
+local updated = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - updated) * rate)
+
+local allowed, retry = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate) + 1000)
+return {allowed, math.floor(tokens), retry}
+`)
+
+// RedisLimiter keeps buckets in Redis, so limits apply across instances. While Redis is
+// unavailable requests are counted by the fallback limiter instead. It is safe for concurrent use.
+type RedisLimiter struct {
+	client    *redis.Client
+	fallback  Limiter
+	timeout   time.Duration
+	downUntil atomic.Int64 // unix nanoseconds until which the fallback is used
+}
+
+// NewRedisLimiter returns a limiter on client whose calls are bounded by timeout
+func NewRedisLimiter(client *redis.Client, fallback Limiter, timeout time.Duration) *RedisLimiter {
+	return &RedisLimiter{client: client, fallback: fallback, timeout: timeout}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if time.Now().UnixNano() < l.downUntil.Load() {
+		return l.fallback.Allow(ctx, key, limit)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+	values, err := tokenBucketScript.Run(callCtx, l.client, []string{"ratelimit:" + key},
+		strconv.FormatFloat(limit.PerSecond/1000, 'g', -1, 64), limit.Burst).Int64Slice()
+
+	if err != nil {
+		logging.FromContext(ctx).Warn("rate limiter falling back to per-instance limits", "error", err, "backoff", backoff)
+		l.downUntil.Store(time.Now().Add(backoff).UnixNano())
+		return l.fallback.Allow(ctx, key, limit)
+	}
+
+	if len(values) != 3 {
+		return Result{}, errors.New("ratelimit: unexpected reply from Redis")
+	}
+	return Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/metrics"
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// memoryComplianceRepository implements ComplianceRepository in memory, for tests and local
+// development. It is safe for concurrent use.
+type memoryComplianceRepository struct {
+	mu         sync.RWMutex
+	records    map[int]models.KYCRecord
+	alerts     map[int]models.TransactionMonitoringAlert
+	lastRecord int
+	lastAlert  int
+	now        func() time.Time
+}
+
+// NewMemoryComplianceRepository creates an empty in-memory repository
+func NewMemoryComplianceRepository() ComplianceRepository {
+	return &memoryComplianceRepository{
+		records: make(map[int]models.KYCRecord),
+		alerts:  make(map[int]models.TransactionMonitoringAlert),
+		now:     storeNow,
+	}
+}
+
+func (r *memoryComplianceRepository) CreateKYCRecord(record *models.KYCRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastRecord++
+	now := r.now()
+	record.ID = r.lastRecord
+	record.CreatedAt, record.UpdatedAt = now, now
+	r.records[record.ID] = *record
+	return nil
+}
+
+func (r *memoryComplianceRepository) GetKYCRecordByID(id int) (*models.KYCRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	record, ok := r.records[id]
+	if !ok {
+		return nil, nil // Record not found
+	}
+	return &record, nil
+}
+
+func (r *memoryComplianceRepository) UpdateKYCRecord(record *models.KYCRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.records[record.ID]
+	if !ok {
+		return fmt.Errorf("kyc record not found")
+	}
+	record.UpdatedAt = r.now()
+	updated := *record
+	updated.CreatedAt = stored.CreatedAt
+	r.records[record.ID] = updated
+	return nil
+}
+
+func (r *memoryComplianceRepository) CreateTransactionMonitoringAlert(alert *models.TransactionMonitoringAlert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastAlert++
+	now := r.now()
+	alert.ID = r.lastAlert
+	alert.CreatedAt, alert.UpdatedAt = now, now
+	r.alerts[alert.ID] = *alert
+	metrics.MonitoringAlerts.WithLabelValues(alert.RuleTriggered, alert.Severity).Inc()
+	return nil
+}
+
+func (r *memoryComplianceRepository) GetTransactionMonitoringAlertByID(id int) (*models.TransactionMonitoringAlert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	alert, ok := r.alerts[id]
+	if !ok {
+		return nil, nil // Alert not found
+	}
+	return &alert, nil
+}
+
+func (r *memoryComplianceRepository) UpdateTransactionMonitoringAlert(alert *models.TransactionMonitoringAlert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.alerts[alert.ID]
+	if !ok {
+		return fmt.Errorf("transaction monitoring alert not found")
+	}
+	alert.UpdatedAt = r.now()
+	updated := *alert
+	updated.CreatedAt = stored.CreatedAt
+	r.alerts[alert.ID] = updated
+	return nil
+}
+
+func (r *memoryComplianceRepository) CountAlertsBySeverity(userID int, since time.Time) (map[string]int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, alert := range r.alerts {
+		if alert.UserID == userID && !alert.CreatedAt.Before(since) && alert.Status != "false_positive" {
+			counts[alert.Severity]++
+		}
+	}
+	return counts, nil
+}
@@ -3,8 +3,10 @@ package repositories
 import (
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"time"
 
+	"github.com/kodra-pay/compliance-service/internal/metrics"
 	"github.com/kodra-pay/compliance-service/internal/models"
 	_ "github.com/lib/pq" // PostgreSQL driver
 )
@@ -17,6 +19,7 @@ type ComplianceRepository interface {
 	CreateTransactionMonitoringAlert(alert *models.TransactionMonitoringAlert) error
 	GetTransactionMonitoringAlertByID(id int) (*models.TransactionMonitoringAlert, error)
 	UpdateTransactionMonitoringAlert(alert *models.TransactionMonitoringAlert) error
+	CountAlertsBySeverity(userID int, since time.Time) (map[string]int, error)
 }
 
 // postgresComplianceRepository implements ComplianceRepository for PostgreSQL
@@ -30,13 +33,9 @@ func NewPostgresComplianceRepository(db *sql.DB) ComplianceRepository {
 }
 
 func (r *postgresComplianceRepository) CreateKYCRecord(record *models.KYCRecord) error {
-	query := `INSERT INTO kyc_records (user_id, status, document_type, document_id, issue_date, expiry_date, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
-	var id int
-	err := r.db.QueryRow(query, record.UserID, record.Status, record.DocumentType, record.DocumentID, record.IssueDate, record.ExpiryDate, time.Now(), time.Now()).Scan(&id)
-	if err == nil {
-		record.ID = id
-	}
-	return err
+	query := `INSERT INTO kyc_records (user_id, status, document_type, document_id, issue_date, expiry_date, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, updated_at`
+	now := time.Now()
+	return r.db.QueryRow(query, record.UserID, record.Status, record.DocumentType, record.DocumentID, record.IssueDate, record.ExpiryDate, now, now).Scan(&record.ID, &record.CreatedAt, &record.UpdatedAt)
 }
 
 func (r *postgresComplianceRepository) GetKYCRecordByID(id int) (*models.KYCRecord, error) {
@@ -50,19 +49,22 @@ func (r *postgresComplianceRepository) GetKYCRecordByID(id int) (*models.KYCReco
 }
 
 func (r *postgresComplianceRepository) UpdateKYCRecord(record *models.KYCRecord) error {
-	query := `UPDATE kyc_records SET user_id = $2, status = $3, document_type = $4, document_id = $5, issue_date = $6, expiry_date = $7, updated_at = $8 WHERE id = $1`
-	_, err := r.db.Exec(query, record.ID, record.UserID, record.Status, record.DocumentType, record.DocumentID, record.IssueDate, record.ExpiryDate, time.Now())
+	query := `UPDATE kyc_records SET user_id = $2, status = $3, document_type = $4, document_id = $5, issue_date = $6, expiry_date = $7, updated_at = $8 WHERE id = $1 RETURNING updated_at`
+	err := r.db.QueryRow(query, record.ID, record.UserID, record.Status, record.DocumentType, record.DocumentID, record.IssueDate, record.ExpiryDate, time.Now()).Scan(&record.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("kyc record not found")
+	}
 	return err
 }
 
 func (r *postgresComplianceRepository) CreateTransactionMonitoringAlert(alert *models.TransactionMonitoringAlert) error {
-	query := `INSERT INTO transaction_monitoring_alerts (transaction_id, user_id, rule_triggered, severity, status, description, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
-	var id int
-	err := r.db.QueryRow(query, alert.TransactionID, alert.UserID, alert.RuleTriggered, alert.Severity, alert.Status, alert.Description, time.Now(), time.Now()).Scan(&id)
-	if err == nil {
-		alert.ID = id
+	query := `INSERT INTO transaction_monitoring_alerts (transaction_id, user_id, rule_triggered, severity, status, description, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, updated_at`
+	now := time.Now()
+	if err := r.db.QueryRow(query, alert.TransactionID, alert.UserID, alert.RuleTriggered, alert.Severity, alert.Status, alert.Description, now, now).Scan(&alert.ID, &alert.CreatedAt, &alert.UpdatedAt); err != nil {
+		return err
 	}
-	return err
+	metrics.MonitoringAlerts.WithLabelValues(alert.RuleTriggered, alert.Severity).Inc()
+	return nil
 }
 
 func (r *postgresComplianceRepository) GetTransactionMonitoringAlertByID(id int) (*models.TransactionMonitoringAlert, error) {
@@ -76,11 +78,34 @@ func (r *postgresComplianceRepository) GetTransactionMonitoringAlertByID(id int)
 }
 
 func (r *postgresComplianceRepository) UpdateTransactionMonitoringAlert(alert *models.TransactionMonitoringAlert) error {
-	query := `UPDATE transaction_monitoring_alerts SET transaction_id = $2, user_id = $3, rule_triggered = $4, severity = $5, status = $6, description = $7, updated_at = $8 WHERE id = $1`
-	_, err := r.db.Exec(query, alert.ID, alert.TransactionID, alert.UserID, alert.RuleTriggered, alert.Severity, alert.Status, alert.Description, time.Now())
+	query := `UPDATE transaction_monitoring_alerts SET transaction_id = $2, user_id = $3, rule_triggered = $4, severity = $5, status = $6, description = $7, updated_at = $8 WHERE id = $1 RETURNING updated_at`
+	err := r.db.QueryRow(query, alert.ID, alert.TransactionID, alert.UserID, alert.RuleTriggered, alert.Severity, alert.Status, alert.Description, time.Now()).Scan(&alert.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("transaction monitoring alert not found")
+	}
 	return err
 }
 
+func (r *postgresComplianceRepository) CountAlertsBySeverity(userID int, since time.Time) (map[string]int, error) {
+	query := `SELECT severity, COUNT(*) FROM transaction_monitoring_alerts WHERE user_id = $1 AND created_at >= $2 AND status <> 'false_positive' GROUP BY severity`
+	rows, err := r.db.Query(query, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var severity string
+		var count int
+		if err := rows.Scan(&severity, &count); err != nil {
+			return nil, err
+		}
+		counts[severity] = count
+	}
+	return counts, rows.Err()
+}
+
 // InitDB initializes the database connection
 func InitDB(databaseURL string) (*sql.DB, error) {
 	db, err := sql.Open("postgres", databaseURL)
@@ -92,6 +117,6 @@ func InitDB(databaseURL string) (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	fmt.Println("Successfully connected to PostgreSQL!")
+	slog.Info("connected to PostgreSQL")
 	return db, nil
 }
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+type CTRRepository struct {
+	db *sql.DB
+}
+
+func NewCTRRepository(db *sql.DB) *CTRRepository {
+	return &CTRRepository{db: db}
+}
+
+// Create stores a CTR batch together with its entries in a single transaction
+func (r *CTRRepository) Create(ctx context.Context, batch *models.CTRBatch) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO ctr_batches (
+			report_date, status, individual_threshold, corporate_threshold, currency,
+			record_count, total_amount, goaml_xml, csv_payload, generated_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, generated_at
+	`
+
+	if err := tx.QueryRowContext(ctx, query,
+		batch.ReportDate,
+		batch.Status,
+		batch.IndividualThreshold,
+		batch.CorporateThreshold,
+		batch.Currency,
+		batch.RecordCount,
+		batch.TotalAmount,
+		batch.GoAMLXML,
+		batch.CSVPayload,
+		batch.GeneratedBy,
+	).Scan(&batch.ID, &batch.GeneratedAt); err != nil {
+		return fmt.Errorf("failed to insert ctr batch: %w", err)
+	}
+
+	entryQuery := `
+		INSERT INTO ctr_batch_entries (
+			batch_id, customer_id, customer_type, customer_name, transaction_count, total_amount, threshold
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	for i := range batch.Entries {
+		entry := &batch.Entries[i]
+		entry.BatchID = batch.ID
+		if err := tx.QueryRowContext(ctx, entryQuery,
+			entry.BatchID,
+			entry.CustomerID,
+			entry.CustomerType,
+			entry.CustomerName,
+			entry.TransactionCount,
+			entry.TotalAmount,
+			entry.Threshold,
+		).Scan(&entry.ID); err != nil {
+			return fmt.Errorf("failed to insert ctr batch entry: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetByID retrieves a CTR batch and its entries
+func (r *CTRRepository) GetByID(ctx context.Context, id int) (*models.CTRBatch, error) {
+	query := `
+		SELECT id, report_date, status, individual_threshold, corporate_threshold, currency,
+			record_count, total_amount, goaml_xml, csv_payload, generated_by, generated_at
+		FROM ctr_batches
+		WHERE id = $1
+	`
+
+	var batch models.CTRBatch
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&batch.ID,
+		&batch.ReportDate,
+		&batch.Status,
+		&batch.IndividualThreshold,
+		&batch.CorporateThreshold,
+		&batch.Currency,
+		&batch.RecordCount,
+		&batch.TotalAmount,
+		&batch.GoAMLXML,
+		&batch.CSVPayload,
+		&batch.GeneratedBy,
+		&batch.GeneratedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := r.listEntries(ctx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	batch.Entries = entries
+
+	return &batch, nil
+}
+
+// ExistsForDate reports whether a CTR batch has already been generated for the given day
+func (r *CTRRepository) ExistsForDate(ctx context.Context, reportDate time.Time) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM ctr_batches WHERE report_date = $1)`
+	if err := r.db.QueryRowContext(ctx, query, reportDate).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (r *CTRRepository) listEntries(ctx context.Context, batchID int) ([]models.CTRBatchEntry, error) {
+	query := `
+		SELECT id, batch_id, customer_id, customer_type, COALESCE(customer_name, ''),
+			transaction_count, total_amount, threshold
+		FROM ctr_batch_entries
+		WHERE batch_id = $1
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.CTRBatchEntry
+	for rows.Next() {
+		var entry models.CTRBatchEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.BatchID,
+			&entry.CustomerID,
+			&entry.CustomerType,
+			&entry.CustomerName,
+			&entry.TransactionCount,
+			&entry.TotalAmount,
+			&entry.Threshold,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,210 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+type EDDRepository struct {
+	db *sql.DB
+}
+
+func NewEDDRepository(db *sql.DB) *EDDRepository {
+	return &EDDRepository{db: db}
+}
+
+// Create stores a checklist with its items in a single transaction
+func (r *EDDRepository) Create(ctx context.Context, checklist *models.EDDChecklist) error {
+	reasonsJSON, err := json.Marshal(checklist.Reasons)
+	if err != nil {
+		return fmt.Errorf("failed to marshal edd reasons: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO edd_checklists (submission_id, merchant_id, reasons, status)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`
+	if err := tx.QueryRowContext(ctx, query,
+		checklist.SubmissionID,
+		checklist.MerchantID,
+		reasonsJSON,
+		checklist.Status,
+	).Scan(&checklist.ID, &checklist.CreatedAt, &checklist.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to insert edd checklist: %w", err)
+	}
+
+	itemQuery := `
+		INSERT INTO edd_items (checklist_id, item_type, status)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+	for i := range checklist.Items {
+		item := &checklist.Items[i]
+		item.ChecklistID = checklist.ID
+		if err := tx.QueryRowContext(ctx, itemQuery, item.ChecklistID, item.ItemType, item.Status).Scan(&item.ID); err != nil {
+			return fmt.Errorf("failed to insert edd item: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetBySubmission retrieves the checklist and items for a KYC submission
+func (r *EDDRepository) GetBySubmission(ctx context.Context, submissionID int) (*models.EDDChecklist, error) {
+	query := `
+		SELECT id, submission_id, merchant_id, reasons, status, signed_off_by, signed_off_role,
+			signoff_notes, signed_off_at, created_at, updated_at
+		FROM edd_checklists
+		WHERE submission_id = $1
+	`
+
+	var checklist models.EDDChecklist
+	var reasonsJSON []byte
+	var signedOffBy sql.NullInt32
+
+	err := r.db.QueryRowContext(ctx, query, submissionID).Scan(
+		&checklist.ID,
+		&checklist.SubmissionID,
+		&checklist.MerchantID,
+		&reasonsJSON,
+		&checklist.Status,
+		&signedOffBy,
+		&checklist.SignedOffRole,
+		&checklist.SignOffNotes,
+		&checklist.SignedOffAt,
+		&checklist.CreatedAt,
+		&checklist.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(reasonsJSON, &checklist.Reasons); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal edd reasons: %w", err)
+	}
+	if signedOffBy.Valid {
+		val := int(signedOffBy.Int32)
+		checklist.SignedOffBy = &val
+	}
+
+	items, err := r.listItems(ctx, checklist.ID)
+	if err != nil {
+		return nil, err
+	}
+	checklist.Items = items
+
+	return &checklist, nil
+}
+
+// UpdateItem persists the completion state of an item
+func (r *EDDRepository) UpdateItem(ctx context.Context, item *models.EDDItem) error {
+	query := `
+		UPDATE edd_items
+		SET status = $1, evidence = $2, notes = $3, completed_by = $4, completed_at = $5, updated_at = NOW()
+		WHERE id = $6
+	`
+
+	result, err := r.db.ExecContext(ctx, query, item.Status, item.Evidence, item.Notes, item.CompletedBy, item.CompletedAt, item.ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("edd item %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// UpdateChecklist persists the status and sign-off of a checklist
+func (r *EDDRepository) UpdateChecklist(ctx context.Context, checklist *models.EDDChecklist) error {
+	query := `
+		UPDATE edd_checklists
+		SET status = $1, signed_off_by = $2, signed_off_role = $3, signoff_notes = $4,
+			signed_off_at = $5, updated_at = NOW()
+		WHERE id = $6
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		checklist.Status,
+		checklist.SignedOffBy,
+		checklist.SignedOffRole,
+		checklist.SignOffNotes,
+		checklist.SignedOffAt,
+		checklist.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("edd checklist %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *EDDRepository) listItems(ctx context.Context, checklistID int) ([]models.EDDItem, error) {
+	query := `
+		SELECT id, checklist_id, item_type, status, evidence, notes, completed_by, completed_at
+		FROM edd_items
+		WHERE checklist_id = $1
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, checklistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []models.EDDItem
+	for rows.Next() {
+		var item models.EDDItem
+		var completedBy sql.NullInt32
+
+		if err := rows.Scan(
+			&item.ID,
+			&item.ChecklistID,
+			&item.ItemType,
+			&item.Status,
+			&item.Evidence,
+			&item.Notes,
+			&completedBy,
+			&item.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if completedBy.Valid {
+			val := int(completedBy.Int32)
+			item.CompletedBy = &val
+		}
+
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
@@ -0,0 +1,10 @@
+package repositories
+
+import "errors"
+
+// ErrNotFound is returned, wrapped with the kind of record, by updates whose record does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is returned, wrapped with the kind of record, by updates that lost a race with a
+// concurrent request that changed the record first
+var ErrConflict = errors.New("was changed by another request")
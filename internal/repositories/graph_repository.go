@@ -0,0 +1,372 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/lib/pq"
+)
+
+// GraphRepository persists the entity graph used in investigations
+type GraphRepository struct {
+	db *sql.DB
+}
+
+func NewGraphRepository(db *sql.DB) *GraphRepository {
+	return &GraphRepository{db: db}
+}
+
+// GraphAlert is a transaction monitoring alert as read by the graph sync
+type GraphAlert struct {
+	ID            int
+	UserID        int
+	TransactionID int
+	RuleTriggered string
+	Severity      string
+	Status        string
+	UpdatedAt     time.Time
+}
+
+// GraphCounterparty is a monitored transaction with a counterparty account as read by the graph sync
+type GraphCounterparty struct {
+	ID                  int
+	CustomerID          int
+	CounterpartyName    string
+	CounterpartyAccount string
+	Direction           string
+	CreatedAt           time.Time
+}
+
+// GraphCursor is the incremental sync position of a source table
+type GraphCursor struct {
+	Until  time.Time
+	LastID int
+}
+
+// UpsertNode creates a node or refreshes its label and properties, setting node.ID
+func (r *GraphRepository) UpsertNode(ctx context.Context, node *models.GraphNode) error {
+	props, err := json.Marshal(node.Properties)
+	if err != nil {
+		return fmt.Errorf("failed to marshal node properties: %w", err)
+	}
+
+	query := `
+		INSERT INTO graph_nodes (node_type, node_key, label, properties)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (node_type, node_key)
+		DO UPDATE SET label = EXCLUDED.label, properties = EXCLUDED.properties, updated_at = NOW()
+		RETURNING id, updated_at
+	`
+
+	return r.db.QueryRowContext(ctx, query, node.Type, node.Key, node.Label, props).Scan(&node.ID, &node.UpdatedAt)
+}
+
+// EnsureNode creates a node if it does not exist yet, leaving an existing node's label and
+// properties untouched, and sets node.ID
+func (r *GraphRepository) EnsureNode(ctx context.Context, node *models.GraphNode) error {
+	props, err := json.Marshal(node.Properties)
+	if err != nil {
+		return fmt.Errorf("failed to marshal node properties: %w", err)
+	}
+
+	query := `
+		INSERT INTO graph_nodes (node_type, node_key, label, properties)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (node_type, node_key) DO UPDATE SET node_key = EXCLUDED.node_key
+		RETURNING id, updated_at
+	`
+
+	return r.db.QueryRowContext(ctx, query, node.Type, node.Key, node.Label, props).Scan(&node.ID, &node.UpdatedAt)
+}
+
+// UpsertEdge creates an edge or refreshes its properties
+func (r *GraphRepository) UpsertEdge(ctx context.Context, edge *models.GraphEdge) error {
+	props, err := json.Marshal(edge.Properties)
+	if err != nil {
+		return fmt.Errorf("failed to marshal edge properties: %w", err)
+	}
+
+	query := `
+		INSERT INTO graph_edges (from_node_id, to_node_id, edge_type, source, properties)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (from_node_id, to_node_id, edge_type, source)
+		DO UPDATE SET properties = EXCLUDED.properties
+		RETURNING id
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		edge.FromNodeID, edge.ToNodeID, edge.Type, edge.Source, props,
+	).Scan(&edge.ID)
+}
+
+// DeleteEdgesBySource removes the edges derived from a source row before it is re-synced
+func (r *GraphRepository) DeleteEdgesBySource(ctx context.Context, source string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM graph_edges WHERE source = $1`, source)
+	return err
+}
+
+// DeleteOrphanNodes removes nodes that no longer have any edges, e.g. after a submission was
+// anonymised. It returns the number of nodes removed.
+func (r *GraphRepository) DeleteOrphanNodes(ctx context.Context) (int, error) {
+	query := `
+		DELETE FROM graph_nodes n
+		WHERE NOT EXISTS (SELECT 1 FROM graph_edges e WHERE e.from_node_id = n.id OR e.to_node_id = n.id)
+	`
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	return int(rows), err
+}
+
+// GetNode retrieves a node by type and key
+func (r *GraphRepository) GetNode(ctx context.Context, nodeType, key string) (*models.GraphNode, error) {
+	query := `
+		SELECT id, node_type, node_key, label, properties, updated_at
+		FROM graph_nodes
+		WHERE node_type = $1 AND node_key = $2
+	`
+	nodes, err := r.queryNodes(ctx, query, nodeType, key)
+	if err != nil || len(nodes) == 0 {
+		return nil, err
+	}
+	return &nodes[0], nil
+}
+
+// Neighbourhood returns the nodes within depth hops of the root, nearest first and at most limit
+// of them, together with the edges between the returned nodes. Edges are walked in both
+// directions.
+func (r *GraphRepository) Neighbourhood(ctx context.Context, rootID, depth, limit int) ([]models.GraphNode, []models.GraphEdge, error) {
+	query := `
+		WITH RECURSIVE walk (node_id, depth) AS (
+			SELECT $1::BIGINT, 0
+			UNION
+			SELECT CASE WHEN e.from_node_id = w.node_id THEN e.to_node_id ELSE e.from_node_id END, w.depth + 1
+			FROM walk w
+			JOIN graph_edges e ON e.from_node_id = w.node_id OR e.to_node_id = w.node_id
+			WHERE w.depth < $2
+		),
+		reached AS (
+			SELECT node_id, MIN(depth) AS depth FROM walk GROUP BY node_id
+		)
+		SELECT n.id, n.node_type, n.node_key, n.label, n.properties, n.updated_at, r.depth
+		FROM reached r
+		JOIN graph_nodes n ON n.id = r.node_id
+		ORDER BY r.depth, n.id
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, rootID, depth, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var nodes []models.GraphNode
+	var ids []int
+	for rows.Next() {
+		var node models.GraphNode
+		var props []byte
+		if err := rows.Scan(&node.ID, &node.Type, &node.Key, &node.Label, &props, &node.UpdatedAt, &node.Depth); err != nil {
+			return nil, nil, err
+		}
+		if err := json.Unmarshal(props, &node.Properties); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal node properties: %w", err)
+		}
+		nodes = append(nodes, node)
+		ids = append(ids, node.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	edges, err := r.edgesBetween(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nodes, edges, nil
+}
+
+func (r *GraphRepository) edgesBetween(ctx context.Context, ids []int) ([]models.GraphEdge, error) {
+	query := `
+		SELECT id, from_node_id, to_node_id, edge_type, source, properties
+		FROM graph_edges
+		WHERE from_node_id = ANY($1) AND to_node_id = ANY($1)
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edges []models.GraphEdge
+	for rows.Next() {
+		var edge models.GraphEdge
+		var props []byte
+		if err := rows.Scan(&edge.ID, &edge.FromNodeID, &edge.ToNodeID, &edge.Type, &edge.Source, &props); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(props, &edge.Properties); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal edge properties: %w", err)
+		}
+		edges = append(edges, edge)
+	}
+
+	return edges, rows.Err()
+}
+
+func (r *GraphRepository) queryNodes(ctx context.Context, query string, args ...interface{}) ([]models.GraphNode, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodes []models.GraphNode
+	for rows.Next() {
+		var node models.GraphNode
+		var props []byte
+		if err := rows.Scan(&node.ID, &node.Type, &node.Key, &node.Label, &props, &node.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(props, &node.Properties); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal node properties: %w", err)
+		}
+		nodes = append(nodes, node)
+	}
+
+	return nodes, rows.Err()
+}
+
+// GetCursor returns the sync position of a source, or the zero cursor if it was never synced
+func (r *GraphRepository) GetCursor(ctx context.Context, source string) (GraphCursor, error) {
+	var cursor GraphCursor
+	err := r.db.QueryRowContext(ctx,
+		`SELECT synced_until, last_id FROM graph_sync_state WHERE source = $1`, source,
+	).Scan(&cursor.Until, &cursor.LastID)
+	if err == sql.ErrNoRows {
+		return GraphCursor{}, nil
+	}
+	return cursor, err
+}
+
+// SaveCursor stores the sync position of a source
+func (r *GraphRepository) SaveCursor(ctx context.Context, source string, cursor GraphCursor) error {
+	query := `
+		INSERT INTO graph_sync_state (source, synced_until, last_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (source) DO UPDATE SET synced_until = EXCLUDED.synced_until, last_id = EXCLUDED.last_id
+	`
+	_, err := r.db.ExecContext(ctx, query, source, cursor.Until, cursor.LastID)
+	return err
+}
+
+// ListChangedSubmissions returns the IDs and update times of KYC submissions changed after the cursor
+func (r *GraphRepository) ListChangedSubmissions(ctx context.Context, after GraphCursor, limit int) ([]int, []time.Time, error) {
+	query := `
+		SELECT id, updated_at FROM kyc_submissions
+		WHERE (updated_at, id) > ($1, $2)
+		ORDER BY updated_at, id
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, after.Until, after.LastID, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	var times []time.Time
+	for rows.Next() {
+		var id int
+		var updatedAt time.Time
+		if err := rows.Scan(&id, &updatedAt); err != nil {
+			return nil, nil, err
+		}
+		ids = append(ids, id)
+		times = append(times, updatedAt)
+	}
+
+	return ids, times, rows.Err()
+}
+
+// ListChangedAlerts returns transaction monitoring alerts changed after the cursor
+func (r *GraphRepository) ListChangedAlerts(ctx context.Context, after GraphCursor, limit int) ([]GraphAlert, error) {
+	query := `
+		SELECT id, user_id, transaction_id, rule_triggered, severity, status, updated_at
+		FROM transaction_monitoring_alerts
+		WHERE (updated_at, id) > ($1, $2)
+		ORDER BY updated_at, id
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, after.Until, after.LastID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []GraphAlert
+	for rows.Next() {
+		var alert GraphAlert
+		if err := rows.Scan(
+			&alert.ID,
+			&alert.UserID,
+			&alert.TransactionID,
+			&alert.RuleTriggered,
+			&alert.Severity,
+			&alert.Status,
+			&alert.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, rows.Err()
+}
+
+// ListNewCounterparties returns monitored transactions with a counterparty account recorded after
+// the cursor
+func (r *GraphRepository) ListNewCounterparties(ctx context.Context, after GraphCursor, limit int) ([]GraphCounterparty, error) {
+	query := `
+		SELECT id, customer_id, COALESCE(counterparty_name, ''), counterparty_account, direction, created_at
+		FROM monitored_transactions
+		WHERE (created_at, id) > ($1, $2) AND COALESCE(counterparty_account, '') <> ''
+		ORDER BY created_at, id
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, after.Until, after.LastID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counterparties []GraphCounterparty
+	for rows.Next() {
+		var cp GraphCounterparty
+		if err := rows.Scan(
+			&cp.ID,
+			&cp.CustomerID,
+			&cp.CounterpartyName,
+			&cp.CounterpartyAccount,
+			&cp.Direction,
+			&cp.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		counterparties = append(counterparties, cp)
+	}
+
+	return counterparties, rows.Err()
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// MemoryIdempotencyStore is an IdempotencyStore kept in memory, for tests and local development.
+// It is safe for concurrent use.
+type MemoryIdempotencyStore struct {
+	mu       sync.Mutex
+	requests map[string]models.IdempotentRequest
+	now      func() time.Time
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{requests: make(map[string]models.IdempotentRequest), now: storeNow}
+}
+
+func (s *MemoryIdempotencyStore) Reserve(ctx context.Context, key, requestHash string, lockTimeout time.Duration) (*models.IdempotentRequest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if existing, ok := s.requests[key]; ok && existing.ExpiresAt.After(now) {
+		existing.Body = append([]byte(nil), existing.Body...)
+		return &existing, nil
+	}
+	s.requests[key] = models.IdempotentRequest{Key: key, RequestHash: requestHash, ExpiresAt: now.Add(lockTimeout)}
+	return nil, nil
+}
+
+func (s *MemoryIdempotencyStore) Complete(ctx context.Context, request *models.IdempotentRequest, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	existing, ok := s.requests[request.Key]
+	if !ok || existing.RequestHash != request.RequestHash || existing.Completed || !existing.ExpiresAt.After(now) {
+		return nil
+	}
+	s.requests[request.Key] = models.IdempotentRequest{
+		Key:         request.Key,
+		RequestHash: request.RequestHash,
+		Completed:   true,
+		StatusCode:  request.StatusCode,
+		ContentType: request.ContentType,
+		Body:        append([]byte(nil), request.Body...),
+		ExpiresAt:   now.Add(ttl),
+	}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Release(ctx context.Context, key, requestHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.requests[key]; ok && existing.RequestHash == requestHash && !existing.Completed {
+		delete(s.requests, key)
+	}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) PurgeExpired(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	purged := 0
+	for key, request := range s.requests {
+		if !request.ExpiresAt.After(now) {
+			delete(s.requests, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+const idempotencyKeyPrefix = "idempotency:"
+
+// idempotencyBackoff is how long the fallback store is used after a failed Redis call
+const idempotencyBackoff = 5 * time.Second
+
+// RedisIdempotencyStore is an IdempotencyStore in Redis. Each key holds the request as JSON and
+// expires through the Redis TTL. While Redis is unavailable keys are kept in the fallback store
+// instead, so a retry that spans the outage may not be recognised. It is safe for concurrent use.
+type RedisIdempotencyStore struct {
+	client    *redis.Client
+	fallback  IdempotencyStore
+	downUntil atomic.Int64 // unix nanoseconds until which the fallback is used
+}
+
+func NewRedisIdempotencyStore(client *redis.Client, fallback IdempotencyStore) *RedisIdempotencyStore {
+	return &RedisIdempotencyStore{client: client, fallback: fallback}
+}
+
+// completeScript replaces the value of a key with the completed request if the in-progress claim
+// in it still has the same request hash
+var completeScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if not current then return 0 end
+local claim = cjson.decode(current)
+if claim.request_hash ~= ARGV[1] or claim.completed then return 0 end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// releaseScript deletes a key if it still holds the in-progress claim with the request hash
+var releaseScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if not current then return 0 end
+local claim = cjson.decode(current)
+if claim.request_hash ~= ARGV[1] or claim.completed then return 0 end
+return redis.call('DEL', KEYS[1])
+`)
+
+// Reserve sets the key if it is absent. When the key is held it is read; it is retried once in case
+// it expired or was released in between.
+func (s *RedisIdempotencyStore) Reserve(ctx context.Context, key, requestHash string, lockTimeout time.Duration) (*models.IdempotentRequest, error) {
+	if s.down() {
+		return s.fallback.Reserve(ctx, key, requestHash, lockTimeout)
+	}
+
+	claim, err := json.Marshal(models.IdempotentRequest{
+		Key:         key,
+		RequestHash: requestHash,
+		ExpiresAt:   time.Now().Add(lockTimeout),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		claimed, err := s.client.SetNX(ctx, idempotencyKeyPrefix+key, claim, lockTimeout).Result()
+		if err != nil {
+			s.failed(ctx, err)
+			return s.fallback.Reserve(ctx, key, requestHash, lockTimeout)
+		}
+		if claimed {
+			return nil, nil
+		}
+
+		value, err := s.client.Get(ctx, idempotencyKeyPrefix+key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			s.failed(ctx, err)
+			return s.fallback.Reserve(ctx, key, requestHash, lockTimeout)
+		}
+		var existing models.IdempotentRequest
+		if err := json.Unmarshal(value, &existing); err != nil {
+			return nil, fmt.Errorf("decode idempotency key %q: %w", key, err)
+		}
+		return &existing, nil
+	}
+	return nil, fmt.Errorf("idempotency key %q changed while being claimed", key)
+}
+
+func (s *RedisIdempotencyStore) Complete(ctx context.Context, request *models.IdempotentRequest, ttl time.Duration) error {
+	if s.down() {
+		return s.fallback.Complete(ctx, request, ttl)
+	}
+
+	completed := *request
+	completed.Completed = true
+	completed.ExpiresAt = time.Now().Add(ttl)
+	value, err := json.Marshal(completed)
+	if err != nil {
+		return err
+	}
+
+	err = completeScript.Run(ctx, s.client, []string{idempotencyKeyPrefix + request.Key},
+		request.RequestHash, value, ttl.Milliseconds()).Err()
+	if err != nil {
+		s.failed(ctx, err)
+		return s.fallback.Complete(ctx, request, ttl)
+	}
+	return nil
+}
+
+func (s *RedisIdempotencyStore) Release(ctx context.Context, key, requestHash string) error {
+	if s.down() {
+		return s.fallback.Release(ctx, key, requestHash)
+	}
+
+	err := releaseScript.Run(ctx, s.client, []string{idempotencyKeyPrefix + key}, requestHash).Err()
+	if err != nil {
+		s.failed(ctx, err)
+		return s.fallback.Release(ctx, key, requestHash)
+	}
+	return nil
+}
+
+// PurgeExpired purges the fallback store: Redis expires its keys itself
+func (s *RedisIdempotencyStore) PurgeExpired(ctx context.Context) (int, error) {
+	return s.fallback.PurgeExpired(ctx)
+}
+
+func (s *RedisIdempotencyStore) down() bool {
+	return time.Now().UnixNano() < s.downUntil.Load()
+}
+
+// failed switches to the fallback store for the backoff period
+func (s *RedisIdempotencyStore) failed(ctx context.Context, err error) {
+	logging.FromContext(ctx).Warn("idempotency keys falling back from Redis", "error", err, "backoff", idempotencyBackoff)
+	s.downUntil.Store(time.Now().Add(idempotencyBackoff).UnixNano())
+}
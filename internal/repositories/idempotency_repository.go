@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// IdempotencyRepository is an IdempotencyStore in Postgres, used when Redis is not configured.
+// Expiry is computed by the database clock so every instance agrees on it.
+type IdempotencyRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// Reserve inserts the key, or takes over an expired row for it. When the key is held the existing
+// row is read; it is retried once in case the row expired or was released in between.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, key, requestHash string, lockTimeout time.Duration) (*models.IdempotentRequest, error) {
+	claim := `
+		INSERT INTO idempotency_keys (key, request_hash, expires_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP + $3 * INTERVAL '1 millisecond')
+		ON CONFLICT (key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status_code = NULL,
+			content_type = NULL,
+			body = NULL,
+			created_at = CURRENT_TIMESTAMP,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= CURRENT_TIMESTAMP
+		RETURNING key
+	`
+	existing := `
+		SELECT key, request_hash, status_code, content_type, body, expires_at
+		FROM idempotency_keys
+		WHERE key = $1 AND expires_at > CURRENT_TIMESTAMP
+	`
+
+	for attempt := 0; attempt < 2; attempt++ {
+		var claimed string
+		err := r.db.QueryRowContext(ctx, claim, key, requestHash, lockTimeout.Milliseconds()).Scan(&claimed)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		var request models.IdempotentRequest
+		var statusCode sql.NullInt64
+		var contentType sql.NullString
+		err = r.db.QueryRowContext(ctx, existing, key).Scan(
+			&request.Key,
+			&request.RequestHash,
+			&statusCode,
+			&contentType,
+			&request.Body,
+			&request.ExpiresAt,
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		request.Completed = statusCode.Valid
+		request.StatusCode = int(statusCode.Int64)
+		request.ContentType = contentType.String
+		return &request, nil
+	}
+	return nil, fmt.Errorf("idempotency key %q changed while being claimed", key)
+}
+
+// Complete stores the response on the row, if the request still holds it
+func (r *IdempotencyRepository) Complete(ctx context.Context, request *models.IdempotentRequest, ttl time.Duration) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $3, content_type = $4, body = $5,
+			expires_at = CURRENT_TIMESTAMP + $6 * INTERVAL '1 millisecond'
+		WHERE key = $1 AND request_hash = $2 AND status_code IS NULL AND expires_at > CURRENT_TIMESTAMP
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		request.Key,
+		request.RequestHash,
+		request.StatusCode,
+		request.ContentType,
+		request.Body,
+		ttl.Milliseconds(),
+	)
+	return err
+}
+
+// Release deletes the row of a request that did not complete
+func (r *IdempotencyRepository) Release(ctx context.Context, key, requestHash string) error {
+	query := `DELETE FROM idempotency_keys WHERE key = $1 AND request_hash = $2 AND status_code IS NULL`
+
+	_, err := r.db.ExecContext(ctx, query, key, requestHash)
+	return err
+}
+
+// PurgeExpired deletes the expired rows
+func (r *IdempotencyRepository) PurgeExpired(ctx context.Context) (int, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= CURRENT_TIMESTAMP`)
+	if err != nil {
+		return 0, err
+	}
+	purged, err := result.RowsAffected()
+	return int(purged), err
+}
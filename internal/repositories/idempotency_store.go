@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// IdempotencyStore holds Idempotency-Key claims and the responses stored for them.
+// RedisIdempotencyStore keeps them in Redis, IdempotencyRepository in Postgres and
+// MemoryIdempotencyStore in memory. Expired keys are treated as absent.
+type IdempotencyStore interface {
+	// Reserve claims key for a request with the given hash until lockTimeout passes. It returns
+	// nil when the key was claimed, otherwise the request already holding it.
+	Reserve(ctx context.Context, key, requestHash string, lockTimeout time.Duration) (*models.IdempotentRequest, error)
+	// Complete stores the response of a claimed request and keeps it for ttl. It does nothing if
+	// the claim has expired and the key was taken by another request.
+	Complete(ctx context.Context, request *models.IdempotentRequest, ttl time.Duration) error
+	// Release drops the claim of a request that did not complete, so it can be retried
+	Release(ctx context.Context, key, requestHash string) error
+	// PurgeExpired deletes expired keys and returns how many were deleted
+	PurgeExpired(ctx context.Context) (int, error)
+}
+
+var (
+	_ IdempotencyStore = (*IdempotencyRepository)(nil)
+	_ IdempotencyStore = (*RedisIdempotencyStore)(nil)
+	_ IdempotencyStore = (*MemoryIdempotencyStore)(nil)
+)
@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+const decisionColumns = `
+	id, submission_id, merchant_id, proposed_status, proposed_by, proposal_notes, proposed_at,
+	status, final_status, checked_by, check_notes, checked_at
+`
+
+// CreateDecision records a proposed KYC status change
+func (r *KYCRepository) CreateDecision(ctx context.Context, decision *models.KYCDecision) error {
+	query := `
+		INSERT INTO kyc_decisions (submission_id, merchant_id, proposed_status, proposed_by, proposal_notes, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, proposed_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		decision.SubmissionID,
+		decision.MerchantID,
+		decision.ProposedStatus,
+		decision.ProposedBy,
+		decision.ProposalNotes,
+		decision.Status,
+	).Scan(&decision.ID, &decision.ProposedAt)
+}
+
+// GetDecision retrieves a KYC decision by ID
+func (r *KYCRepository) GetDecision(ctx context.Context, id int) (*models.KYCDecision, error) {
+	query := `SELECT ` + decisionColumns + ` FROM kyc_decisions WHERE id = $1`
+
+	decision, err := scanDecision(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return decision, err
+}
+
+// GetOpenDecision retrieves the pending proposal for a submission, if any
+func (r *KYCRepository) GetOpenDecision(ctx context.Context, submissionID int) (*models.KYCDecision, error) {
+	query := `SELECT ` + decisionColumns + ` FROM kyc_decisions WHERE submission_id = $1 AND status = 'proposed'`
+
+	decision, err := scanDecision(r.db.QueryRowContext(ctx, query, submissionID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return decision, err
+}
+
+// ResolveDecision records the checker's outcome. Only a decision that is still proposed can be
+// resolved, so concurrent checkers cannot both act on the same proposal.
+func (r *KYCRepository) ResolveDecision(ctx context.Context, decision *models.KYCDecision) error {
+	query := `
+		UPDATE kyc_decisions
+		SET status = $1, final_status = $2, checked_by = $3, check_notes = $4, checked_at = $5
+		WHERE id = $6 AND status = 'proposed'
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		decision.Status,
+		decision.FinalStatus,
+		decision.CheckedBy,
+		decision.CheckNotes,
+		decision.CheckedAt,
+		decision.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("kyc decision %w", ErrConflict)
+	}
+
+	return nil
+}
+
+// ListDecisionsByStatus retrieves decisions in the given status, oldest first
+func (r *KYCRepository) ListDecisionsByStatus(ctx context.Context, status string, limit int) ([]models.KYCDecision, error) {
+	query := `
+		SELECT ` + decisionColumns + `
+		FROM kyc_decisions
+		WHERE status = $1
+		ORDER BY proposed_at ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var decisions []models.KYCDecision
+	for rows.Next() {
+		decision, err := scanDecision(rows)
+		if err != nil {
+			return nil, err
+		}
+		decisions = append(decisions, *decision)
+	}
+
+	return decisions, rows.Err()
+}
+
+func scanDecision(row rowScanner) (*models.KYCDecision, error) {
+	var decision models.KYCDecision
+	var checkedBy sql.NullInt32
+
+	if err := row.Scan(
+		&decision.ID,
+		&decision.SubmissionID,
+		&decision.MerchantID,
+		&decision.ProposedStatus,
+		&decision.ProposedBy,
+		&decision.ProposalNotes,
+		&decision.ProposedAt,
+		&decision.Status,
+		&decision.FinalStatus,
+		&checkedBy,
+		&decision.CheckNotes,
+		&decision.CheckedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if checkedBy.Valid {
+		val := int(checkedBy.Int32)
+		decision.CheckedBy = &val
+	}
+
+	return &decision, nil
+}
@@ -0,0 +1,226 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kodra-pay/compliance-service/internal/encryption"
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// sealedField is an encrypted column value together with its blind index
+type sealedField struct {
+	value string
+	index sql.NullString
+}
+
+type sealedSubmission struct {
+	tin, bvn, phone, email sealedField
+	keyVersion             int
+}
+
+type sealedPerson struct {
+	bvn, phone, email sealedField
+	keyVersion        int
+}
+
+func (r *KYCRepository) seal(ctx context.Context, kind encryption.IndexKind, plaintext string) (sealedField, error) {
+	value, err := r.enc.Encrypt(ctx, plaintext)
+	if err != nil {
+		return sealedField{}, fmt.Errorf("failed to encrypt %s: %w", kind, err)
+	}
+	index, err := r.enc.BlindIndex(ctx, kind, plaintext)
+	if err != nil {
+		return sealedField{}, fmt.Errorf("failed to index %s: %w", kind, err)
+	}
+	return sealedField{value: value, index: sql.NullString{String: index, Valid: index != ""}}, nil
+}
+
+func (r *KYCRepository) sealSubmission(ctx context.Context, submission *models.KYCSubmission) (*sealedSubmission, error) {
+	var sealed sealedSubmission
+	var err error
+
+	if sealed.tin, err = r.seal(ctx, encryption.IndexTIN, submission.TINNumber); err != nil {
+		return nil, err
+	}
+	if sealed.bvn, err = r.seal(ctx, encryption.IndexBVN, submission.DirectorBVN); err != nil {
+		return nil, err
+	}
+	if sealed.phone, err = r.seal(ctx, encryption.IndexPhone, submission.DirectorPhone); err != nil {
+		return nil, err
+	}
+	if sealed.email, err = r.seal(ctx, encryption.IndexEmail, submission.DirectorEmail); err != nil {
+		return nil, err
+	}
+	sealed.keyVersion = r.enc.CurrentVersion()
+
+	return &sealed, nil
+}
+
+func (r *KYCRepository) sealPerson(ctx context.Context, person *models.KYCPerson) (*sealedPerson, error) {
+	var sealed sealedPerson
+	var err error
+
+	if sealed.bvn, err = r.seal(ctx, encryption.IndexBVN, person.BVN); err != nil {
+		return nil, err
+	}
+	if sealed.phone, err = r.seal(ctx, encryption.IndexPhone, person.Phone); err != nil {
+		return nil, err
+	}
+	if sealed.email, err = r.seal(ctx, encryption.IndexEmail, person.Email); err != nil {
+		return nil, err
+	}
+	sealed.keyVersion = r.enc.CurrentVersion()
+
+	return &sealed, nil
+}
+
+// openSubmission decrypts the encrypted columns of a submission in place
+func (r *KYCRepository) openSubmission(ctx context.Context, submission *models.KYCSubmission) error {
+	for _, field := range []*string{
+		&submission.TINNumber,
+		&submission.DirectorBVN,
+		&submission.DirectorPhone,
+		&submission.DirectorEmail,
+	} {
+		plaintext, err := r.enc.Decrypt(ctx, *field)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt kyc submission %d: %w", submission.ID, err)
+		}
+		*field = plaintext
+	}
+	return nil
+}
+
+// openPerson decrypts the encrypted columns of a person in place
+func (r *KYCRepository) openPerson(ctx context.Context, person *models.KYCPerson) error {
+	for _, field := range []*string{&person.BVN, &person.Phone, &person.Email} {
+		plaintext, err := r.enc.Decrypt(ctx, *field)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt kyc person %d: %w", person.ID, err)
+		}
+		*field = plaintext
+	}
+	return nil
+}
+
+// ListSubmissionIDsByDirectorBVN finds submissions whose director BVN matches exactly, using the
+// blind index rather than decrypting every row
+func (r *KYCRepository) ListSubmissionIDsByDirectorBVN(ctx context.Context, bvn string) ([]int, error) {
+	index, err := r.enc.BlindIndex(ctx, encryption.IndexBVN, bvn)
+	if err != nil || index == "" {
+		return nil, err
+	}
+
+	query := `
+		SELECT id FROM kyc_submissions WHERE director_bvn_bidx = $1
+		UNION
+		SELECT submission_id FROM kyc_persons WHERE bvn_bidx = $1
+	`
+	return queryIDs(ctx, r.db, query, index)
+}
+
+// ReencryptSubmissions re-encrypts up to limit submissions that are not on the current key
+// version, including values still stored in plaintext. It returns the number of rows rotated.
+func (r *KYCRepository) ReencryptSubmissions(ctx context.Context, limit int) (int, error) {
+	ids, err := queryIDs(ctx, r.db,
+		`SELECT id FROM kyc_submissions WHERE encryption_key_version <> $1 ORDER BY id LIMIT $2`,
+		r.enc.CurrentVersion(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		submission, err := r.GetByID(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+		if submission == nil {
+			continue
+		}
+
+		sealed, err := r.sealSubmission(ctx, submission)
+		if err != nil {
+			return 0, err
+		}
+
+		query := `
+			UPDATE kyc_submissions
+			SET tin_number = $1, director_bvn = $2, director_phone = $3, director_email = $4,
+				tin_number_bidx = $5, director_bvn_bidx = $6, director_phone_bidx = $7,
+				director_email_bidx = $8, encryption_key_version = $9
+			WHERE id = $10
+		`
+		if _, err := r.db.ExecContext(ctx, query,
+			sealed.tin.value, sealed.bvn.value, sealed.phone.value, sealed.email.value,
+			sealed.tin.index, sealed.bvn.index, sealed.phone.index, sealed.email.index,
+			sealed.keyVersion, id,
+		); err != nil {
+			return 0, fmt.Errorf("failed to re-encrypt kyc submission %d: %w", id, err)
+		}
+	}
+
+	return len(ids), nil
+}
+
+// ReencryptPersons re-encrypts up to limit persons that are not on the current key version
+func (r *KYCRepository) ReencryptPersons(ctx context.Context, limit int) (int, error) {
+	ids, err := queryIDs(ctx, r.db,
+		`SELECT id FROM kyc_persons WHERE encryption_key_version <> $1 ORDER BY id LIMIT $2`,
+		r.enc.CurrentVersion(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, id := range ids {
+		person, err := r.GetPerson(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+		if person == nil {
+			continue
+		}
+
+		sealed, err := r.sealPerson(ctx, person)
+		if err != nil {
+			return 0, err
+		}
+
+		query := `
+			UPDATE kyc_persons
+			SET bvn = $1, phone = $2, email = $3, bvn_bidx = $4, phone_bidx = $5, email_bidx = $6,
+				encryption_key_version = $7
+			WHERE id = $8
+		`
+		if _, err := r.db.ExecContext(ctx, query,
+			sealed.bvn.value, sealed.phone.value, sealed.email.value,
+			sealed.bvn.index, sealed.phone.index, sealed.email.index,
+			sealed.keyVersion, id,
+		); err != nil {
+			return 0, fmt.Errorf("failed to re-encrypt kyc person %d: %w", id, err)
+		}
+	}
+
+	return len(ids), nil
+}
+
+// queryIDs runs a query that selects a single integer ID column
+func queryIDs(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]int, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,574 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/encryption"
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// memorySubmission is a stored submission with the columns that are not part of the model
+type memorySubmission struct {
+	submission models.KYCSubmission
+	flagged    bool
+	flagReason *string
+}
+
+// MemoryKYCStore is a KYCStore kept in memory, for tests and local development. Values are kept
+// in plaintext; lookups that Postgres does through blind indexes compare normalised values.
+// It is safe for concurrent use.
+type MemoryKYCStore struct {
+	mu            sync.RWMutex
+	submissions   map[int]*memorySubmission
+	persons       map[int]models.KYCPerson
+	decisions     map[int]models.KYCDecision
+	relationships []models.MerchantRelationship
+	lastID        int
+	now           func() time.Time
+}
+
+func NewMemoryKYCStore() *MemoryKYCStore {
+	return &MemoryKYCStore{
+		submissions: make(map[int]*memorySubmission),
+		persons:     make(map[int]models.KYCPerson),
+		decisions:   make(map[int]models.KYCDecision),
+		now:         storeNow,
+	}
+}
+
+var _ KYCStore = (*MemoryKYCStore)(nil)
+
+// nextID hands out IDs from one sequence for every table, which keeps them unique and increasing
+func (s *MemoryKYCStore) nextID() int {
+	s.lastID++
+	return s.lastID
+}
+
+// Create stores a new pending submission together with its declared persons
+func (s *MemoryKYCStore) Create(ctx context.Context, submission *models.KYCSubmission) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	submission.ID = s.nextID()
+	submission.CreatedAt, submission.UpdatedAt = now, now
+
+	stored := cloneSubmission(submission)
+	stored.Status = "pending"
+	stored.Persons = nil
+	s.submissions[stored.ID] = &memorySubmission{submission: stored}
+
+	for i := range submission.Persons {
+		person := &submission.Persons[i]
+		person.ID = s.nextID()
+		person.SubmissionID = submission.ID
+		person.CreatedAt, person.UpdatedAt = now, now
+		s.persons[person.ID] = *person
+	}
+	return nil
+}
+
+func (s *MemoryKYCStore) GetByID(ctx context.Context, id int) (*models.KYCSubmission, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored, ok := s.submissions[id]
+	if !ok {
+		return nil, nil
+	}
+	submission := cloneSubmission(&stored.submission)
+	return &submission, nil
+}
+
+// GetLatestByMerchant retrieves the latest KYC submission for a merchant
+func (s *MemoryKYCStore) GetLatestByMerchant(ctx context.Context, merchantID int) (*models.KYCSubmission, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	submissions := s.selectSubmissions(func(submission *models.KYCSubmission) bool {
+		return submission.MerchantID == merchantID
+	}, 1)
+	if len(submissions) == 0 {
+		return nil, nil
+	}
+	return &submissions[0], nil
+}
+
+// UpdateStatus updates the status of a KYC submission
+func (s *MemoryKYCStore) UpdateStatus(ctx context.Context, id int, status string, reviewerID *int, notes *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.submissions[id]
+	if !ok {
+		return fmt.Errorf("kyc submission %w", ErrNotFound)
+	}
+	now := s.now()
+	stored.submission.Status = status
+	stored.submission.ReviewerID = cloneInt(reviewerID)
+	stored.submission.ReviewNotes = cloneString(notes)
+	stored.submission.ReviewedAt = &now
+	stored.submission.UpdatedAt = now
+	return nil
+}
+
+// ListByStatus retrieves KYC submissions by status, newest first
+func (s *MemoryKYCStore) ListByStatus(ctx context.Context, status string, limit int) ([]models.KYCSubmission, error) {
+	if limit < 0 {
+		return nil, errNegativeLimit
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.selectSubmissions(func(submission *models.KYCSubmission) bool {
+		return submission.Status == status
+	}, limit), nil
+}
+
+// PendingBacklog returns the number of pending submissions and the created_at of the oldest one
+func (s *MemoryKYCStore) PendingBacklog(ctx context.Context) (int, *time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int
+	var oldest *time.Time
+	for _, stored := range s.submissions {
+		if stored.submission.Status != "pending" {
+			continue
+		}
+		count++
+		if oldest == nil || stored.submission.CreatedAt.Before(*oldest) {
+			createdAt := stored.submission.CreatedAt
+			oldest = &createdAt
+		}
+	}
+	return count, oldest, nil
+}
+
+// selectSubmissions returns up to limit matching submissions ordered by created_at descending
+func (s *MemoryKYCStore) selectSubmissions(match func(*models.KYCSubmission) bool, limit int) []models.KYCSubmission {
+	var submissions []models.KYCSubmission
+	for _, stored := range s.submissions {
+		if match(&stored.submission) {
+			submissions = append(submissions, cloneSubmission(&stored.submission))
+		}
+	}
+	sort.Slice(submissions, func(i, j int) bool {
+		a, b := submissions[i], submissions[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+	if len(submissions) > limit {
+		submissions = submissions[:limit]
+	}
+	return submissions
+}
+
+// ListPersons retrieves the directors, shareholders and beneficial owners of a submission
+func (s *MemoryKYCStore) ListPersons(ctx context.Context, submissionID int) ([]models.KYCPerson, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var persons []models.KYCPerson
+	for _, person := range s.persons {
+		if person.SubmissionID == submissionID {
+			persons = append(persons, person)
+		}
+	}
+	sort.Slice(persons, func(i, j int) bool { return persons[i].ID < persons[j].ID })
+	return persons, nil
+}
+
+// GetPerson retrieves a single declared person
+func (s *MemoryKYCStore) GetPerson(ctx context.Context, id int) (*models.KYCPerson, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	person, ok := s.persons[id]
+	if !ok {
+		return nil, nil
+	}
+	return &person, nil
+}
+
+// UpdatePersonIdentity records the identity verification outcome of a person
+func (s *MemoryKYCStore) UpdatePersonIdentity(ctx context.Context, id int, status string, reference *string) error {
+	return s.updatePerson(id, func(person *models.KYCPerson) {
+		person.IdentityStatus = status
+		person.IdentityReference = cloneString(reference)
+	})
+}
+
+// UpdatePersonScreening records the screening outcome of a person
+func (s *MemoryKYCStore) UpdatePersonScreening(ctx context.Context, id int, status string) error {
+	return s.updatePerson(id, func(person *models.KYCPerson) {
+		person.ScreeningStatus = status
+	})
+}
+
+func (s *MemoryKYCStore) updatePerson(id int, update func(*models.KYCPerson)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	person, ok := s.persons[id]
+	if !ok {
+		return fmt.Errorf("kyc person %w", ErrNotFound)
+	}
+	update(&person)
+	person.UpdatedAt = s.now()
+	s.persons[id] = person
+	return nil
+}
+
+// CreateDecision records a proposed KYC status change
+func (s *MemoryKYCStore) CreateDecision(ctx context.Context, decision *models.KYCDecision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	decision.ID = s.nextID()
+	decision.ProposedAt = s.now()
+	s.decisions[decision.ID] = *decision
+	return nil
+}
+
+// GetDecision retrieves a KYC decision by ID
+func (s *MemoryKYCStore) GetDecision(ctx context.Context, id int) (*models.KYCDecision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	decision, ok := s.decisions[id]
+	if !ok {
+		return nil, nil
+	}
+	return &decision, nil
+}
+
+// GetOpenDecision retrieves the pending proposal for a submission, if any
+func (s *MemoryKYCStore) GetOpenDecision(ctx context.Context, submissionID int) (*models.KYCDecision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, decision := range s.decisions {
+		if decision.SubmissionID == submissionID && decision.Status == models.DecisionStatusProposed {
+			return &decision, nil
+		}
+	}
+	return nil, nil
+}
+
+// ResolveDecision records the checker's outcome of a decision that is still proposed
+func (s *MemoryKYCStore) ResolveDecision(ctx context.Context, decision *models.KYCDecision) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.decisions[decision.ID]
+	if !ok || stored.Status != models.DecisionStatusProposed {
+		return fmt.Errorf("kyc decision %w", ErrConflict)
+	}
+	stored.Status = decision.Status
+	stored.FinalStatus = cloneString(decision.FinalStatus)
+	stored.CheckedBy = cloneInt(decision.CheckedBy)
+	stored.CheckNotes = cloneString(decision.CheckNotes)
+	stored.CheckedAt = decision.CheckedAt
+	s.decisions[decision.ID] = stored
+	return nil
+}
+
+// ListDecisionsByStatus retrieves decisions in the given status, oldest first
+func (s *MemoryKYCStore) ListDecisionsByStatus(ctx context.Context, status string, limit int) ([]models.KYCDecision, error) {
+	if limit < 0 {
+		return nil, errNegativeLimit
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var decisions []models.KYCDecision
+	for _, decision := range s.decisions {
+		if decision.Status == status {
+			decisions = append(decisions, decision)
+		}
+	}
+	sort.Slice(decisions, func(i, j int) bool {
+		a, b := decisions[i], decisions[j]
+		if !a.ProposedAt.Equal(b.ProposedAt) {
+			return a.ProposedAt.Before(b.ProposedAt)
+		}
+		return a.ID < b.ID
+	})
+	if len(decisions) > limit {
+		decisions = decisions[:limit]
+	}
+	return decisions, nil
+}
+
+// FindRelatedSubmissions returns links from the submission to submissions of other merchants that
+// share an identifier, with the same rules as relatedSubmissionsQuery
+func (s *MemoryKYCStore) FindRelatedSubmissions(ctx context.Context, submission *models.KYCSubmission) ([]models.MerchantRelationship, error) {
+	bvns := identifierSet(encryption.IndexBVN, submission.DirectorBVN)
+	phones := identifierSet(encryption.IndexPhone, submission.DirectorPhone)
+	emails := identifierSet(encryption.IndexEmail, submission.DirectorEmail)
+	for _, person := range submission.Persons {
+		bvns.add(encryption.IndexBVN, person.BVN)
+		phones.add(encryption.IndexPhone, person.Phone)
+		emails.add(encryption.IndexEmail, person.Email)
+	}
+	tin := encryption.IndexTIN.Normalize(submission.TINNumber)
+	cac := cacKey(submission.CACNumber)
+	addressKey := normalizedAddressKey(submission)
+	legacyKey := legacyAddressKey(submission.BusinessAddress, submission.City)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var links []models.MerchantRelationship
+	for _, stored := range s.submissions {
+		candidate := &stored.submission
+		if candidate.MerchantID == submission.MerchantID {
+			continue
+		}
+
+		persons := s.personsOf(candidate.ID)
+		matches := map[string]bool{
+			models.LinkTypeBVN:   bvns.matches(encryption.IndexBVN, candidate.DirectorBVN, persons, func(p models.KYCPerson) string { return p.BVN }),
+			models.LinkTypePhone: phones.matches(encryption.IndexPhone, candidate.DirectorPhone, persons, func(p models.KYCPerson) string { return p.Phone }),
+			models.LinkTypeEmail: emails.matches(encryption.IndexEmail, candidate.DirectorEmail, persons, func(p models.KYCPerson) string { return p.Email }),
+			models.LinkTypeTIN:   tin != "" && encryption.IndexTIN.Normalize(candidate.TINNumber) == tin,
+			models.LinkTypeCAC:   cac != "" && cacKey(candidate.CACNumber) == cac,
+		}
+		if candidate.Address != nil {
+			matches[models.LinkTypeAddress] = addressKey != "" && candidate.Address.Key == addressKey
+		} else {
+			matches[models.LinkTypeAddress] = legacyKey != "" && legacyAddressKey(candidate.BusinessAddress, candidate.City) == legacyKey
+		}
+
+		for linkType, matched := range matches {
+			if matched {
+				links = append(links, models.MerchantRelationship{
+					SubmissionID:        submission.ID,
+					MerchantID:          submission.MerchantID,
+					RelatedSubmissionID: candidate.ID,
+					RelatedMerchantID:   candidate.MerchantID,
+					LinkType:            linkType,
+				})
+			}
+		}
+	}
+
+	sort.Slice(links, func(i, j int) bool {
+		if links[i].RelatedSubmissionID != links[j].RelatedSubmissionID {
+			return links[i].RelatedSubmissionID < links[j].RelatedSubmissionID
+		}
+		return links[i].LinkType < links[j].LinkType
+	})
+	return links, nil
+}
+
+func (s *MemoryKYCStore) personsOf(submissionID int) []models.KYCPerson {
+	var persons []models.KYCPerson
+	for _, person := range s.persons {
+		if person.SubmissionID == submissionID {
+			persons = append(persons, person)
+		}
+	}
+	return persons
+}
+
+// CreateRelationships stores links in the related-merchant graph, ignoring links already stored
+func (s *MemoryKYCStore) CreateRelationships(ctx context.Context, links []models.MerchantRelationship) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, link := range links {
+		duplicate := false
+		for _, stored := range s.relationships {
+			if stored.SubmissionID == link.SubmissionID && stored.RelatedSubmissionID == link.RelatedSubmissionID &&
+				stored.LinkType == link.LinkType {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			continue
+		}
+		link.ID = s.nextID()
+		link.CreatedAt = s.now()
+		s.relationships = append(s.relationships, link)
+	}
+	return nil
+}
+
+// ListRelationships returns the links of a submission in either direction, oriented so that
+// SubmissionID is the given submission
+func (s *MemoryKYCStore) ListRelationships(ctx context.Context, submissionID int) ([]models.MerchantRelationship, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var links []models.MerchantRelationship
+	for _, link := range s.relationships {
+		switch submissionID {
+		case link.SubmissionID:
+			links = append(links, link)
+		case link.RelatedSubmissionID:
+			link.SubmissionID, link.RelatedSubmissionID = link.RelatedSubmissionID, link.SubmissionID
+			link.MerchantID, link.RelatedMerchantID = link.RelatedMerchantID, link.MerchantID
+			links = append(links, link)
+		}
+	}
+	sort.SliceStable(links, func(i, j int) bool {
+		if !links[i].CreatedAt.Equal(links[j].CreatedAt) {
+			return links[i].CreatedAt.Before(links[j].CreatedAt)
+		}
+		return links[i].ID < links[j].ID
+	})
+	return links, nil
+}
+
+// FlagForReview marks a submission for manual review. Reasons accumulate when a submission is
+// flagged more than once.
+func (s *MemoryKYCStore) FlagForReview(ctx context.Context, id int, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.submissions[id]
+	if !ok {
+		return nil
+	}
+	if stored.flagReason != nil && *stored.flagReason != "" {
+		reason = *stored.flagReason + "; " + reason
+	}
+	stored.flagged = true
+	stored.flagReason = &reason
+	stored.submission.UpdatedAt = s.now()
+	return nil
+}
+
+// GetReviewFlag returns whether a submission is flagged for manual review and why
+func (s *MemoryKYCStore) GetReviewFlag(ctx context.Context, id int) (bool, *string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	stored, ok := s.submissions[id]
+	if !ok {
+		return false, nil, sql.ErrNoRows
+	}
+	return stored.flagged, cloneString(stored.flagReason), nil
+}
+
+// ListSubmissionIDsByDirectorBVN finds submissions whose director or a declared person has the BVN
+func (s *MemoryKYCStore) ListSubmissionIDsByDirectorBVN(ctx context.Context, bvn string) ([]int, error) {
+	normalized := encryption.IndexBVN.Normalize(bvn)
+	if normalized == "" {
+		return nil, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	found := make(map[int]bool)
+	for id, stored := range s.submissions {
+		if encryption.IndexBVN.Normalize(stored.submission.DirectorBVN) == normalized {
+			found[id] = true
+		}
+	}
+	for _, person := range s.persons {
+		if encryption.IndexBVN.Normalize(person.BVN) == normalized {
+			found[person.SubmissionID] = true
+		}
+	}
+
+	var ids []int
+	for id := range found {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// ReencryptSubmissions is a no-op: the memory store keeps values in plaintext
+func (s *MemoryKYCStore) ReencryptSubmissions(ctx context.Context, limit int) (int, error) {
+	return 0, nil
+}
+
+// ReencryptPersons is a no-op: the memory store keeps values in plaintext
+func (s *MemoryKYCStore) ReencryptPersons(ctx context.Context, limit int) (int, error) {
+	return 0, nil
+}
+
+// storeNow is the clock of the memory stores, at the microsecond precision of Postgres timestamps
+func storeNow() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
+// errNegativeLimit is what Postgres reports for a negative LIMIT
+var errNegativeLimit = fmt.Errorf("LIMIT must not be negative")
+
+// identifiers is a set of normalised identifier values
+type identifiers map[string]bool
+
+func identifierSet(kind encryption.IndexKind, value string) identifiers {
+	set := make(identifiers)
+	set.add(kind, value)
+	return set
+}
+
+func (set identifiers) add(kind encryption.IndexKind, value string) {
+	if normalized := kind.Normalize(value); normalized != "" {
+		set[normalized] = true
+	}
+}
+
+// matches reports whether the director value or a person's value is in the set
+func (set identifiers) matches(kind encryption.IndexKind, director string, persons []models.KYCPerson, value func(models.KYCPerson) string) bool {
+	if set[kind.Normalize(director)] {
+		return true
+	}
+	for _, person := range persons {
+		if set[kind.Normalize(value(person))] {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneSubmission copies a submission so the store and its callers never share mutable state
+func cloneSubmission(submission *models.KYCSubmission) models.KYCSubmission {
+	clone := *submission
+	if submission.Documents != nil {
+		clone.Documents = make(map[string]string, len(submission.Documents))
+		for key, value := range submission.Documents {
+			clone.Documents[key] = value
+		}
+	}
+	if submission.Address != nil {
+		address := *submission.Address
+		address.Flags = append([]string(nil), submission.Address.Flags...)
+		clone.Address = &address
+	}
+	clone.Persons = append([]models.KYCPerson(nil), submission.Persons...)
+	clone.ReviewerID = cloneInt(submission.ReviewerID)
+	clone.ReviewNotes = cloneString(submission.ReviewNotes)
+	return clone
+}
+
+func cloneInt(value *int) *int {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
+
+func cloneString(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
@@ -0,0 +1,236 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/kodra-pay/compliance-service/internal/encryption"
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/lib/pq"
+)
+
+// relatedSubmissionsQuery finds submissions of other merchants sharing an identifier with a
+// submission. Encrypted identifiers are matched through their blind indexes; directors and
+// declared persons of both submissions are compared. Addresses are matched on the normalised
+// address key, or on the raw address for submissions made before address normalisation.
+const relatedSubmissionsQuery = `
+	WITH candidates AS (
+		SELECT id, merchant_id, cac_number, tin_number_bidx, director_bvn_bidx, director_phone_bidx,
+			director_email_bidx, business_address, city, address_key
+		FROM kyc_submissions
+		WHERE merchant_id <> $1 AND anonymised_at IS NULL
+	),
+	candidate_persons AS (
+		SELECT c.id, c.merchant_id, p.bvn_bidx, p.phone_bidx, p.email_bidx
+		FROM kyc_persons p
+		JOIN candidates c ON c.id = p.submission_id
+	)
+	SELECT id, merchant_id, 'bvn' FROM candidates WHERE director_bvn_bidx = ANY($2)
+	UNION SELECT id, merchant_id, 'bvn' FROM candidate_persons WHERE bvn_bidx = ANY($2)
+	UNION SELECT id, merchant_id, 'phone' FROM candidates WHERE director_phone_bidx = ANY($3)
+	UNION SELECT id, merchant_id, 'phone' FROM candidate_persons WHERE phone_bidx = ANY($3)
+	UNION SELECT id, merchant_id, 'email' FROM candidates WHERE director_email_bidx = ANY($4)
+	UNION SELECT id, merchant_id, 'email' FROM candidate_persons WHERE email_bidx = ANY($4)
+	UNION SELECT id, merchant_id, 'tin' FROM candidates WHERE $5 <> '' AND tin_number_bidx = $5
+	UNION SELECT id, merchant_id, 'cac' FROM candidates
+		WHERE $6 <> '' AND regexp_replace(UPPER(cac_number), '[^A-Z0-9]', '', 'g') = $6
+	UNION SELECT id, merchant_id, 'address' FROM candidates WHERE $7 <> '' AND address_key = $7
+	UNION SELECT id, merchant_id, 'address' FROM candidates
+		WHERE $8 <> '' AND address_key IS NULL AND COALESCE(business_address, '') <> ''
+			AND regexp_replace(LOWER(business_address || COALESCE(city, '')), '[^a-z0-9]', '', 'g') = $8
+	ORDER BY 1
+`
+
+// FindRelatedSubmissions returns links from the submission to submissions of other merchants that
+// share its director or person BVNs, phones or emails, its TIN, CAC number or business address
+func (r *KYCRepository) FindRelatedSubmissions(ctx context.Context, submission *models.KYCSubmission) ([]models.MerchantRelationship, error) {
+	bvns, phones, emails := []string{submission.DirectorBVN}, []string{submission.DirectorPhone}, []string{submission.DirectorEmail}
+	for _, person := range submission.Persons {
+		bvns = append(bvns, person.BVN)
+		phones = append(phones, person.Phone)
+		emails = append(emails, person.Email)
+	}
+
+	bvnIndexes, err := r.blindIndexes(ctx, encryption.IndexBVN, bvns)
+	if err != nil {
+		return nil, err
+	}
+	phoneIndexes, err := r.blindIndexes(ctx, encryption.IndexPhone, phones)
+	if err != nil {
+		return nil, err
+	}
+	emailIndexes, err := r.blindIndexes(ctx, encryption.IndexEmail, emails)
+	if err != nil {
+		return nil, err
+	}
+	tinIndex, err := r.enc.BlindIndex(ctx, encryption.IndexTIN, submission.TINNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, relatedSubmissionsQuery,
+		submission.MerchantID,
+		pq.Array(bvnIndexes),
+		pq.Array(phoneIndexes),
+		pq.Array(emailIndexes),
+		tinIndex,
+		cacKey(submission.CACNumber),
+		normalizedAddressKey(submission),
+		legacyAddressKey(submission.BusinessAddress, submission.City),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []models.MerchantRelationship
+	for rows.Next() {
+		link := models.MerchantRelationship{SubmissionID: submission.ID, MerchantID: submission.MerchantID}
+		if err := rows.Scan(&link.RelatedSubmissionID, &link.RelatedMerchantID, &link.LinkType); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+// CreateRelationships stores links in the related-merchant graph, ignoring links already stored
+func (r *KYCRepository) CreateRelationships(ctx context.Context, links []models.MerchantRelationship) error {
+	query := `
+		INSERT INTO merchant_relationships (
+			submission_id, merchant_id, related_submission_id, related_merchant_id, link_type
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (submission_id, related_submission_id, link_type) DO NOTHING
+	`
+
+	for _, link := range links {
+		if _, err := r.db.ExecContext(ctx, query,
+			link.SubmissionID,
+			link.MerchantID,
+			link.RelatedSubmissionID,
+			link.RelatedMerchantID,
+			link.LinkType,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ListRelationships returns the links of a submission in either direction, oriented so that
+// SubmissionID is the given submission
+func (r *KYCRepository) ListRelationships(ctx context.Context, submissionID int) ([]models.MerchantRelationship, error) {
+	query := `
+		SELECT id, submission_id, merchant_id, related_submission_id, related_merchant_id, link_type, created_at
+		FROM merchant_relationships
+		WHERE submission_id = $1
+		UNION ALL
+		SELECT id, related_submission_id, related_merchant_id, submission_id, merchant_id, link_type, created_at
+		FROM merchant_relationships
+		WHERE related_submission_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []models.MerchantRelationship
+	for rows.Next() {
+		var link models.MerchantRelationship
+		if err := rows.Scan(
+			&link.ID,
+			&link.SubmissionID,
+			&link.MerchantID,
+			&link.RelatedSubmissionID,
+			&link.RelatedMerchantID,
+			&link.LinkType,
+			&link.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+// FlagForReview marks a submission for manual review. Reasons accumulate when a submission is
+// flagged more than once.
+func (r *KYCRepository) FlagForReview(ctx context.Context, id int, reason string) error {
+	query := `
+		UPDATE kyc_submissions
+		SET flagged_for_review = TRUE,
+			flag_reason = CASE WHEN COALESCE(flag_reason, '') = '' THEN $1 ELSE flag_reason || '; ' || $1 END,
+			updated_at = NOW()
+		WHERE id = $2
+	`
+	_, err := r.db.ExecContext(ctx, query, reason, id)
+	return err
+}
+
+// GetReviewFlag returns whether a submission is flagged for manual review and why
+func (r *KYCRepository) GetReviewFlag(ctx context.Context, id int) (bool, *string, error) {
+	query := `SELECT flagged_for_review, flag_reason FROM kyc_submissions WHERE id = $1`
+
+	var flagged bool
+	var reason sql.NullString
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(&flagged, &reason); err != nil {
+		return false, nil, err
+	}
+	if !reason.Valid {
+		return flagged, nil, nil
+	}
+	return flagged, &reason.String, nil
+}
+
+func (r *KYCRepository) blindIndexes(ctx context.Context, kind encryption.IndexKind, values []string) ([]string, error) {
+	var indexes []string
+	for _, value := range values {
+		index, err := r.enc.BlindIndex(ctx, kind, value)
+		if err != nil {
+			return nil, err
+		}
+		if index != "" {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes, nil
+}
+
+// cacKey normalises a CAC number the same way relatedSubmissionsQuery does
+func cacKey(cac string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, strings.ToUpper(cac))
+}
+
+func normalizedAddressKey(submission *models.KYCSubmission) string {
+	if submission.Address == nil {
+		return ""
+	}
+	return submission.Address.Key
+}
+
+// legacyAddressKey reduces a raw business address and city the same way relatedSubmissionsQuery
+// does for submissions without a normalised address
+func legacyAddressKey(address, city string) string {
+	if strings.TrimSpace(address) == "" {
+		return ""
+	}
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, strings.ToLower(address+city))
+}
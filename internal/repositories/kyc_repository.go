@@ -5,19 +5,24 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/kodra-pay/compliance-service/internal/encryption"
 	"github.com/kodra-pay/compliance-service/internal/models"
 )
 
+// KYCRepository persists KYC submissions. BVN, phone, email and TIN values are encrypted at rest
+// and blind-indexed for exact-match lookups.
 type KYCRepository struct {
-	db *sql.DB
+	db  *sql.DB
+	enc *encryption.FieldEncryptor
 }
 
-func NewKYCRepository(db *sql.DB) *KYCRepository {
-	return &KYCRepository{db: db}
+func NewKYCRepository(db *sql.DB, enc *encryption.FieldEncryptor) *KYCRepository {
+	return &KYCRepository{db: db, enc: enc}
 }
 
-// Create creates a new KYC submission
+// Create creates a new KYC submission together with its declared persons
 func (r *KYCRepository) Create(ctx context.Context, submission *models.KYCSubmission) error {
 	// Convert documents map to JSONB
 	docsJSON, err := json.Marshal(submission.Documents)
@@ -25,23 +30,49 @@ func (r *KYCRepository) Create(ctx context.Context, submission *models.KYCSubmis
 		return fmt.Errorf("failed to marshal documents: %w", err)
 	}
 
+	sealed, err := r.sealSubmission(ctx, submission)
+	if err != nil {
+		return err
+	}
+
+	// Submissions made before address normalisation have neither column set
+	var addressJSON []byte
+	var addressKey *string
+	if submission.Address != nil {
+		if addressJSON, err = json.Marshal(submission.Address); err != nil {
+			return fmt.Errorf("failed to marshal normalized address: %w", err)
+		}
+		if submission.Address.Key != "" {
+			addressKey = &submission.Address.Key
+		}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO kyc_submissions (
 			merchant_id, business_type, business_name, cac_number, tin_number,
 			business_address, city, state, postal_code, incorporation_date,
 			business_category, director_name, director_bvn, director_phone,
-			director_email, documents, status
+			director_email, documents, status, tin_number_bidx, director_bvn_bidx,
+			director_phone_bidx, director_email_bidx, encryption_key_version,
+			address_normalized, address_key
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, 'pending')
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, 'pending',
+			$17, $18, $19, $20, $21, $22, $23)
 		RETURNING id, created_at, updated_at
 	`
 
-	return r.db.QueryRowContext(ctx, query,
+	if err := tx.QueryRowContext(ctx, query,
 		submission.MerchantID,
 		submission.BusinessType,
 		submission.BusinessName,
 		submission.CACNumber,
-		submission.TINNumber,
+		sealed.tin.value,
 		submission.BusinessAddress,
 		submission.City,
 		submission.State,
@@ -49,17 +80,68 @@ func (r *KYCRepository) Create(ctx context.Context, submission *models.KYCSubmis
 		submission.IncorporationDate,
 		submission.BusinessCategory,
 		submission.DirectorName,
-		submission.DirectorBVN,
-		submission.DirectorPhone,
-		submission.DirectorEmail,
+		sealed.bvn.value,
+		sealed.phone.value,
+		sealed.email.value,
 		docsJSON,
-	).Scan(&submission.ID, &submission.CreatedAt, &submission.UpdatedAt)
+		sealed.tin.index,
+		sealed.bvn.index,
+		sealed.phone.index,
+		sealed.email.index,
+		sealed.keyVersion,
+		addressJSON,
+		addressKey,
+	).Scan(&submission.ID, &submission.CreatedAt, &submission.UpdatedAt); err != nil {
+		return err
+	}
+
+	personQuery := `
+		INSERT INTO kyc_persons (
+			submission_id, role, full_name, bvn, phone, email, date_of_birth,
+			nationality, ownership_percentage, identity_status, screening_status,
+			bvn_bidx, phone_bidx, email_bidx, encryption_key_version
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING id, created_at, updated_at
+	`
+
+	for i := range submission.Persons {
+		person := &submission.Persons[i]
+		person.SubmissionID = submission.ID
+
+		sealedPerson, err := r.sealPerson(ctx, person)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.QueryRowContext(ctx, personQuery,
+			person.SubmissionID,
+			person.Role,
+			person.FullName,
+			sealedPerson.bvn.value,
+			sealedPerson.phone.value,
+			sealedPerson.email.value,
+			person.DateOfBirth,
+			person.Nationality,
+			person.OwnershipPercentage,
+			person.IdentityStatus,
+			person.ScreeningStatus,
+			sealedPerson.bvn.index,
+			sealedPerson.phone.index,
+			sealedPerson.email.index,
+			sealedPerson.keyVersion,
+		).Scan(&person.ID, &person.CreatedAt, &person.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to insert kyc person: %w", err)
+		}
+	}
+
+	return tx.Commit()
 }
 
 func (r *KYCRepository) GetByID(ctx context.Context, id int) (*models.KYCSubmission, error) {
 	query := `
 		SELECT id, merchant_id, business_type, business_name, cac_number, tin_number,
-			business_address, city, state, postal_code, incorporation_date,
+			business_address, city, state, postal_code, address_normalized, incorporation_date,
 			business_category, director_name, director_bvn, director_phone,
 			director_email, documents, status, reviewer_id, review_notes,
 			reviewed_at, created_at, updated_at
@@ -69,6 +151,7 @@ func (r *KYCRepository) GetByID(ctx context.Context, id int) (*models.KYCSubmiss
 
 	var submission models.KYCSubmission
 	var docsJSON []byte
+	var addressJSON []byte
 	var reviewerID sql.NullInt32 // To handle nullable int
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
@@ -82,6 +165,7 @@ func (r *KYCRepository) GetByID(ctx context.Context, id int) (*models.KYCSubmiss
 		&submission.City,
 		&submission.State,
 		&submission.PostalCode,
+		&addressJSON,
 		&submission.IncorporationDate,
 		&submission.BusinessCategory,
 		&submission.DirectorName,
@@ -109,6 +193,14 @@ func (r *KYCRepository) GetByID(ctx context.Context, id int) (*models.KYCSubmiss
 		return nil, fmt.Errorf("failed to unmarshal documents: %w", err)
 	}
 
+	if submission.Address, err = unmarshalAddress(addressJSON); err != nil {
+		return nil, err
+	}
+
+	if err := r.openSubmission(ctx, &submission); err != nil {
+		return nil, err
+	}
+
 	// Handle nullable reviewerID
 	if reviewerID.Valid {
 		val := int(reviewerID.Int32)
@@ -124,7 +216,7 @@ func (r *KYCRepository) GetByID(ctx context.Context, id int) (*models.KYCSubmiss
 func (r *KYCRepository) GetLatestByMerchant(ctx context.Context, merchantID int) (*models.KYCSubmission, error) {
 	query := `
 		SELECT id, merchant_id, business_type, business_name, cac_number, tin_number,
-			business_address, city, state, postal_code, incorporation_date,
+			business_address, city, state, postal_code, address_normalized, incorporation_date,
 			business_category, director_name, director_bvn, director_phone,
 			director_email, documents, status, reviewer_id, review_notes,
 			reviewed_at, created_at, updated_at
@@ -136,6 +228,7 @@ func (r *KYCRepository) GetLatestByMerchant(ctx context.Context, merchantID int)
 
 	var submission models.KYCSubmission
 	var docsJSON []byte
+	var addressJSON []byte
 	var reviewerID sql.NullInt32
 
 	err := r.db.QueryRowContext(ctx, query, merchantID).Scan(
@@ -149,6 +242,7 @@ func (r *KYCRepository) GetLatestByMerchant(ctx context.Context, merchantID int)
 		&submission.City,
 		&submission.State,
 		&submission.PostalCode,
+		&addressJSON,
 		&submission.IncorporationDate,
 		&submission.BusinessCategory,
 		&submission.DirectorName,
@@ -176,6 +270,14 @@ func (r *KYCRepository) GetLatestByMerchant(ctx context.Context, merchantID int)
 		return nil, fmt.Errorf("failed to unmarshal documents: %w", err)
 	}
 
+	if submission.Address, err = unmarshalAddress(addressJSON); err != nil {
+		return nil, err
+	}
+
+	if err := r.openSubmission(ctx, &submission); err != nil {
+		return nil, err
+	}
+
 	// Handle nullable reviewerID
 	if reviewerID.Valid {
 		val := int(reviewerID.Int32)
@@ -205,7 +307,7 @@ func (r *KYCRepository) UpdateStatus(ctx context.Context, id int, status string,
 		return err
 	}
 	if rows == 0 {
-		return fmt.Errorf("kyc submission not found")
+		return fmt.Errorf("kyc submission %w", ErrNotFound)
 	}
 
 	return nil
@@ -215,7 +317,7 @@ func (r *KYCRepository) UpdateStatus(ctx context.Context, id int, status string,
 func (r *KYCRepository) ListByStatus(ctx context.Context, status string, limit int) ([]models.KYCSubmission, error) {
 	query := `
 		SELECT id, merchant_id, business_type, business_name, cac_number, tin_number,
-			business_address, city, state, postal_code, incorporation_date,
+			business_address, city, state, postal_code, address_normalized, incorporation_date,
 			business_category, director_name, director_bvn, director_phone,
 			director_email, documents, status, reviewer_id, review_notes,
 			reviewed_at, created_at, updated_at
@@ -235,6 +337,7 @@ func (r *KYCRepository) ListByStatus(ctx context.Context, status string, limit i
 	for rows.Next() {
 		var submission models.KYCSubmission
 		var docsJSON []byte
+		var addressJSON []byte
 		var reviewerID sql.NullInt32
 
 		if err := rows.Scan(
@@ -248,6 +351,7 @@ func (r *KYCRepository) ListByStatus(ctx context.Context, status string, limit i
 			&submission.City,
 			&submission.State,
 			&submission.PostalCode,
+			&addressJSON,
 			&submission.IncorporationDate,
 			&submission.BusinessCategory,
 			&submission.DirectorName,
@@ -270,6 +374,16 @@ func (r *KYCRepository) ListByStatus(ctx context.Context, status string, limit i
 			return nil, fmt.Errorf("failed to unmarshal documents: %w", err)
 		}
 
+		address, err := unmarshalAddress(addressJSON)
+		if err != nil {
+			return nil, err
+		}
+		submission.Address = address
+
+		if err := r.openSubmission(ctx, &submission); err != nil {
+			return nil, err
+		}
+
 		// Handle nullable reviewerID
 		if reviewerID.Valid {
 			val := int(reviewerID.Int32)
@@ -283,3 +397,153 @@ func (r *KYCRepository) ListByStatus(ctx context.Context, status string, limit i
 
 	return submissions, rows.Err()
 }
+
+// PendingBacklog returns the number of submissions waiting for review and the created_at of the
+// oldest one, which is nil when none is pending
+func (r *KYCRepository) PendingBacklog(ctx context.Context) (int, *time.Time, error) {
+	query := `SELECT COUNT(*), MIN(created_at) FROM kyc_submissions WHERE status = 'pending'`
+
+	var count int
+	var oldest sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query).Scan(&count, &oldest); err != nil {
+		return 0, nil, err
+	}
+	if !oldest.Valid {
+		return count, nil, nil
+	}
+	return count, &oldest.Time, nil
+}
+
+const personColumns = `
+	id, submission_id, role, full_name, COALESCE(bvn, ''), COALESCE(phone, ''), COALESCE(email, ''),
+	date_of_birth, COALESCE(nationality, ''), ownership_percentage, identity_status,
+	identity_reference, screening_status, created_at, updated_at
+`
+
+// ListPersons retrieves the directors, shareholders and beneficial owners of a submission
+func (r *KYCRepository) ListPersons(ctx context.Context, submissionID int) ([]models.KYCPerson, error) {
+	query := `SELECT ` + personColumns + ` FROM kyc_persons WHERE submission_id = $1 ORDER BY id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var persons []models.KYCPerson
+	for rows.Next() {
+		person, err := scanPerson(rows)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.openPerson(ctx, person); err != nil {
+			return nil, err
+		}
+		persons = append(persons, *person)
+	}
+
+	return persons, rows.Err()
+}
+
+// GetPerson retrieves a single declared person
+func (r *KYCRepository) GetPerson(ctx context.Context, id int) (*models.KYCPerson, error) {
+	query := `SELECT ` + personColumns + ` FROM kyc_persons WHERE id = $1`
+
+	person, err := scanPerson(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.openPerson(ctx, person); err != nil {
+		return nil, err
+	}
+	return person, nil
+}
+
+// UpdatePersonIdentity records the identity verification outcome of a person
+func (r *KYCRepository) UpdatePersonIdentity(ctx context.Context, id int, status string, reference *string) error {
+	query := `
+		UPDATE kyc_persons
+		SET identity_status = $1, identity_reference = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+	return r.execPersonUpdate(ctx, query, status, reference, id)
+}
+
+// UpdatePersonScreening records the screening outcome of a person
+func (r *KYCRepository) UpdatePersonScreening(ctx context.Context, id int, status string) error {
+	query := `
+		UPDATE kyc_persons
+		SET screening_status = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+	return r.execPersonUpdate(ctx, query, status, id)
+}
+
+func (r *KYCRepository) execPersonUpdate(ctx context.Context, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("kyc person %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPerson(row rowScanner) (*models.KYCPerson, error) {
+	var person models.KYCPerson
+	var ownership sql.NullFloat64
+
+	if err := row.Scan(
+		&person.ID,
+		&person.SubmissionID,
+		&person.Role,
+		&person.FullName,
+		&person.BVN,
+		&person.Phone,
+		&person.Email,
+		&person.DateOfBirth,
+		&person.Nationality,
+		&ownership,
+		&person.IdentityStatus,
+		&person.IdentityReference,
+		&person.ScreeningStatus,
+		&person.CreatedAt,
+		&person.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if ownership.Valid {
+		person.OwnershipPercentage = &ownership.Float64
+	}
+
+	return &person, nil
+}
+
+// unmarshalAddress decodes the normalized address column, which is NULL for submissions made
+// before address normalisation
+func unmarshalAddress(data []byte) (*models.NormalizedAddress, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var address models.NormalizedAddress
+	if err := json.Unmarshal(data, &address); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal normalized address: %w", err)
+	}
+	return &address, nil
+}
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// KYCStore persists KYC submissions, their declared persons, maker-checker decisions and
+// related-merchant links. KYCRepository stores them in Postgres and MemoryKYCStore in memory.
+//
+// Lookups of a single record return nil, nil when it does not exist; updates of a missing record
+// return an error.
+type KYCStore interface {
+	Create(ctx context.Context, submission *models.KYCSubmission) error
+	GetByID(ctx context.Context, id int) (*models.KYCSubmission, error)
+	GetLatestByMerchant(ctx context.Context, merchantID int) (*models.KYCSubmission, error)
+	UpdateStatus(ctx context.Context, id int, status string, reviewerID *int, notes *string) error
+	ListByStatus(ctx context.Context, status string, limit int) ([]models.KYCSubmission, error)
+	PendingBacklog(ctx context.Context) (count int, oldest *time.Time, err error)
+
+	ListPersons(ctx context.Context, submissionID int) ([]models.KYCPerson, error)
+	GetPerson(ctx context.Context, id int) (*models.KYCPerson, error)
+	UpdatePersonIdentity(ctx context.Context, id int, status string, reference *string) error
+	UpdatePersonScreening(ctx context.Context, id int, status string) error
+
+	CreateDecision(ctx context.Context, decision *models.KYCDecision) error
+	GetDecision(ctx context.Context, id int) (*models.KYCDecision, error)
+	GetOpenDecision(ctx context.Context, submissionID int) (*models.KYCDecision, error)
+	ResolveDecision(ctx context.Context, decision *models.KYCDecision) error
+	ListDecisionsByStatus(ctx context.Context, status string, limit int) ([]models.KYCDecision, error)
+
+	FindRelatedSubmissions(ctx context.Context, submission *models.KYCSubmission) ([]models.MerchantRelationship, error)
+	CreateRelationships(ctx context.Context, links []models.MerchantRelationship) error
+	ListRelationships(ctx context.Context, submissionID int) ([]models.MerchantRelationship, error)
+	FlagForReview(ctx context.Context, id int, reason string) error
+	GetReviewFlag(ctx context.Context, id int) (bool, *string, error)
+
+	ListSubmissionIDsByDirectorBVN(ctx context.Context, bvn string) ([]int, error)
+	ReencryptSubmissions(ctx context.Context, limit int) (int, error)
+	ReencryptPersons(ctx context.Context, limit int) (int, error)
+}
+
+var _ KYCStore = (*KYCRepository)(nil)
@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/kodra-pay/compliance-service/internal/repositories")
+
+// tracedKYCStore records a span for every call to a KYCStore, named after the store and the
+// statement, such as KYCRepository.GetLatestByMerchant
+type tracedKYCStore struct {
+	store  KYCStore
+	name   string
+	system string
+}
+
+// TraceKYCStore wraps store so its calls show up in request traces. The spans are children of the
+// span in the caller's context.
+func TraceKYCStore(store KYCStore) KYCStore {
+	traced := &tracedKYCStore{store: store, name: "KYCStore", system: "other_sql"}
+	switch store.(type) {
+	case *KYCRepository:
+		traced.name, traced.system = "KYCRepository", "postgresql"
+	case *MemoryKYCStore:
+		traced.name, traced.system = "MemoryKYCStore", "memory"
+	}
+	return traced
+}
+
+func (s *tracedKYCStore) start(ctx context.Context, statement string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, s.name+"."+statement,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", s.system),
+			attribute.String("db.operation.name", statement),
+		))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (s *tracedKYCStore) Create(ctx context.Context, submission *models.KYCSubmission) error {
+	ctx, span := s.start(ctx, "Create")
+	err := s.store.Create(ctx, submission)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedKYCStore) GetByID(ctx context.Context, id int) (*models.KYCSubmission, error) {
+	ctx, span := s.start(ctx, "GetByID")
+	result, err := s.store.GetByID(ctx, id)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedKYCStore) GetLatestByMerchant(ctx context.Context, merchantID int) (*models.KYCSubmission, error) {
+	ctx, span := s.start(ctx, "GetLatestByMerchant")
+	result, err := s.store.GetLatestByMerchant(ctx, merchantID)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedKYCStore) UpdateStatus(ctx context.Context, id int, status string, reviewerID *int, notes *string) error {
+	ctx, span := s.start(ctx, "UpdateStatus")
+	err := s.store.UpdateStatus(ctx, id, status, reviewerID, notes)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedKYCStore) ListByStatus(ctx context.Context, status string, limit int) ([]models.KYCSubmission, error) {
+	ctx, span := s.start(ctx, "ListByStatus")
+	result, err := s.store.ListByStatus(ctx, status, limit)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedKYCStore) PendingBacklog(ctx context.Context) (int, *time.Time, error) {
+	ctx, span := s.start(ctx, "PendingBacklog")
+	count, oldest, err := s.store.PendingBacklog(ctx)
+	endSpan(span, err)
+	return count, oldest, err
+}
+
+func (s *tracedKYCStore) ListPersons(ctx context.Context, submissionID int) ([]models.KYCPerson, error) {
+	ctx, span := s.start(ctx, "ListPersons")
+	result, err := s.store.ListPersons(ctx, submissionID)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedKYCStore) GetPerson(ctx context.Context, id int) (*models.KYCPerson, error) {
+	ctx, span := s.start(ctx, "GetPerson")
+	result, err := s.store.GetPerson(ctx, id)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedKYCStore) UpdatePersonIdentity(ctx context.Context, id int, status string, reference *string) error {
+	ctx, span := s.start(ctx, "UpdatePersonIdentity")
+	err := s.store.UpdatePersonIdentity(ctx, id, status, reference)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedKYCStore) UpdatePersonScreening(ctx context.Context, id int, status string) error {
+	ctx, span := s.start(ctx, "UpdatePersonScreening")
+	err := s.store.UpdatePersonScreening(ctx, id, status)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedKYCStore) CreateDecision(ctx context.Context, decision *models.KYCDecision) error {
+	ctx, span := s.start(ctx, "CreateDecision")
+	err := s.store.CreateDecision(ctx, decision)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedKYCStore) GetDecision(ctx context.Context, id int) (*models.KYCDecision, error) {
+	ctx, span := s.start(ctx, "GetDecision")
+	result, err := s.store.GetDecision(ctx, id)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedKYCStore) GetOpenDecision(ctx context.Context, submissionID int) (*models.KYCDecision, error) {
+	ctx, span := s.start(ctx, "GetOpenDecision")
+	result, err := s.store.GetOpenDecision(ctx, submissionID)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedKYCStore) ResolveDecision(ctx context.Context, decision *models.KYCDecision) error {
+	ctx, span := s.start(ctx, "ResolveDecision")
+	err := s.store.ResolveDecision(ctx, decision)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedKYCStore) ListDecisionsByStatus(ctx context.Context, status string, limit int) ([]models.KYCDecision, error) {
+	ctx, span := s.start(ctx, "ListDecisionsByStatus")
+	result, err := s.store.ListDecisionsByStatus(ctx, status, limit)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedKYCStore) FindRelatedSubmissions(ctx context.Context, submission *models.KYCSubmission) ([]models.MerchantRelationship, error) {
+	ctx, span := s.start(ctx, "FindRelatedSubmissions")
+	result, err := s.store.FindRelatedSubmissions(ctx, submission)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedKYCStore) CreateRelationships(ctx context.Context, links []models.MerchantRelationship) error {
+	ctx, span := s.start(ctx, "CreateRelationships")
+	err := s.store.CreateRelationships(ctx, links)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedKYCStore) ListRelationships(ctx context.Context, submissionID int) ([]models.MerchantRelationship, error) {
+	ctx, span := s.start(ctx, "ListRelationships")
+	result, err := s.store.ListRelationships(ctx, submissionID)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedKYCStore) FlagForReview(ctx context.Context, id int, reason string) error {
+	ctx, span := s.start(ctx, "FlagForReview")
+	err := s.store.FlagForReview(ctx, id, reason)
+	endSpan(span, err)
+	return err
+}
+
+func (s *tracedKYCStore) GetReviewFlag(ctx context.Context, id int) (bool, *string, error) {
+	ctx, span := s.start(ctx, "GetReviewFlag")
+	flagged, reason, err := s.store.GetReviewFlag(ctx, id)
+	endSpan(span, err)
+	return flagged, reason, err
+}
+
+func (s *tracedKYCStore) ListSubmissionIDsByDirectorBVN(ctx context.Context, bvn string) ([]int, error) {
+	ctx, span := s.start(ctx, "ListSubmissionIDsByDirectorBVN")
+	result, err := s.store.ListSubmissionIDsByDirectorBVN(ctx, bvn)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedKYCStore) ReencryptSubmissions(ctx context.Context, limit int) (int, error) {
+	ctx, span := s.start(ctx, "ReencryptSubmissions")
+	result, err := s.store.ReencryptSubmissions(ctx, limit)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedKYCStore) ReencryptPersons(ctx context.Context, limit int) (int, error) {
+	ctx, span := s.start(ctx, "ReencryptPersons")
+	result, err := s.store.ReencryptPersons(ctx, limit)
+	endSpan(span, err)
+	return result, err
+}
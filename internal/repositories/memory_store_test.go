@@ -0,0 +1,131 @@
+package repositories_test
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kodra-pay/compliance-service/internal/encryption"
+	"github.com/kodra-pay/compliance-service/internal/migrate"
+	"github.com/kodra-pay/compliance-service/internal/repositories"
+	"github.com/kodra-pay/compliance-service/internal/repositories/storetest"
+	"github.com/kodra-pay/compliance-service/migrations"
+	_ "github.com/lib/pq"
+)
+
+func TestMemoryKYCStore(t *testing.T) {
+	err := storetest.TestKYCStore(context.Background(), func() repositories.KYCStore {
+		return repositories.NewMemoryKYCStore()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemoryComplianceRepository(t *testing.T) {
+	if err := storetest.TestComplianceRepository(repositories.NewMemoryComplianceRepository); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// The Postgres variants run against the database in TEST_DATABASE_URL. It is migrated and its KYC
+// and compliance tables are truncated before every check, so it must not be shared.
+
+func TestPostgresKYCStore(t *testing.T) {
+	db := testDB(t)
+	enc := encryption.NewFieldEncryptor(testKeyProvider(t))
+
+	err := storetest.TestKYCStore(context.Background(), func() repositories.KYCStore {
+		truncate(t, db, "kyc_decisions", "kyc_persons", "merchant_relationships", "kyc_submissions")
+		return repositories.NewKYCRepository(db, enc)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresComplianceRepository(t *testing.T) {
+	db := testDB(t)
+
+	err := storetest.TestComplianceRepository(func() repositories.ComplianceRepository {
+		truncate(t, db, "kyc_records", "transaction_monitoring_alerts")
+		return repositories.NewPostgresComplianceRepository(db)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPostgresAnonymisation(t *testing.T) {
+	db := testDB(t)
+	enc := encryption.NewFieldEncryptor(testKeyProvider(t))
+
+	err := storetest.TestAnonymisation(context.Background(), func() (repositories.KYCStore, repositories.ComplianceRepository, storetest.Anonymiser) {
+		truncate(t, db, "screening_results", "kyc_decisions", "kyc_persons", "merchant_relationships",
+			"kyc_submissions", "kyc_records", "transaction_monitoring_alerts")
+		return repositories.NewKYCRepository(db, enc), repositories.NewPostgresComplianceRepository(db),
+			repositories.NewPrivacyRepository(db)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testDB opens and migrates the test database, skipping the test when none is configured
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := migrate.Up(context.Background(), db, migrations.Files); err != nil {
+		t.Fatalf("migrate database: %v", err)
+	}
+	return db
+}
+
+func truncate(t *testing.T, db *sql.DB, tables ...string) {
+	t.Helper()
+
+	for _, table := range tables {
+		if _, err := db.Exec(fmt.Sprintf("TRUNCATE %s RESTART IDENTITY CASCADE", table)); err != nil {
+			t.Fatalf("truncate %s: %v", table, err)
+		}
+	}
+}
+
+// testKeyProvider writes a keyfile with random keys for the encrypted KYC fields
+func testKeyProvider(t *testing.T) encryption.KeyProvider {
+	t.Helper()
+
+	key := func() string {
+		data := make([]byte, 32)
+		if _, err := rand.Read(data); err != nil {
+			t.Fatalf("generate key: %v", err)
+		}
+		return base64.StdEncoding.EncodeToString(data)
+	}
+	path := filepath.Join(t.TempDir(), "keys.json")
+	content := fmt.Sprintf(`{"current_version": 1, "keys": {"1": %q}, "index_key": %q}`, key(), key())
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write keyfile: %v", err)
+	}
+
+	provider, err := encryption.NewLocalKeyProvider(path)
+	if err != nil {
+		t.Fatalf("load keyfile: %v", err)
+	}
+	return provider
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/lib/pq"
+)
+
+type PIIAccessRepository struct {
+	db *sql.DB
+}
+
+func NewPIIAccessRepository(db *sql.DB) *PIIAccessRepository {
+	return &PIIAccessRepository{db: db}
+}
+
+// Create records an unmask of personal data
+func (r *PIIAccessRepository) Create(ctx context.Context, access *models.PIIAccess) error {
+	query := `
+		INSERT INTO pii_access_log (actor_id, actor_role, entity, entity_id, fields, reason, ip, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		access.ActorID,
+		access.ActorRole,
+		access.Entity,
+		access.EntityID,
+		pq.Array(access.Fields),
+		access.Reason,
+		access.IP,
+		access.RequestID,
+	).Scan(&access.ID, &access.CreatedAt)
+}
@@ -0,0 +1,407 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/lib/pq"
+)
+
+// anonymisedName replaces personal names on anonymised rows
+const anonymisedName = "[anonymised]"
+
+// PrivacyRepository persists erasure requests and legal holds, and anonymises personal data
+// once it falls out of retention. Anonymised rows are kept so aggregates and audit trails
+// referencing them stay intact.
+type PrivacyRepository struct {
+	db *sql.DB
+}
+
+func NewPrivacyRepository(db *sql.DB) *PrivacyRepository {
+	return &PrivacyRepository{db: db}
+}
+
+const erasureColumns = `
+	id, subject_type, subject_id, reason, requested_by, status, retain_until, status_notes,
+	completed_at, created_at, updated_at
+`
+
+// CreateErasureRequest records a new erasure request
+func (r *PrivacyRepository) CreateErasureRequest(ctx context.Context, req *models.ErasureRequest) error {
+	query := `
+		INSERT INTO erasure_requests (subject_type, subject_id, reason, requested_by, status)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		req.SubjectType,
+		req.SubjectID,
+		req.Reason,
+		req.RequestedBy,
+		req.Status,
+	).Scan(&req.ID, &req.CreatedAt, &req.UpdatedAt)
+}
+
+// UpdateErasureRequest persists the progress of an erasure request
+func (r *PrivacyRepository) UpdateErasureRequest(ctx context.Context, req *models.ErasureRequest) error {
+	query := `
+		UPDATE erasure_requests
+		SET status = $1, retain_until = $2, status_notes = $3, completed_at = $4, updated_at = NOW()
+		WHERE id = $5
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		req.Status,
+		req.RetainUntil,
+		req.StatusNotes,
+		req.CompletedAt,
+		req.ID,
+	).Scan(&req.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("erasure request %w", ErrNotFound)
+	}
+	return err
+}
+
+// GetErasureRequest retrieves an erasure request by ID
+func (r *PrivacyRepository) GetErasureRequest(ctx context.Context, id int) (*models.ErasureRequest, error) {
+	query := `SELECT ` + erasureColumns + ` FROM erasure_requests WHERE id = $1`
+	requests, err := r.queryErasureRequests(ctx, query, id)
+	if err != nil || len(requests) == 0 {
+		return nil, err
+	}
+	return &requests[0], nil
+}
+
+// ListErasureRequestsByStatus retrieves erasure requests in any of the given statuses, oldest first
+func (r *PrivacyRepository) ListErasureRequestsByStatus(ctx context.Context, statuses []string, limit int) ([]models.ErasureRequest, error) {
+	query := `
+		SELECT ` + erasureColumns + `
+		FROM erasure_requests
+		WHERE status = ANY($1)
+		ORDER BY created_at ASC
+		LIMIT $2
+	`
+	return r.queryErasureRequests(ctx, query, pq.Array(statuses), limit)
+}
+
+// ListDueErasureRequests retrieves open erasure requests that need processing: new and held
+// requests, and deferred requests whose retention period has ended
+func (r *PrivacyRepository) ListDueErasureRequests(ctx context.Context, now time.Time, limit int) ([]models.ErasureRequest, error) {
+	query := `
+		SELECT ` + erasureColumns + `
+		FROM erasure_requests
+		WHERE status IN ('received', 'on_hold') OR (status = 'deferred' AND retain_until <= $1)
+		ORDER BY created_at ASC
+		LIMIT $2
+	`
+	return r.queryErasureRequests(ctx, query, now, limit)
+}
+
+func (r *PrivacyRepository) queryErasureRequests(ctx context.Context, query string, args ...interface{}) ([]models.ErasureRequest, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []models.ErasureRequest
+	for rows.Next() {
+		var req models.ErasureRequest
+		if err := rows.Scan(
+			&req.ID,
+			&req.SubjectType,
+			&req.SubjectID,
+			&req.Reason,
+			&req.RequestedBy,
+			&req.Status,
+			&req.RetainUntil,
+			&req.StatusNotes,
+			&req.CompletedAt,
+			&req.CreatedAt,
+			&req.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		requests = append(requests, req)
+	}
+
+	return requests, rows.Err()
+}
+
+// CreateLegalHold places a legal hold on a data subject
+func (r *PrivacyRepository) CreateLegalHold(ctx context.Context, hold *models.LegalHold) error {
+	query := `
+		INSERT INTO legal_holds (subject_type, subject_id, reason, placed_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, placed_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		hold.SubjectType,
+		hold.SubjectID,
+		hold.Reason,
+		hold.PlacedBy,
+	).Scan(&hold.ID, &hold.PlacedAt)
+}
+
+// ReleaseLegalHold releases an active legal hold. It returns nil if the hold does not exist
+// or was already released.
+func (r *PrivacyRepository) ReleaseLegalHold(ctx context.Context, id, releasedBy int) (*models.LegalHold, error) {
+	query := `
+		UPDATE legal_holds
+		SET released_by = $1, released_at = NOW()
+		WHERE id = $2 AND released_at IS NULL
+		RETURNING id, subject_type, subject_id, reason, placed_by, placed_at, released_by, released_at
+	`
+
+	var hold models.LegalHold
+	var releasedByCol sql.NullInt32
+	err := r.db.QueryRowContext(ctx, query, releasedBy, id).Scan(
+		&hold.ID,
+		&hold.SubjectType,
+		&hold.SubjectID,
+		&hold.Reason,
+		&hold.PlacedBy,
+		&hold.PlacedAt,
+		&releasedByCol,
+		&hold.ReleasedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if releasedByCol.Valid {
+		val := int(releasedByCol.Int32)
+		hold.ReleasedBy = &val
+	}
+
+	return &hold, nil
+}
+
+// HasActiveLegalHold reports whether the data subject is under an unreleased legal hold
+func (r *PrivacyRepository) HasActiveLegalHold(ctx context.Context, subjectType string, subjectID int) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM legal_holds
+			WHERE subject_type = $1 AND subject_id = $2 AND released_at IS NULL
+		)
+	`
+
+	var held bool
+	err := r.db.QueryRowContext(ctx, query, subjectType, subjectID).Scan(&held)
+	return held, err
+}
+
+// EndMerchantRelationship starts the retention period of the merchant's KYC submissions.
+// Submissions whose relationship already ended keep their original end date.
+func (r *PrivacyRepository) EndMerchantRelationship(ctx context.Context, merchantID int, endedAt time.Time) error {
+	query := `
+		UPDATE kyc_submissions
+		SET relationship_ended_at = $1, updated_at = NOW()
+		WHERE merchant_id = $2 AND relationship_ended_at IS NULL
+	`
+	_, err := r.db.ExecContext(ctx, query, endedAt, merchantID)
+	return err
+}
+
+// MerchantRelationshipEndedAt returns when the merchant relationship ended, or nil if it is
+// ongoing or the merchant has no KYC submissions
+func (r *PrivacyRepository) MerchantRelationshipEndedAt(ctx context.Context, merchantID int) (*time.Time, error) {
+	query := `
+		SELECT MAX(relationship_ended_at)
+		FROM kyc_submissions
+		WHERE merchant_id = $1 AND anonymised_at IS NULL
+	`
+
+	var endedAt sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query, merchantID).Scan(&endedAt); err != nil {
+		return nil, err
+	}
+	if !endedAt.Valid {
+		return nil, nil
+	}
+	return &endedAt.Time, nil
+}
+
+// UserRetentionAnchors returns the latest KYC record update and the latest alert of a user that
+// are not yet anonymised. Either is nil when the user has no such rows.
+func (r *PrivacyRepository) UserRetentionAnchors(ctx context.Context, userID int) (lastRecord, lastAlert *time.Time, err error) {
+	query := `
+		SELECT
+			(SELECT MAX(updated_at) FROM kyc_records WHERE user_id = $1 AND anonymised_at IS NULL),
+			(SELECT MAX(created_at) FROM transaction_monitoring_alerts WHERE user_id = $1 AND anonymised_at IS NULL)
+	`
+
+	var record, alert sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&record, &alert); err != nil {
+		return nil, nil, err
+	}
+	if record.Valid {
+		lastRecord = &record.Time
+	}
+	if alert.Valid {
+		lastAlert = &alert.Time
+	}
+	return lastRecord, lastAlert, nil
+}
+
+// ListSubmissionIDsByMerchant returns the merchant's KYC submissions that are not yet anonymised
+func (r *PrivacyRepository) ListSubmissionIDsByMerchant(ctx context.Context, merchantID int) ([]int, error) {
+	return queryIDs(ctx, r.db,
+		`SELECT id FROM kyc_submissions WHERE merchant_id = $1 AND anonymised_at IS NULL`, merchantID)
+}
+
+// ListKYCRecordIDsByUser returns the user's KYC records that are not yet anonymised
+func (r *PrivacyRepository) ListKYCRecordIDsByUser(ctx context.Context, userID int) ([]int, error) {
+	return queryIDs(ctx, r.db,
+		`SELECT id FROM kyc_records WHERE user_id = $1 AND anonymised_at IS NULL`, userID)
+}
+
+// ListAlertIDsByUser returns the user's alerts that are not yet anonymised
+func (r *PrivacyRepository) ListAlertIDsByUser(ctx context.Context, userID int) ([]int, error) {
+	return queryIDs(ctx, r.db,
+		`SELECT id FROM transaction_monitoring_alerts WHERE user_id = $1 AND anonymised_at IS NULL`, userID)
+}
+
+// ListExpiredSubmissions returns submissions whose merchant relationship ended before the cutoff,
+// skipping merchants under legal hold
+func (r *PrivacyRepository) ListExpiredSubmissions(ctx context.Context, cutoff time.Time, limit int) ([]int, error) {
+	query := `
+		SELECT k.id FROM kyc_submissions k
+		WHERE k.relationship_ended_at < $1 AND k.anonymised_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM legal_holds h
+				WHERE h.subject_type = 'merchant' AND h.subject_id = k.merchant_id AND h.released_at IS NULL
+			)
+		ORDER BY k.id
+		LIMIT $2
+	`
+	return queryIDs(ctx, r.db, query, cutoff, limit)
+}
+
+// ListExpiredDocuments returns submissions whose documents are past retention, skipping merchants
+// under legal hold
+func (r *PrivacyRepository) ListExpiredDocuments(ctx context.Context, cutoff time.Time, limit int) ([]int, error) {
+	query := `
+		SELECT k.id FROM kyc_submissions k
+		WHERE k.relationship_ended_at < $1 AND k.documents_purged_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM legal_holds h
+				WHERE h.subject_type = 'merchant' AND h.subject_id = k.merchant_id AND h.released_at IS NULL
+			)
+		ORDER BY k.id
+		LIMIT $2
+	`
+	return queryIDs(ctx, r.db, query, cutoff, limit)
+}
+
+// ListExpiredKYCRecords returns KYC records last updated before the cutoff, skipping users under
+// legal hold
+func (r *PrivacyRepository) ListExpiredKYCRecords(ctx context.Context, cutoff time.Time, limit int) ([]int, error) {
+	query := `
+		SELECT k.id FROM kyc_records k
+		WHERE k.updated_at < $1 AND k.anonymised_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM legal_holds h
+				WHERE h.subject_type = 'user' AND h.subject_id = k.user_id AND h.released_at IS NULL
+			)
+		ORDER BY k.id
+		LIMIT $2
+	`
+	return queryIDs(ctx, r.db, query, cutoff, limit)
+}
+
+// ListExpiredAlerts returns alerts raised before the cutoff, skipping users under legal hold
+func (r *PrivacyRepository) ListExpiredAlerts(ctx context.Context, cutoff time.Time, limit int) ([]int, error) {
+	query := `
+		SELECT a.id FROM transaction_monitoring_alerts a
+		WHERE a.created_at < $1 AND a.anonymised_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM legal_holds h
+				WHERE h.subject_type = 'user' AND h.subject_id = a.user_id AND h.released_at IS NULL
+			)
+		ORDER BY a.id
+		LIMIT $2
+	`
+	return queryIDs(ctx, r.db, query, cutoff, limit)
+}
+
+// AnonymiseSubmissions removes the personal data of the submissions, their declared persons and
+// the persons' screening results. Statuses, categories, locations and ownership percentages are
+// kept for reporting.
+func (r *PrivacyRepository) AnonymiseSubmissions(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`UPDATE kyc_submissions
+		SET director_name = $2, director_bvn = '', director_phone = '', director_email = '',
+			tin_number = '', business_address = '', postal_code = '',
+			address_normalized = NULL, address_key = NULL,
+			tin_number_bidx = NULL, director_bvn_bidx = NULL, director_phone_bidx = NULL,
+			director_email_bidx = NULL, documents = '{}',
+			documents_purged_at = COALESCE(documents_purged_at, NOW()),
+			anonymised_at = NOW(), updated_at = NOW()
+		WHERE id = ANY($1)`,
+		`UPDATE screening_results SET subject_name = $2
+		WHERE person_id IN (SELECT id FROM kyc_persons WHERE submission_id = ANY($1))`,
+		`UPDATE kyc_persons
+		SET full_name = $2, bvn = '', phone = '', email = '', bvn_bidx = NULL, phone_bidx = NULL,
+			email_bidx = NULL, date_of_birth = NULL, identity_reference = NULL, updated_at = NOW()
+		WHERE submission_id = ANY($1)`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement, pq.Array(ids), anonymisedName); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// PurgeDocuments removes the document references of the submissions. The files themselves live in
+// the document store, which applies the same policy.
+func (r *PrivacyRepository) PurgeDocuments(ctx context.Context, ids []int) error {
+	query := `
+		UPDATE kyc_submissions
+		SET documents = '{}', documents_purged_at = NOW(), updated_at = NOW()
+		WHERE id = ANY($1)
+	`
+	_, err := r.db.ExecContext(ctx, query, pq.Array(ids))
+	return err
+}
+
+// AnonymiseKYCRecords removes the document numbers of the KYC records. updated_at is left alone
+// as it anchors the retention period.
+func (r *PrivacyRepository) AnonymiseKYCRecords(ctx context.Context, ids []int) error {
+	query := `UPDATE kyc_records SET document_id = '', anonymised_at = NOW() WHERE id = ANY($1)`
+	_, err := r.db.ExecContext(ctx, query, pq.Array(ids))
+	return err
+}
+
+// AnonymiseAlerts removes the free-text descriptions of the alerts, keeping rule and severity
+func (r *PrivacyRepository) AnonymiseAlerts(ctx context.Context, ids []int) error {
+	query := `
+		UPDATE transaction_monitoring_alerts
+		SET description = '', anonymised_at = NOW(), updated_at = NOW()
+		WHERE id = ANY($1)
+	`
+	_, err := r.db.ExecContext(ctx, query, pq.Array(ids))
+	return err
+}
@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/lib/pq"
+)
+
+type ReviewRepository struct {
+	db *sql.DB
+}
+
+func NewReviewRepository(db *sql.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+const reviewColumns = `
+	id, merchant_id, submission_id, risk_tier, status, last_reviewed_at, due_at,
+	opened_at, escalated_at, completed_at, reviewer_id, review_notes, created_at, updated_at
+`
+
+// Create schedules a new KYC review
+func (r *ReviewRepository) Create(ctx context.Context, review *models.KYCReview) error {
+	query := `
+		INSERT INTO kyc_reviews (merchant_id, submission_id, risk_tier, status, last_reviewed_at, due_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		review.MerchantID,
+		review.SubmissionID,
+		review.RiskTier,
+		review.Status,
+		review.LastReviewedAt,
+		review.DueAt,
+	).Scan(&review.ID, &review.CreatedAt, &review.UpdatedAt)
+}
+
+// Update persists the mutable fields of a review
+func (r *ReviewRepository) Update(ctx context.Context, review *models.KYCReview) error {
+	query := `
+		UPDATE kyc_reviews
+		SET submission_id = $1, risk_tier = $2, status = $3, last_reviewed_at = $4, due_at = $5,
+			opened_at = $6, escalated_at = $7, completed_at = $8, reviewer_id = $9,
+			review_notes = $10, updated_at = NOW()
+		WHERE id = $11
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		review.SubmissionID,
+		review.RiskTier,
+		review.Status,
+		review.LastReviewedAt,
+		review.DueAt,
+		review.OpenedAt,
+		review.EscalatedAt,
+		review.CompletedAt,
+		review.ReviewerID,
+		review.ReviewNotes,
+		review.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("kyc review %w", ErrNotFound)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a review by ID
+func (r *ReviewRepository) GetByID(ctx context.Context, id int) (*models.KYCReview, error) {
+	query := `SELECT ` + reviewColumns + ` FROM kyc_reviews WHERE id = $1`
+	reviews, err := r.query(ctx, query, id)
+	if err != nil || len(reviews) == 0 {
+		return nil, err
+	}
+	return &reviews[0], nil
+}
+
+// GetActiveByMerchant retrieves the merchant's review that is not yet completed
+func (r *ReviewRepository) GetActiveByMerchant(ctx context.Context, merchantID int) (*models.KYCReview, error) {
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM kyc_reviews
+		WHERE merchant_id = $1 AND status <> 'completed'
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	reviews, err := r.query(ctx, query, merchantID)
+	if err != nil || len(reviews) == 0 {
+		return nil, err
+	}
+	return &reviews[0], nil
+}
+
+// ListByStatus retrieves reviews in any of the given statuses, earliest due first
+func (r *ReviewRepository) ListByStatus(ctx context.Context, statuses []string, limit int) ([]models.KYCReview, error) {
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM kyc_reviews
+		WHERE status = ANY($1)
+		ORDER BY due_at ASC
+		LIMIT $2
+	`
+	return r.query(ctx, query, pq.Array(statuses), limit)
+}
+
+// ListApprovedWithoutReview returns approved merchants that have no active review scheduled
+func (r *ReviewRepository) ListApprovedWithoutReview(ctx context.Context, limit int) ([]models.ApprovedMerchant, error) {
+	query := `
+		SELECT DISTINCT ON (k.merchant_id) k.merchant_id, k.id, COALESCE(k.reviewed_at, k.updated_at)
+		FROM kyc_submissions k
+		WHERE k.status = 'approved'
+			AND NOT EXISTS (
+				SELECT 1 FROM kyc_reviews r
+				WHERE r.merchant_id = k.merchant_id AND r.status <> 'completed'
+			)
+		ORDER BY k.merchant_id, k.created_at DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var merchants []models.ApprovedMerchant
+	for rows.Next() {
+		var m models.ApprovedMerchant
+		if err := rows.Scan(&m.MerchantID, &m.SubmissionID, &m.ApprovedAt); err != nil {
+			return nil, err
+		}
+		merchants = append(merchants, m)
+	}
+
+	return merchants, rows.Err()
+}
+
+func (r *ReviewRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.KYCReview, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []models.KYCReview
+	for rows.Next() {
+		var review models.KYCReview
+		var reviewerID sql.NullInt32
+
+		if err := rows.Scan(
+			&review.ID,
+			&review.MerchantID,
+			&review.SubmissionID,
+			&review.RiskTier,
+			&review.Status,
+			&review.LastReviewedAt,
+			&review.DueAt,
+			&review.OpenedAt,
+			&review.EscalatedAt,
+			&review.CompletedAt,
+			&reviewerID,
+			&review.ReviewNotes,
+			&review.CreatedAt,
+			&review.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if reviewerID.Valid {
+			val := int(reviewerID.Int32)
+			review.ReviewerID = &val
+		}
+
+		reviews = append(reviews, review)
+	}
+
+	return reviews, rows.Err()
+}
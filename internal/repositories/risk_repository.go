@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+type RiskRepository struct {
+	db *sql.DB
+}
+
+func NewRiskRepository(db *sql.DB) *RiskRepository {
+	return &RiskRepository{db: db}
+}
+
+// Create appends a risk score to the merchant's score history
+func (r *RiskRepository) Create(ctx context.Context, score *models.RiskScore) error {
+	factorsJSON, err := json.Marshal(score.Factors)
+	if err != nil {
+		return fmt.Errorf("failed to marshal risk factors: %w", err)
+	}
+
+	query := `
+		INSERT INTO risk_scores (merchant_id, score, tier, factors, trigger)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		score.MerchantID,
+		score.Score,
+		score.Tier,
+		factorsJSON,
+		score.Trigger,
+	).Scan(&score.ID, &score.CreatedAt)
+}
+
+// GetLatestByMerchant retrieves the current risk score for a merchant
+func (r *RiskRepository) GetLatestByMerchant(ctx context.Context, merchantID int) (*models.RiskScore, error) {
+	scores, err := r.ListByMerchant(ctx, merchantID, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(scores) == 0 {
+		return nil, nil
+	}
+	return &scores[0], nil
+}
+
+// ListByMerchant retrieves the score history for a merchant, newest first
+func (r *RiskRepository) ListByMerchant(ctx context.Context, merchantID int, limit int) ([]models.RiskScore, error) {
+	query := `
+		SELECT id, merchant_id, score, tier, factors, trigger, created_at
+		FROM risk_scores
+		WHERE merchant_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, merchantID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scores []models.RiskScore
+	for rows.Next() {
+		var score models.RiskScore
+		var factorsJSON []byte
+
+		if err := rows.Scan(
+			&score.ID,
+			&score.MerchantID,
+			&score.Score,
+			&score.Tier,
+			&factorsJSON,
+			&score.Trigger,
+			&score.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(factorsJSON, &score.Factors); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal risk factors: %w", err)
+		}
+
+		scores = append(scores, score)
+	}
+
+	return scores, rows.Err()
+}
+
+// ListStaleMerchants returns merchants with a KYC submission whose latest risk score is
+// missing or older than the given time
+func (r *RiskRepository) ListStaleMerchants(ctx context.Context, olderThan time.Time, limit int) ([]int, error) {
+	query := `
+		SELECT k.merchant_id
+		FROM (SELECT DISTINCT merchant_id FROM kyc_submissions) k
+		LEFT JOIN (
+			SELECT merchant_id, MAX(created_at) AS scored_at
+			FROM risk_scores
+			GROUP BY merchant_id
+		) s ON s.merchant_id = k.merchant_id
+		WHERE s.scored_at IS NULL OR s.scored_at < $1
+		ORDER BY s.scored_at ASC NULLS FIRST
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, olderThan, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var merchantIDs []int
+	for rows.Next() {
+		var merchantID int
+		if err := rows.Scan(&merchantID); err != nil {
+			return nil, err
+		}
+		merchantIDs = append(merchantIDs, merchantID)
+	}
+
+	return merchantIDs, rows.Err()
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+type ScreeningRepository struct {
+	db *sql.DB
+}
+
+func NewScreeningRepository(db *sql.DB) *ScreeningRepository {
+	return &ScreeningRepository{db: db}
+}
+
+// Create records a screening result
+func (r *ScreeningRepository) Create(ctx context.Context, result *models.ScreeningResult) error {
+	query := `
+		INSERT INTO screening_results (
+			merchant_id, person_id, subject_name, subject_type, list_type, list_name, match_score, status
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		result.MerchantID,
+		result.PersonID,
+		result.SubjectName,
+		result.SubjectType,
+		result.ListType,
+		result.ListName,
+		result.MatchScore,
+		result.Status,
+	).Scan(&result.ID, &result.CreatedAt)
+}
+
+// ListByMerchant retrieves all screening results for a merchant, newest first
+func (r *ScreeningRepository) ListByMerchant(ctx context.Context, merchantID int) ([]models.ScreeningResult, error) {
+	query := `
+		SELECT id, merchant_id, person_id, subject_name, subject_type, list_type, COALESCE(list_name, ''),
+			match_score, status, created_at
+		FROM screening_results
+		WHERE merchant_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+	return r.query(ctx, query, merchantID)
+}
+
+// ListByPerson retrieves all screening results for a declared person, newest first
+func (r *ScreeningRepository) ListByPerson(ctx context.Context, personID int) ([]models.ScreeningResult, error) {
+	query := `
+		SELECT id, merchant_id, person_id, subject_name, subject_type, list_type, COALESCE(list_name, ''),
+			match_score, status, created_at
+		FROM screening_results
+		WHERE person_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+	return r.query(ctx, query, personID)
+}
+
+func (r *ScreeningRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.ScreeningResult, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []models.ScreeningResult
+	for rows.Next() {
+		var result models.ScreeningResult
+		var personID sql.NullInt32
+
+		if err := rows.Scan(
+			&result.ID,
+			&result.MerchantID,
+			&personID,
+			&result.SubjectName,
+			&result.SubjectType,
+			&result.ListType,
+			&result.ListName,
+			&result.MatchScore,
+			&result.Status,
+			&result.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if personID.Valid {
+			val := int(personID.Int32)
+			result.PersonID = &val
+		}
+
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
@@ -0,0 +1,111 @@
+package storetest
+
+import (
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/kodra-pay/compliance-service/internal/repositories"
+)
+
+// TestComplianceRepository runs the conformance checks against the ComplianceRepository returned
+// by newRepo
+func TestComplianceRepository(newRepo func() repositories.ComplianceRepository) error {
+	return run(map[string]func(*suite){
+		"compliance kyc records":    func(s *suite) { complianceRecords(s, newRepo()) },
+		"compliance alerts":         func(s *suite) { complianceAlerts(s, newRepo()) },
+		"compliance alert severity": func(s *suite) { complianceSeverity(s, newRepo()) },
+	})
+}
+
+func complianceRecords(s *suite, repo repositories.ComplianceRepository) {
+	issued := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	record := &models.KYCRecord{
+		UserID:       201,
+		Status:       "pending",
+		DocumentType: "passport",
+		DocumentID:   "A01234567",
+		IssueDate:    issued,
+		ExpiryDate:   issued.AddDate(10, 0, 0),
+	}
+	if !s.must(repo.CreateKYCRecord(record), "create record") {
+		return
+	}
+	if record.ID == 0 || record.CreatedAt.IsZero() || record.UpdatedAt.IsZero() {
+		s.errorf("create did not set the ID and timestamps: %+v", record)
+	}
+
+	record.Status = "approved"
+	if !s.must(repo.UpdateKYCRecord(record), "update record") {
+		return
+	}
+	got, err := repo.GetKYCRecordByID(record.ID)
+	if s.must(err, "get record") && (got == nil || got.Status != "approved" || got.DocumentID != record.DocumentID) {
+		s.errorf("get returned %+v, want the updated record", got)
+	}
+
+	missing, err := repo.GetKYCRecordByID(record.ID + 1000)
+	if s.must(err, "get missing record") && missing != nil {
+		s.errorf("get of a missing record returned %+v, want nil", missing)
+	}
+	if err := repo.UpdateKYCRecord(&models.KYCRecord{ID: record.ID + 1000, Status: "approved"}); err == nil {
+		s.errorf("updating a missing record did not fail")
+	}
+}
+
+func complianceAlerts(s *suite, repo repositories.ComplianceRepository) {
+	alert := &models.TransactionMonitoringAlert{
+		TransactionID: 301,
+		UserID:        202,
+		RuleTriggered: "velocity",
+		Severity:      "high",
+		Status:        "open",
+		Description:   "ten transfers in a minute",
+	}
+	if !s.must(repo.CreateTransactionMonitoringAlert(alert), "create alert") {
+		return
+	}
+	if alert.ID == 0 || alert.CreatedAt.IsZero() {
+		s.errorf("create did not set the ID and timestamps: %+v", alert)
+	}
+
+	alert.Status = "escalated"
+	if !s.must(repo.UpdateTransactionMonitoringAlert(alert), "update alert") {
+		return
+	}
+	got, err := repo.GetTransactionMonitoringAlertByID(alert.ID)
+	if s.must(err, "get alert") && (got == nil || got.Status != "escalated" || got.RuleTriggered != alert.RuleTriggered) {
+		s.errorf("get returned %+v, want the updated alert", got)
+	}
+
+	missing, err := repo.GetTransactionMonitoringAlertByID(alert.ID + 1000)
+	if s.must(err, "get missing alert") && missing != nil {
+		s.errorf("get of a missing alert returned %+v, want nil", missing)
+	}
+	if err := repo.UpdateTransactionMonitoringAlert(&models.TransactionMonitoringAlert{ID: alert.ID + 1000}); err == nil {
+		s.errorf("updating a missing alert did not fail")
+	}
+}
+
+func complianceSeverity(s *suite, repo repositories.ComplianceRepository) {
+	since := time.Now().Add(-time.Hour)
+	for _, alert := range []models.TransactionMonitoringAlert{
+		{TransactionID: 1, UserID: 203, RuleTriggered: "velocity", Severity: "high", Status: "open"},
+		{TransactionID: 2, UserID: 203, RuleTriggered: "velocity", Severity: "high", Status: "closed"},
+		{TransactionID: 3, UserID: 203, RuleTriggered: "amount", Severity: "low", Status: "open"},
+		{TransactionID: 4, UserID: 203, RuleTriggered: "amount", Severity: "medium", Status: "false_positive"},
+		{TransactionID: 5, UserID: 204, RuleTriggered: "amount", Severity: "high", Status: "open"},
+	} {
+		if !s.must(repo.CreateTransactionMonitoringAlert(&alert), "create alert") {
+			return
+		}
+	}
+
+	counts, err := repo.CountAlertsBySeverity(203, since)
+	if s.must(err, "count alerts") && (len(counts) != 2 || counts["high"] != 2 || counts["low"] != 1) {
+		s.errorf("alert counts are %v, want high=2 low=1 without false positives", counts)
+	}
+	counts, err = repo.CountAlertsBySeverity(203, time.Now().Add(time.Hour))
+	if s.must(err, "count future alerts") && len(counts) != 0 {
+		s.errorf("alert counts since a future time are %v, want none", counts)
+	}
+}
@@ -0,0 +1,412 @@
+package storetest
+
+import (
+	"context"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/kodra-pay/compliance-service/internal/repositories"
+)
+
+// TestKYCStore runs the conformance checks against the KYCStore returned by newStore
+func TestKYCStore(ctx context.Context, newStore func() repositories.KYCStore) error {
+	return run(map[string]func(*suite){
+		"kyc create and get":         func(s *suite) { kycCreateAndGet(ctx, s, newStore()) },
+		"kyc latest and list":        func(s *suite) { kycLatestAndList(ctx, s, newStore()) },
+		"kyc update status":          func(s *suite) { kycUpdateStatus(ctx, s, newStore()) },
+		"kyc persons":                func(s *suite) { kycPersons(ctx, s, newStore()) },
+		"kyc decisions":              func(s *suite) { kycDecisions(ctx, s, newStore()) },
+		"kyc related merchants":      func(s *suite) { kycRelated(ctx, s, newStore()) },
+		"kyc review flag":            func(s *suite) { kycReviewFlag(ctx, s, newStore()) },
+		"kyc director bvn lookup":    func(s *suite) { kycDirectorBVN(ctx, s, newStore()) },
+		"kyc missing submission ids": func(s *suite) { kycMissing(ctx, s, newStore()) },
+		"kyc pending backlog":        func(s *suite) { kycPendingBacklog(ctx, s, newStore()) },
+	})
+}
+
+// submission returns a pending submission for a merchant with one director
+func submission(merchantID int, bvn string) *models.KYCSubmission {
+	return &models.KYCSubmission{
+		MerchantID:       merchantID,
+		BusinessType:     "registered",
+		BusinessName:     "Conformance Traders Ltd",
+		CACNumber:        "RC" + bvn[5:],
+		BusinessAddress:  "12 Allen Avenue " + bvn,
+		City:             "Ikeja",
+		State:            "Lagos",
+		BusinessCategory: "retail",
+		DirectorName:     "Ada Obi",
+		DirectorBVN:      bvn,
+		DirectorPhone:    "+234" + bvn[1:],
+		DirectorEmail:    "director" + bvn + "@example.com",
+		Documents:        map[string]string{"cac_certificate": "s3://kyc/" + bvn},
+		Status:           "pending",
+		Persons: []models.KYCPerson{{
+			Role:            "director",
+			FullName:        "Ada Obi",
+			BVN:             bvn,
+			IdentityStatus:  "pending",
+			ScreeningStatus: "pending",
+		}},
+	}
+}
+
+func create(ctx context.Context, s *suite, store repositories.KYCStore, merchantID int, bvn string) *models.KYCSubmission {
+	sub := submission(merchantID, bvn)
+	if !s.must(store.Create(ctx, sub), "create submission") {
+		return nil
+	}
+	return sub
+}
+
+func kycCreateAndGet(ctx context.Context, s *suite, store repositories.KYCStore) {
+	sub := create(ctx, s, store, 101, "22100000001")
+	if sub == nil {
+		return
+	}
+	if sub.ID == 0 || sub.CreatedAt.IsZero() || sub.UpdatedAt.IsZero() {
+		s.errorf("create did not set the ID and timestamps: id=%d created_at=%v", sub.ID, sub.CreatedAt)
+	}
+	if sub.Persons[0].ID == 0 || sub.Persons[0].SubmissionID != sub.ID {
+		s.errorf("create did not set the person ID and submission ID: %+v", sub.Persons[0])
+	}
+
+	got, err := store.GetByID(ctx, sub.ID)
+	if !s.must(err, "get submission") {
+		return
+	}
+	if got == nil {
+		s.errorf("get returned nil for submission %d", sub.ID)
+		return
+	}
+	if got.MerchantID != sub.MerchantID || got.BusinessName != sub.BusinessName || got.DirectorBVN != sub.DirectorBVN {
+		s.errorf("get returned %+v, want the created submission", got)
+	}
+	if got.Status != "pending" {
+		s.errorf("new submission has status %q, want pending", got.Status)
+	}
+	if got.Documents["cac_certificate"] != sub.Documents["cac_certificate"] {
+		s.errorf("documents were not stored: %v", got.Documents)
+	}
+
+	// callers must not be able to change stored data through a returned value
+	got.Documents["cac_certificate"] = "changed"
+	again, err := store.GetByID(ctx, sub.ID)
+	if s.must(err, "get submission again") && again.Documents["cac_certificate"] == "changed" {
+		s.errorf("changing a returned submission changed the stored one")
+	}
+}
+
+func kycLatestAndList(ctx context.Context, s *suite, store repositories.KYCStore) {
+	first := create(ctx, s, store, 102, "22100000002")
+	second := create(ctx, s, store, 102, "22100000003")
+	other := create(ctx, s, store, 103, "22100000004")
+	if first == nil || second == nil || other == nil {
+		return
+	}
+
+	latest, err := store.GetLatestByMerchant(ctx, 102)
+	if s.must(err, "get latest") && (latest == nil || latest.ID != second.ID) {
+		s.errorf("latest submission of merchant 102 is %v, want %d", latest, second.ID)
+	}
+	missing, err := store.GetLatestByMerchant(ctx, 999)
+	if s.must(err, "get latest of unknown merchant") && missing != nil {
+		s.errorf("latest submission of an unknown merchant is %d, want nil", missing.ID)
+	}
+
+	if !s.must(store.UpdateStatus(ctx, other.ID, "approved", nil, nil), "approve submission") {
+		return
+	}
+	pending, err := store.ListByStatus(ctx, "pending", 10)
+	if !s.must(err, "list pending") {
+		return
+	}
+	if len(pending) != 2 || pending[0].ID != second.ID || pending[1].ID != first.ID {
+		s.errorf("pending submissions are %v, want [%d %d] newest first", submissionIDs(pending), second.ID, first.ID)
+	}
+	limited, err := store.ListByStatus(ctx, "pending", 1)
+	if s.must(err, "list pending with limit") && len(limited) != 1 {
+		s.errorf("list with limit 1 returned %d submissions", len(limited))
+	}
+	none, err := store.ListByStatus(ctx, "rejected", 10)
+	if s.must(err, "list rejected") && len(none) != 0 {
+		s.errorf("list rejected returned %v, want none", submissionIDs(none))
+	}
+}
+
+func kycUpdateStatus(ctx context.Context, s *suite, store repositories.KYCStore) {
+	sub := create(ctx, s, store, 104, "22100000005")
+	if sub == nil {
+		return
+	}
+
+	reviewer, notes := 7, "documents verified"
+	if !s.must(store.UpdateStatus(ctx, sub.ID, "approved", &reviewer, &notes), "update status") {
+		return
+	}
+	got, err := store.GetByID(ctx, sub.ID)
+	if !s.must(err, "get updated submission") {
+		return
+	}
+	if got.Status != "approved" || got.ReviewerID == nil || *got.ReviewerID != reviewer ||
+		got.ReviewNotes == nil || *got.ReviewNotes != notes || got.ReviewedAt == nil {
+		s.errorf("update status stored %+v", got)
+	}
+
+	if err := store.UpdateStatus(ctx, sub.ID+1000, "approved", nil, nil); err == nil {
+		s.errorf("updating a missing submission did not fail")
+	}
+}
+
+func kycPendingBacklog(ctx context.Context, s *suite, store repositories.KYCStore) {
+	count, oldest, err := store.PendingBacklog(ctx)
+	if s.must(err, "read empty backlog") && (count != 0 || oldest != nil) {
+		s.errorf("empty store has a backlog of %d since %v", count, oldest)
+	}
+
+	first := create(ctx, s, store, 110, "22100000011")
+	second := create(ctx, s, store, 111, "22100000012")
+	decided := create(ctx, s, store, 112, "22100000013")
+	if first == nil || second == nil || decided == nil {
+		return
+	}
+	if !s.must(store.UpdateStatus(ctx, decided.ID, "rejected", nil, nil), "reject submission") {
+		return
+	}
+
+	count, oldest, err = store.PendingBacklog(ctx)
+	if !s.must(err, "read backlog") {
+		return
+	}
+	if count != 2 || oldest == nil || !oldest.Equal(first.CreatedAt) {
+		s.errorf("backlog is %d since %v, want 2 since %v", count, oldest, first.CreatedAt)
+	}
+}
+
+func kycPersons(ctx context.Context, s *suite, store repositories.KYCStore) {
+	sub := submission(105, "22100000006")
+	sub.Persons = append(sub.Persons, models.KYCPerson{
+		Role: "shareholder", FullName: "Emeka Obi", IdentityStatus: "pending", ScreeningStatus: "pending",
+	})
+	if !s.must(store.Create(ctx, sub), "create submission") {
+		return
+	}
+
+	persons, err := store.ListPersons(ctx, sub.ID)
+	if !s.must(err, "list persons") {
+		return
+	}
+	if len(persons) != 2 || persons[0].ID != sub.Persons[0].ID || persons[1].ID != sub.Persons[1].ID {
+		s.errorf("persons are %+v, want both persons in ID order", persons)
+	}
+
+	id := sub.Persons[0].ID
+	reference := "nimc-123"
+	if !s.must(store.UpdatePersonIdentity(ctx, id, "verified", &reference), "update identity") ||
+		!s.must(store.UpdatePersonScreening(ctx, id, "clear"), "update screening") {
+		return
+	}
+	person, err := store.GetPerson(ctx, id)
+	if s.must(err, "get person") && (person == nil || person.IdentityStatus != "verified" ||
+		person.IdentityReference == nil || *person.IdentityReference != reference || person.ScreeningStatus != "clear") {
+		s.errorf("person after updates is %+v", person)
+	}
+
+	missing, err := store.GetPerson(ctx, id+1000)
+	if s.must(err, "get missing person") && missing != nil {
+		s.errorf("get of a missing person returned %+v, want nil", missing)
+	}
+	if err := store.UpdatePersonIdentity(ctx, id+1000, "verified", nil); err == nil {
+		s.errorf("updating the identity of a missing person did not fail")
+	}
+	if err := store.UpdatePersonScreening(ctx, id+1000, "clear"); err == nil {
+		s.errorf("updating the screening of a missing person did not fail")
+	}
+}
+
+func kycDecisions(ctx context.Context, s *suite, store repositories.KYCStore) {
+	first := create(ctx, s, store, 106, "22100000007")
+	second := create(ctx, s, store, 107, "22100000008")
+	if first == nil || second == nil {
+		return
+	}
+
+	propose := func(sub *models.KYCSubmission) *models.KYCDecision {
+		decision := &models.KYCDecision{
+			SubmissionID:   sub.ID,
+			MerchantID:     sub.MerchantID,
+			ProposedStatus: "approved",
+			ProposedBy:     7,
+			Status:         models.DecisionStatusProposed,
+		}
+		if !s.must(store.CreateDecision(ctx, decision), "create decision") {
+			return nil
+		}
+		return decision
+	}
+	older, newer := propose(first), propose(second)
+	if older == nil || newer == nil {
+		return
+	}
+	if older.ID == 0 || older.ProposedAt.IsZero() {
+		s.errorf("create decision did not set the ID and proposed_at: %+v", older)
+	}
+
+	open, err := store.GetOpenDecision(ctx, first.ID)
+	if s.must(err, "get open decision") && (open == nil || open.ID != older.ID) {
+		s.errorf("open decision of submission %d is %+v, want %d", first.ID, open, older.ID)
+	}
+	proposed, err := store.ListDecisionsByStatus(ctx, models.DecisionStatusProposed, 10)
+	if s.must(err, "list proposed decisions") &&
+		(len(proposed) != 2 || proposed[0].ID != older.ID || proposed[1].ID != newer.ID) {
+		s.errorf("proposed decisions are %+v, want oldest first", proposed)
+	}
+
+	checker, final := 8, "approved"
+	older.Status = models.DecisionStatusConfirmed
+	older.FinalStatus = &final
+	older.CheckedBy = &checker
+	if !s.must(store.ResolveDecision(ctx, older), "resolve decision") {
+		return
+	}
+	if err := store.ResolveDecision(ctx, older); err == nil {
+		s.errorf("resolving a decision twice did not fail")
+	}
+	resolved, err := store.GetDecision(ctx, older.ID)
+	if s.must(err, "get decision") && (resolved == nil || resolved.Status != models.DecisionStatusConfirmed ||
+		resolved.CheckedBy == nil || *resolved.CheckedBy != checker) {
+		s.errorf("resolved decision is %+v", resolved)
+	}
+	open, err = store.GetOpenDecision(ctx, first.ID)
+	if s.must(err, "get open decision after resolving") && open != nil {
+		s.errorf("submission %d still has open decision %d", first.ID, open.ID)
+	}
+
+	missing, err := store.GetDecision(ctx, newer.ID+1000)
+	if s.must(err, "get missing decision") && missing != nil {
+		s.errorf("get of a missing decision returned %+v, want nil", missing)
+	}
+}
+
+func kycRelated(ctx context.Context, s *suite, store repositories.KYCStore) {
+	sub := create(ctx, s, store, 108, "22100000009")
+	sameMerchant := create(ctx, s, store, 108, "22100000009")
+	if sub == nil || sameMerchant == nil {
+		return
+	}
+
+	// The same BVN and phone, formatted differently, on another merchant's submission
+	related := submission(109, "22100000010")
+	related.DirectorBVN = "221-0000-0009"
+	related.Persons[0].Phone = "0" + sub.DirectorPhone[4:]
+	if !s.must(store.Create(ctx, related), "create related submission") {
+		return
+	}
+
+	links, err := store.FindRelatedSubmissions(ctx, sub)
+	if !s.must(err, "find related submissions") {
+		return
+	}
+	types := make(map[string]bool)
+	for _, link := range links {
+		if link.RelatedSubmissionID != related.ID || link.RelatedMerchantID != 109 || link.SubmissionID != sub.ID {
+			s.errorf("unexpected link %+v", link)
+		}
+		types[link.LinkType] = true
+	}
+	if !types[models.LinkTypeBVN] || !types[models.LinkTypePhone] || len(types) != 2 {
+		s.errorf("link types are %v, want bvn and phone", types)
+	}
+
+	if !s.must(store.CreateRelationships(ctx, links), "create relationships") ||
+		!s.must(store.CreateRelationships(ctx, links), "create relationships again") {
+		return
+	}
+	stored, err := store.ListRelationships(ctx, related.ID)
+	if !s.must(err, "list relationships") {
+		return
+	}
+	if len(stored) != len(links) {
+		s.errorf("%d relationships stored for %d links, duplicates must be ignored", len(stored), len(links))
+	}
+	for _, link := range stored {
+		if link.SubmissionID != related.ID || link.RelatedSubmissionID != sub.ID || link.ID == 0 || link.CreatedAt.IsZero() {
+			s.errorf("relationship %+v is not oriented from submission %d", link, related.ID)
+		}
+	}
+}
+
+func kycReviewFlag(ctx context.Context, s *suite, store repositories.KYCStore) {
+	sub := create(ctx, s, store, 110, "22100000011")
+	if sub == nil {
+		return
+	}
+
+	flagged, reason, err := store.GetReviewFlag(ctx, sub.ID)
+	if s.must(err, "get review flag") && (flagged || reason != nil) {
+		s.errorf("new submission is flagged: %v %v", flagged, reason)
+	}
+	if !s.must(store.FlagForReview(ctx, sub.ID, "shares bvn"), "flag") ||
+		!s.must(store.FlagForReview(ctx, sub.ID, "shares address"), "flag again") {
+		return
+	}
+	flagged, reason, err = store.GetReviewFlag(ctx, sub.ID)
+	if s.must(err, "get review flag after flagging") && (!flagged || reason == nil || *reason != "shares bvn; shares address") {
+		s.errorf("review flag is %v %v, want accumulated reasons", flagged, reason)
+	}
+	if _, _, err := store.GetReviewFlag(ctx, sub.ID+1000); err == nil {
+		s.errorf("getting the review flag of a missing submission did not fail")
+	}
+}
+
+func kycDirectorBVN(ctx context.Context, s *suite, store repositories.KYCStore) {
+	sub := create(ctx, s, store, 111, "22100000012")
+	withPerson := submission(112, "22100000013")
+	withPerson.Persons[0].BVN = "22100000012"
+	if sub == nil || !s.must(store.Create(ctx, withPerson), "create submission") {
+		return
+	}
+
+	ids, err := store.ListSubmissionIDsByDirectorBVN(ctx, "221 0000 0012")
+	if s.must(err, "list by director bvn") && !sameIDs(ids, []int{sub.ID, withPerson.ID}) {
+		s.errorf("submissions with the bvn are %v, want %d and %d", ids, sub.ID, withPerson.ID)
+	}
+	ids, err = store.ListSubmissionIDsByDirectorBVN(ctx, "")
+	if s.must(err, "list by empty bvn") && len(ids) != 0 {
+		s.errorf("an empty bvn matched %v", ids)
+	}
+}
+
+func kycMissing(ctx context.Context, s *suite, store repositories.KYCStore) {
+	got, err := store.GetByID(ctx, 424242)
+	if s.must(err, "get missing submission") && got != nil {
+		s.errorf("get of a missing submission returned %+v, want nil", got)
+	}
+	persons, err := store.ListPersons(ctx, 424242)
+	if s.must(err, "list persons of a missing submission") && len(persons) != 0 {
+		s.errorf("a missing submission has persons %+v", persons)
+	}
+}
+
+func submissionIDs(submissions []models.KYCSubmission) []int {
+	ids := make([]int, 0, len(submissions))
+	for _, submission := range submissions {
+		ids = append(ids, submission.ID)
+	}
+	return ids
+}
+
+func sameIDs(got, want []int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	seen := make(map[int]bool, len(got))
+	for _, id := range got {
+		seen[id] = true
+	}
+	for _, id := range want {
+		if !seen[id] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package storetest
+
+import (
+	"context"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/kodra-pay/compliance-service/internal/repositories"
+)
+
+// Anonymiser removes the personal data of stored records, like repositories.PrivacyRepository
+type Anonymiser interface {
+	AnonymiseSubmissions(ctx context.Context, ids []int) error
+	AnonymiseKYCRecords(ctx context.Context, ids []int) error
+	AnonymiseAlerts(ctx context.Context, ids []int) error
+}
+
+// TestAnonymisation checks that records anonymised through the Anonymiser returned by newStores can
+// still be read from its stores
+func TestAnonymisation(ctx context.Context, newStores func() (repositories.KYCStore, repositories.ComplianceRepository, Anonymiser)) error {
+	return run(map[string]func(*suite){
+		"anonymised submission": func(s *suite) {
+			store, _, anonymiser := newStores()
+			anonymisedSubmission(ctx, s, store, anonymiser)
+		},
+		"anonymised compliance records": func(s *suite) {
+			_, repo, anonymiser := newStores()
+			anonymisedCompliance(ctx, s, repo, anonymiser)
+		},
+	})
+}
+
+func anonymisedSubmission(ctx context.Context, s *suite, store repositories.KYCStore, anonymiser Anonymiser) {
+	sub := submission(401, "22100000401")
+	sub.PostalCode = "100001"
+	if !s.must(store.Create(ctx, sub), "create submission") {
+		return
+	}
+	if !s.must(anonymiser.AnonymiseSubmissions(ctx, []int{sub.ID}), "anonymise submission") {
+		return
+	}
+
+	got, err := store.GetByID(ctx, sub.ID)
+	if s.must(err, "get anonymised submission") {
+		if got == nil || got.DirectorBVN != "" || got.PostalCode != "" || got.BusinessAddress != "" {
+			s.errorf("get returned %+v, want the submission without personal data", got)
+		}
+	}
+	latest, err := store.GetLatestByMerchant(ctx, sub.MerchantID)
+	if s.must(err, "get latest anonymised submission") && (latest == nil || latest.ID != sub.ID) {
+		s.errorf("get latest returned %+v, want submission %d", latest, sub.ID)
+	}
+	pending, err := store.ListByStatus(ctx, "pending", 10)
+	if s.must(err, "list anonymised submissions") && !sameIDs(submissionIDs(pending), []int{sub.ID}) {
+		s.errorf("list returned submissions %v, want [%d]", submissionIDs(pending), sub.ID)
+	}
+	persons, err := store.ListPersons(ctx, sub.ID)
+	if s.must(err, "list anonymised persons") && (len(persons) != 1 || persons[0].BVN != "") {
+		s.errorf("list persons returned %+v, want one person without a BVN", persons)
+	}
+}
+
+func anonymisedCompliance(ctx context.Context, s *suite, repo repositories.ComplianceRepository, anonymiser Anonymiser) {
+	issued := time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)
+	record := &models.KYCRecord{
+		UserID:       401,
+		Status:       "approved",
+		DocumentType: "passport",
+		DocumentID:   "A07654321",
+		IssueDate:    issued,
+		ExpiryDate:   issued.AddDate(10, 0, 0),
+	}
+	alert := &models.TransactionMonitoringAlert{
+		TransactionID: 402,
+		UserID:        401,
+		RuleTriggered: "velocity",
+		Severity:      "low",
+		Status:        "closed",
+		Description:   "transfers to a new beneficiary",
+	}
+	if !s.must(repo.CreateKYCRecord(record), "create record") || !s.must(repo.CreateTransactionMonitoringAlert(alert), "create alert") {
+		return
+	}
+	if !s.must(anonymiser.AnonymiseKYCRecords(ctx, []int{record.ID}), "anonymise record") ||
+		!s.must(anonymiser.AnonymiseAlerts(ctx, []int{alert.ID}), "anonymise alert") {
+		return
+	}
+
+	gotRecord, err := repo.GetKYCRecordByID(record.ID)
+	if s.must(err, "get anonymised record") && (gotRecord == nil || gotRecord.DocumentID != "") {
+		s.errorf("get returned %+v, want the record without its document number", gotRecord)
+	}
+	gotAlert, err := repo.GetTransactionMonitoringAlertByID(alert.ID)
+	if s.must(err, "get anonymised alert") && (gotAlert == nil || gotAlert.Description != "") {
+		s.errorf("get returned %+v, want the alert without its description", gotAlert)
+	}
+}
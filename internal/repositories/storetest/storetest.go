@@ -0,0 +1,59 @@
+// Package storetest is the conformance suite for the store implementations. It checks that a store
+// behaves like the Postgres repositories: ordering by created_at, nil results for missing records
+// and errors for updates that affect no rows.
+//
+// Like testing/fstest, the checks report failures as an error rather than through *testing.T, so
+// the same suite runs against the in-memory stores and against a migrated Postgres database:
+//
+//	if err := storetest.TestKYCStore(ctx, newStore); err != nil {
+//		t.Fatal(err)
+//	}
+//
+// Every check asks the factory for a new store and expects it to be empty; a Postgres factory
+// truncates the tables first.
+package storetest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// suite collects the failures of one run
+type suite struct {
+	name     string
+	failures []error
+}
+
+func (s *suite) errorf(format string, args ...interface{}) {
+	s.failures = append(s.failures, fmt.Errorf(s.name+": "+format, args...))
+}
+
+// must records a failure for an unexpected error and reports whether the check can go on
+func (s *suite) must(err error, action string) bool {
+	if err != nil {
+		s.errorf("%s: %v", action, err)
+		return false
+	}
+	return true
+}
+
+// run runs each named check and joins the failures
+func run(checks map[string]func(*suite)) error {
+	var failures []error
+	for _, name := range sortedNames(checks) {
+		s := &suite{name: name}
+		checks[name](s)
+		failures = append(failures, s.failures...)
+	}
+	return errors.Join(failures...)
+}
+
+func sortedNames(checks map[string]func(*suite)) []string {
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/lib/pq"
+)
+
+type TransactionRepository struct {
+	db *sql.DB
+}
+
+func NewTransactionRepository(db *sql.DB) *TransactionRepository {
+	return &TransactionRepository{db: db}
+}
+
+// Create records a transaction in the monitoring store
+func (r *TransactionRepository) Create(ctx context.Context, txn *models.MonitoredTransaction) error {
+	query := `
+		INSERT INTO monitored_transactions (
+			reference, customer_id, customer_type, customer_name, channel, direction,
+			amount, currency, counterparty_name, counterparty_account, occurred_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRowContext(ctx, query,
+		txn.Reference,
+		txn.CustomerID,
+		txn.CustomerType,
+		txn.CustomerName,
+		txn.Channel,
+		txn.Direction,
+		txn.Amount,
+		txn.Currency,
+		txn.CounterpartyName,
+		txn.CounterpartyAccount,
+		txn.OccurredAt,
+	).Scan(&txn.ID, &txn.CreatedAt)
+}
+
+// DailyTotalsByCustomer aggregates the transactions in [from, to) per customer for the given channels.
+// Only transactions in currency are counted, so amounts in different currencies are never summed.
+func (r *TransactionRepository) DailyTotalsByCustomer(ctx context.Context, from, to time.Time, channels []string, currency string) ([]models.CustomerDailyTotal, error) {
+	query := `
+		SELECT customer_id, customer_type, COALESCE(MAX(customer_name), ''),
+			COUNT(*), COALESCE(SUM(amount), 0)
+		FROM monitored_transactions
+		WHERE occurred_at >= $1 AND occurred_at < $2 AND channel = ANY($3) AND currency = $4
+		GROUP BY customer_id, customer_type
+		ORDER BY customer_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, from, to, pq.Array(channels), currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []models.CustomerDailyTotal
+	for rows.Next() {
+		var total models.CustomerDailyTotal
+		if err := rows.Scan(
+			&total.CustomerID,
+			&total.CustomerType,
+			&total.CustomerName,
+			&total.TransactionCount,
+			&total.TotalAmount,
+		); err != nil {
+			return nil, err
+		}
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
+
+// Summary aggregates a customer's transactions in [from, to) in the given currency
+func (r *TransactionRepository) Summary(ctx context.Context, customerID int, from, to time.Time, currency string) (*models.TransactionSummary, error) {
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(amount), 0),
+			COALESCE(SUM(amount) FILTER (WHERE channel = 'cash'), 0)
+		FROM monitored_transactions
+		WHERE customer_id = $1 AND occurred_at >= $2 AND occurred_at < $3 AND currency = $4
+	`
+
+	var summary models.TransactionSummary
+	if err := r.db.QueryRowContext(ctx, query, customerID, from, to, currency).Scan(
+		&summary.TransactionCount,
+		&summary.TotalAmount,
+		&summary.CashAmount,
+	); err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+// ListByCustomer retrieves a customer's transactions in [from, to) for the given channels and currency
+func (r *TransactionRepository) ListByCustomer(ctx context.Context, customerID int, from, to time.Time, channels []string, currency string) ([]models.MonitoredTransaction, error) {
+	query := `
+		SELECT id, reference, customer_id, customer_type, COALESCE(customer_name, ''), channel,
+			direction, amount, currency, COALESCE(counterparty_name, ''),
+			COALESCE(counterparty_account, ''), occurred_at, created_at
+		FROM monitored_transactions
+		WHERE customer_id = $1 AND occurred_at >= $2 AND occurred_at < $3 AND channel = ANY($4)
+			AND currency = $5
+		ORDER BY occurred_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, customerID, from, to, pq.Array(channels), currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txns []models.MonitoredTransaction
+	for rows.Next() {
+		var txn models.MonitoredTransaction
+		if err := rows.Scan(
+			&txn.ID,
+			&txn.Reference,
+			&txn.CustomerID,
+			&txn.CustomerType,
+			&txn.CustomerName,
+			&txn.Channel,
+			&txn.Direction,
+			&txn.Amount,
+			&txn.Currency,
+			&txn.CounterpartyName,
+			&txn.CounterpartyAccount,
+			&txn.OccurredAt,
+			&txn.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		txns = append(txns, txn)
+	}
+
+	return txns, rows.Err()
+}
@@ -0,0 +1,273 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/kodra-pay/compliance-service/internal/app"
+	"github.com/kodra-pay/compliance-service/internal/config"
+	"github.com/kodra-pay/compliance-service/internal/handlers"
+	"github.com/kodra-pay/compliance-service/internal/middleware"
+	"github.com/kodra-pay/compliance-service/internal/routes"
+)
+
+const testSecret = "routes-test-secret"
+
+// newServer registers the routes on an App wired on the in-memory stores
+func newServer(t *testing.T) *fiber.App {
+	t.Helper()
+
+	// The merchant service only has to accept the KYC status sync
+	merchantService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(merchantService.Close)
+
+	t.Setenv("STORAGE", config.StorageMemory)
+	t.Setenv("JWT_SECRET", testSecret)
+	t.Setenv("MERCHANT_SERVICE_URL", merchantService.URL)
+	t.Setenv("ADDRESS_GEOCODE", "false")
+	t.Setenv("REDIS_ADDR", "")
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("validate config: %v", err)
+	}
+
+	a := app.NewInMemory(cfg)
+	t.Cleanup(func() { a.Close() })
+
+	server := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	routes.Register(server, a)
+	return server
+}
+
+// token signs a bearer token for the role; merchantID is only set for merchants
+func token(t *testing.T, userID int, role string, merchantID int) string {
+	t.Helper()
+
+	claims := middleware.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.Itoa(userID),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Role:       role,
+		MerchantID: merchantID,
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
+// do sends a request and decodes the JSON response body into out when it is not nil
+func do(t *testing.T, server *fiber.App, method, path, bearer, body string, out interface{}) int {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+	if bearer != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+bearer)
+	}
+	resp, err := server.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("%s %s: read body: %v", method, path, err)
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			t.Fatalf("%s %s: decode %q: %v", method, path, data, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+const submission = `{
+	"merchant_id": 42,
+	"business_type": "registered",
+	"business_name": "Adaeze Foods Ltd",
+	"cac_number": "RC123456",
+	"business_address": "12 Admiralty Way, Lekki Phase 1",
+	"city": "Lagos",
+	"state": "Lagos",
+	"business_category": "food_and_beverage",
+	"director_name": "Adaeze Okafor",
+	"director_bvn": "22123456789",
+	"director_phone": "+2348012345678",
+	"director_email": "adaeze@example.com",
+	"documents": {"cac_certificate": "s3://kyc/42/cac.pdf"}
+}`
+
+func TestKYCSubmitStatusUpdate(t *testing.T) {
+	server := newServer(t)
+	merchant := token(t, 1001, middleware.RoleMerchant, 42)
+	reviewer := token(t, 7, middleware.RoleReviewer, 0)
+
+	var submitted struct {
+		SubmissionID int    `json:"submission_id"`
+		Status       string `json:"status"`
+	}
+	if status := do(t, server, fiber.MethodPost, "/kyc/submit", merchant, submission, &submitted); status != fiber.StatusCreated {
+		t.Fatalf("submit: got status %d, want %d", status, fiber.StatusCreated)
+	}
+	if submitted.SubmissionID == 0 || submitted.Status != "pending" {
+		t.Fatalf("submit: got %+v, want a pending submission", submitted)
+	}
+
+	var kycStatus struct {
+		MerchantID int    `json:"merchant_id"`
+		Status     string `json:"status"`
+		ReviewerID int    `json:"reviewer_id"`
+	}
+	if status := do(t, server, fiber.MethodGet, "/kyc/status/42", merchant, "", &kycStatus); status != fiber.StatusOK {
+		t.Fatalf("status: got status %d, want %d", status, fiber.StatusOK)
+	}
+	if kycStatus.MerchantID != 42 || kycStatus.Status != "pending" {
+		t.Fatalf("status: got %+v, want merchant 42 pending", kycStatus)
+	}
+
+	update := `{"merchant_id": 42, "status": "rejected", "review_notes": "CAC certificate is illegible"}`
+	if status := do(t, server, fiber.MethodPost, "/kyc/update", reviewer, update, nil); status != fiber.StatusOK {
+		t.Fatalf("update: got status %d, want %d", status, fiber.StatusOK)
+	}
+
+	if status := do(t, server, fiber.MethodGet, "/kyc/status/42", merchant, "", &kycStatus); status != fiber.StatusOK {
+		t.Fatalf("status after update: got status %d, want %d", status, fiber.StatusOK)
+	}
+	if kycStatus.Status != "rejected" || kycStatus.ReviewerID != 7 {
+		t.Fatalf("status after update: got %+v, want rejected by reviewer 7", kycStatus)
+	}
+}
+
+func TestKYCApproval(t *testing.T) {
+	server := newServer(t)
+	merchant := token(t, 1001, middleware.RoleMerchant, 42)
+	reviewer := token(t, 7, middleware.RoleReviewer, 0)
+
+	var submitted struct {
+		SubmissionID int `json:"submission_id"`
+	}
+	if status := do(t, server, fiber.MethodPost, "/kyc/submit", merchant, submission, &submitted); status != fiber.StatusCreated {
+		t.Fatalf("submit: got status %d, want %d", status, fiber.StatusCreated)
+	}
+
+	approve := `{"merchant_id": 42, "status": "approved"}`
+	if status := do(t, server, fiber.MethodPost, "/kyc/update", reviewer, approve, nil); status != fiber.StatusConflict {
+		t.Fatalf("approve before identity verification: got status %d, want %d", status, fiber.StatusConflict)
+	}
+
+	// Persons cannot be screened without Postgres, so identity verification is enough
+	var persons struct {
+		Persons []struct {
+			ID int `json:"id"`
+		} `json:"persons"`
+	}
+	path := "/kyc/submissions/" + strconv.Itoa(submitted.SubmissionID) + "/persons"
+	if status := do(t, server, fiber.MethodGet, path, reviewer, "", &persons); status != fiber.StatusOK || len(persons.Persons) == 0 {
+		t.Fatalf("list persons: got status %d and %d persons, want 200 and the director", status, len(persons.Persons))
+	}
+	for _, person := range persons.Persons {
+		path := "/kyc/persons/" + strconv.Itoa(person.ID) + "/identity"
+		if status := do(t, server, fiber.MethodPost, path, reviewer, `{"status": "verified"}`, nil); status != fiber.StatusOK {
+			t.Fatalf("verify person %d: got status %d, want %d", person.ID, status, fiber.StatusOK)
+		}
+	}
+
+	if status := do(t, server, fiber.MethodPost, "/kyc/update", reviewer, approve, nil); status != fiber.StatusOK {
+		t.Fatalf("approve: got status %d, want %d", status, fiber.StatusOK)
+	}
+	var kycStatus struct {
+		Status string `json:"status"`
+	}
+	if status := do(t, server, fiber.MethodGet, "/kyc/status/42", merchant, "", &kycStatus); status != fiber.StatusOK || kycStatus.Status != "approved" {
+		t.Fatalf("status after approval: got status %d and %q, want 200 approved", status, kycStatus.Status)
+	}
+}
+
+func TestKYCRoutesAuthorization(t *testing.T) {
+	server := newServer(t)
+	merchant := token(t, 1001, middleware.RoleMerchant, 42)
+	reviewer := token(t, 7, middleware.RoleReviewer, 0)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		bearer string
+		body   string
+		want   int
+	}{
+		{"missing token", fiber.MethodGet, "/kyc/status/42", "", "", fiber.StatusUnauthorized},
+		{"other merchant's status", fiber.MethodGet, "/kyc/status/43", merchant, "", fiber.StatusForbidden},
+		{"merchant updating status", fiber.MethodPost, "/kyc/update", merchant, `{"merchant_id": 42, "status": "approved"}`, fiber.StatusForbidden},
+		{"status before submission", fiber.MethodGet, "/kyc/status/42", merchant, "", fiber.StatusNotFound},
+		{"postgres-only route", fiber.MethodGet, "/risk/merchants/42", reviewer, "", fiber.StatusNotImplemented},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := do(t, server, tt.method, tt.path, tt.bearer, tt.body, nil); status != tt.want {
+				t.Errorf("got status %d, want %d", status, tt.want)
+			}
+		})
+	}
+}
+
+func TestKYCSubmitRetryAfterRateLimit(t *testing.T) {
+	// One submission per half second, so a retry after Retry-After is let through quickly
+	t.Setenv("RATE_LIMIT_MERCHANT_PER_MINUTE", "120")
+	t.Setenv("RATE_LIMIT_MERCHANT_BURST", "1")
+	server := newServer(t)
+	merchant := token(t, 1001, middleware.RoleMerchant, 42)
+
+	submit := func(key string) *http.Response {
+		req := httptest.NewRequest(fiber.MethodPost, "/kyc/submit", strings.NewReader(submission))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+merchant)
+		req.Header.Set(middleware.HeaderIdempotencyKey, key)
+		resp, err := server.Test(req, -1)
+		if err != nil {
+			t.Fatalf("submit: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	// An invalid submission uses up the merchant's token
+	if status := do(t, server, fiber.MethodPost, "/kyc/submit", merchant, `{}`, nil); status == fiber.StatusTooManyRequests {
+		t.Fatalf("first submission was rate limited")
+	}
+
+	limited := submit("retry-after-limit")
+	if limited.StatusCode != fiber.StatusTooManyRequests || limited.Header.Get(fiber.HeaderRetryAfter) == "" {
+		t.Fatalf("got status %d with Retry-After %q, want 429 with Retry-After", limited.StatusCode, limited.Header.Get(fiber.HeaderRetryAfter))
+	}
+
+	time.Sleep(600 * time.Millisecond)
+	retried := submit("retry-after-limit")
+	if retried.StatusCode != fiber.StatusCreated || retried.Header.Get(middleware.HeaderIdempotentReplayed) != "" {
+		t.Fatalf("retry: got status %d, replayed %q, want a new 201", retried.StatusCode, retried.Header.Get(middleware.HeaderIdempotentReplayed))
+	}
+
+	replayed := submit("retry-after-limit")
+	if replayed.StatusCode != fiber.StatusCreated || replayed.Header.Get(middleware.HeaderIdempotentReplayed) != "true" {
+		t.Fatalf("second retry: got status %d, replayed %q, want the stored 201", replayed.StatusCode, replayed.Header.Get(middleware.HeaderIdempotentReplayed))
+	}
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// goAML report structures. Only the elements required for a CTR submission are modelled.
+type goAMLReport struct {
+	XMLName           xml.Name           `xml:"report"`
+	RentityID         string             `xml:"rentity_id"`
+	SubmissionCode    string             `xml:"submission_code"`
+	ReportCode        string             `xml:"report_code"`
+	EntityReference   string             `xml:"entity_reference"`
+	SubmissionDate    string             `xml:"submission_date"`
+	CurrencyCodeLocal string             `xml:"currency_code_local"`
+	Reason            string             `xml:"reason"`
+	Transactions      []goAMLTransaction `xml:"transaction"`
+}
+
+type goAMLTransaction struct {
+	TransactionNumber      string     `xml:"transactionnumber"`
+	InternalRefNumber      string     `xml:"internal_ref_number"`
+	TransactionDescription string     `xml:"transaction_description"`
+	DateTransaction        string     `xml:"date_transaction"`
+	TransmodeCode          string     `xml:"transmode_code"`
+	AmountLocal            string     `xml:"amount_local"`
+	FromMyClient           *goAMLFrom `xml:"t_from_my_client,omitempty"`
+	From                   *goAMLFrom `xml:"t_from,omitempty"`
+	ToMyClient             *goAMLTo   `xml:"t_to_my_client,omitempty"`
+	To                     *goAMLTo   `xml:"t_to,omitempty"`
+}
+
+type goAMLFrom struct {
+	FundsCode string        `xml:"from_funds_code"`
+	Person    *goAMLPerson  `xml:"from_person,omitempty"`
+	Entity    *goAMLEntity  `xml:"from_entity,omitempty"`
+	Account   *goAMLAccount `xml:"from_account,omitempty"`
+	Country   string        `xml:"from_country"`
+}
+
+type goAMLTo struct {
+	FundsCode string        `xml:"to_funds_code"`
+	Person    *goAMLPerson  `xml:"to_person,omitempty"`
+	Entity    *goAMLEntity  `xml:"to_entity,omitempty"`
+	Account   *goAMLAccount `xml:"to_account,omitempty"`
+	Country   string        `xml:"to_country"`
+}
+
+type goAMLPerson struct {
+	FirstName string `xml:"first_name"`
+	LastName  string `xml:"last_name"`
+	Comments  string `xml:"comments,omitempty"`
+}
+
+type goAMLEntity struct {
+	Name     string `xml:"name"`
+	Comments string `xml:"comments,omitempty"`
+}
+
+type goAMLAccount struct {
+	Account string `xml:"account"`
+}
+
+const goAMLDateTime = "2006-01-02T15:04:05"
+
+// renderGoAML renders the breaching customers' transactions as a goAML CTR report
+func renderGoAML(batch *models.CTRBatch, txns map[int][]models.MonitoredTransaction, rentityID string) ([]byte, error) {
+	report := goAMLReport{
+		RentityID:         rentityID,
+		SubmissionCode:    "E",
+		ReportCode:        "CTR",
+		EntityReference:   "CTR-" + batch.ReportDate.Format("20060102"),
+		SubmissionDate:    time.Now().In(reportingLocation).Format(goAMLDateTime),
+		CurrencyCodeLocal: batch.Currency,
+		Reason:            "Daily customer totals at or above the currency transaction reporting threshold",
+	}
+
+	for _, entry := range batch.Entries {
+		for _, txn := range txns[entry.CustomerID] {
+			report.Transactions = append(report.Transactions, goAMLTransactionFor(entry, txn))
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return nil, fmt.Errorf("failed to encode goAML report: %w", err)
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+func goAMLTransactionFor(entry models.CTRBatchEntry, txn models.MonitoredTransaction) goAMLTransaction {
+	fundsCode := strings.ToUpper(txn.Channel)
+	t := goAMLTransaction{
+		TransactionNumber:      txn.Reference,
+		InternalRefNumber:      strconv.Itoa(txn.ID),
+		TransactionDescription: fmt.Sprintf("%s %s", txn.Channel, txn.Direction),
+		DateTransaction:        txn.OccurredAt.In(reportingLocation).Format(goAMLDateTime),
+		TransmodeCode:          fundsCode,
+		AmountLocal:            formatMinorUnits(txn.Amount),
+	}
+
+	clientPerson, clientEntity := goAMLParty(entry.CustomerType, entry.CustomerName, entry.CustomerID)
+	counterpartyPerson := &goAMLPerson{LastName: txn.CounterpartyName}
+	var counterpartyAccount *goAMLAccount
+	if txn.CounterpartyAccount != "" {
+		counterpartyAccount = &goAMLAccount{Account: txn.CounterpartyAccount}
+		counterpartyPerson = nil
+	}
+
+	if txn.Direction == "credit" {
+		t.From = &goAMLFrom{FundsCode: fundsCode, Person: counterpartyPerson, Account: counterpartyAccount, Country: "NG"}
+		t.ToMyClient = &goAMLTo{FundsCode: fundsCode, Person: clientPerson, Entity: clientEntity, Country: "NG"}
+	} else {
+		t.FromMyClient = &goAMLFrom{FundsCode: fundsCode, Person: clientPerson, Entity: clientEntity, Country: "NG"}
+		t.To = &goAMLTo{FundsCode: fundsCode, Person: counterpartyPerson, Account: counterpartyAccount, Country: "NG"}
+	}
+
+	return t
+}
+
+func goAMLParty(customerType, name string, customerID int) (*goAMLPerson, *goAMLEntity) {
+	comments := fmt.Sprintf("customer_id=%d", customerID)
+	if customerType == "corporate" {
+		return nil, &goAMLEntity{Name: name, Comments: comments}
+	}
+	first, last, found := strings.Cut(strings.TrimSpace(name), " ")
+	if !found {
+		first, last = "", first
+	}
+	return &goAMLPerson{FirstName: first, LastName: last, Comments: comments}, nil
+}
+
+// renderCSV renders one row per breaching customer
+func renderCSV(batch *models.CTRBatch) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write([]string{
+		"report_date", "customer_id", "customer_type", "customer_name",
+		"transaction_count", "total_amount", "threshold", "currency",
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range batch.Entries {
+		if err := w.Write([]string{
+			batch.ReportDate.Format("2006-01-02"),
+			strconv.Itoa(entry.CustomerID),
+			entry.CustomerType,
+			entry.CustomerName,
+			strconv.Itoa(entry.TransactionCount),
+			formatMinorUnits(entry.TotalAmount),
+			formatMinorUnits(entry.Threshold),
+			batch.Currency,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// formatMinorUnits renders an amount in kobo as a decimal string in naira
+func formatMinorUnits(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/config"
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/kodra-pay/compliance-service/internal/repositories"
+)
+
+// reportingLocation is the timezone used to determine the reporting day (West Africa Time)
+var reportingLocation = time.FixedZone("WAT", 60*60)
+
+type CTRService struct {
+	repo    *repositories.CTRRepository
+	txnRepo *repositories.TransactionRepository
+	cfg     config.CTRConfig
+}
+
+func NewCTRService(repo *repositories.CTRRepository, txnRepo *repositories.TransactionRepository, cfg config.CTRConfig) *CTRService {
+	return &CTRService{repo: repo, txnRepo: txnRepo, cfg: cfg}
+}
+
+// RecordTransaction stores a transaction in the monitoring store
+func (s *CTRService) RecordTransaction(ctx context.Context, req dto.MonitoredTransactionRequest) (*models.MonitoredTransaction, error) {
+	if req.Reference == "" {
+		return nil, Validation("reference is required")
+	}
+	if req.CustomerID == 0 {
+		return nil, Validation("customer_id is required")
+	}
+	if req.Amount <= 0 {
+		return nil, Validation("amount must be greater than zero")
+	}
+
+	customerType := strings.ToLower(strings.TrimSpace(req.CustomerType))
+	if customerType != "individual" && customerType != "corporate" {
+		return nil, Validation("customer_type must be 'individual' or 'corporate'")
+	}
+	channel := strings.ToLower(strings.TrimSpace(req.Channel))
+	if channel == "" {
+		return nil, Validation("channel is required")
+	}
+	direction := strings.ToLower(strings.TrimSpace(req.Direction))
+	if direction != "credit" && direction != "debit" {
+		return nil, Validation("direction must be 'credit' or 'debit'")
+	}
+
+	occurredAt, err := time.Parse(time.RFC3339, req.OccurredAt)
+	if err != nil {
+		return nil, Validation("occurred_at must be an RFC 3339 timestamp")
+	}
+
+	currency := strings.ToUpper(req.Currency)
+	if currency == "" {
+		currency = s.cfg.Currency
+	}
+
+	txn := &models.MonitoredTransaction{
+		Reference:           req.Reference,
+		CustomerID:          req.CustomerID,
+		CustomerType:        customerType,
+		CustomerName:        req.CustomerName,
+		Channel:             channel,
+		Direction:           direction,
+		Amount:              req.Amount,
+		Currency:            currency,
+		CounterpartyName:    req.CounterpartyName,
+		CounterpartyAccount: req.CounterpartyAccount,
+		OccurredAt:          occurredAt.UTC(),
+	}
+
+	if err := s.txnRepo.Create(ctx, txn); err != nil {
+		return nil, fmt.Errorf("failed to record transaction: %w", err)
+	}
+
+	return txn, nil
+}
+
+// Generate aggregates the transactions of a reporting day per customer and stores a CTR batch
+// for every customer whose total meets the threshold for their customer type. The thresholds are
+// in the reporting currency, so only transactions in that currency are counted.
+func (s *CTRService) Generate(ctx context.Context, reportDate time.Time, generatedBy string) (*models.CTRBatch, error) {
+	day := time.Date(reportDate.Year(), reportDate.Month(), reportDate.Day(), 0, 0, 0, 0, reportingLocation)
+
+	exists, err := s.repo.ExistsForDate(ctx, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing CTR batch: %w", err)
+	}
+	if exists {
+		return nil, Conflict("CTR batch already generated for %s", day.Format("2006-01-02"))
+	}
+
+	from, to := day.UTC(), day.AddDate(0, 0, 1).UTC()
+	totals, err := s.txnRepo.DailyTotalsByCustomer(ctx, from, to, s.cfg.Channels, s.cfg.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate daily totals: %w", err)
+	}
+
+	batch := &models.CTRBatch{
+		ReportDate:          day,
+		Status:              "generated",
+		IndividualThreshold: s.cfg.IndividualThreshold,
+		CorporateThreshold:  s.cfg.CorporateThreshold,
+		Currency:            s.cfg.Currency,
+		GeneratedBy:         generatedBy,
+	}
+
+	txns := make(map[int][]models.MonitoredTransaction)
+	for _, total := range totals {
+		threshold := s.thresholdFor(total.CustomerType)
+		if total.TotalAmount < threshold {
+			continue
+		}
+
+		customerTxns, err := s.txnRepo.ListByCustomer(ctx, total.CustomerID, from, to, s.cfg.Channels, s.cfg.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list transactions for customer %d: %w", total.CustomerID, err)
+		}
+		txns[total.CustomerID] = customerTxns
+
+		batch.Entries = append(batch.Entries, models.CTRBatchEntry{
+			CustomerID:       total.CustomerID,
+			CustomerType:     total.CustomerType,
+			CustomerName:     total.CustomerName,
+			TransactionCount: total.TransactionCount,
+			TotalAmount:      total.TotalAmount,
+			Threshold:        threshold,
+		})
+		batch.RecordCount++
+		batch.TotalAmount += total.TotalAmount
+	}
+
+	xmlPayload, err := renderGoAML(batch, txns, s.cfg.ReportingEntityID)
+	if err != nil {
+		return nil, err
+	}
+	csvPayload, err := renderCSV(batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render CTR csv: %w", err)
+	}
+	batch.GoAMLXML = string(xmlPayload)
+	batch.CSVPayload = string(csvPayload)
+
+	if err := s.repo.Create(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to store CTR batch: %w", err)
+	}
+
+	return batch, nil
+}
+
+// GeneratePreviousDay generates the batch for yesterday unless it already exists.
+// It is safe to run repeatedly and is used by the scheduler.
+func (s *CTRService) GeneratePreviousDay(ctx context.Context) error {
+	yesterday := time.Now().In(reportingLocation).AddDate(0, 0, -1)
+	day := time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), 0, 0, 0, 0, reportingLocation)
+
+	exists, err := s.repo.ExistsForDate(ctx, day)
+	if err != nil {
+		return fmt.Errorf("failed to check existing CTR batch: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	_, err = s.Generate(ctx, day, "scheduler")
+	return err
+}
+
+// Get retrieves a CTR batch by ID
+func (s *CTRService) Get(ctx context.Context, id int) (*models.CTRBatch, error) {
+	batch, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CTR batch: %w", err)
+	}
+	return batch, nil
+}
+
+func (s *CTRService) thresholdFor(customerType string) int64 {
+	if customerType == "corporate" {
+		return s.cfg.CorporateThreshold
+	}
+	return s.cfg.IndividualThreshold
+}
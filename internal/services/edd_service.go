@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/config"
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/kodra-pay/compliance-service/internal/repositories"
+)
+
+// EDDService manages the enhanced due diligence checklist required for high-risk merchants
+type EDDService struct {
+	repo          *repositories.EDDRepository
+	kycRepo       repositories.KYCStore
+	riskRepo      *repositories.RiskRepository
+	screeningRepo *repositories.ScreeningRepository
+	cfg           config.EDDConfig
+}
+
+func NewEDDService(
+	repo *repositories.EDDRepository,
+	kycRepo repositories.KYCStore,
+	riskRepo *repositories.RiskRepository,
+	screeningRepo *repositories.ScreeningRepository,
+	cfg config.EDDConfig,
+) *EDDService {
+	return &EDDService{repo: repo, kycRepo: kycRepo, riskRepo: riskRepo, screeningRepo: screeningRepo, cfg: cfg}
+}
+
+// Evaluate opens an EDD checklist for the submission when the merchant is high risk.
+// It returns the existing checklist if one is already attached, or nil when EDD is not required.
+func (s *EDDService) Evaluate(ctx context.Context, submission *models.KYCSubmission) (*models.EDDChecklist, error) {
+	existing, err := s.repo.GetBySubmission(ctx, submission.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get edd checklist: %w", err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	reasons, err := s.reasons(ctx, submission)
+	if err != nil {
+		return nil, err
+	}
+	if len(reasons) == 0 {
+		return nil, nil
+	}
+
+	checklist := &models.EDDChecklist{
+		SubmissionID: submission.ID,
+		MerchantID:   submission.MerchantID,
+		Reasons:      reasons,
+		Status:       models.EDDStatusOpen,
+	}
+	for _, itemType := range s.itemsFor(reasons) {
+		checklist.Items = append(checklist.Items, models.EDDItem{
+			ItemType: itemType,
+			Status:   models.EDDItemStatusPending,
+		})
+	}
+
+	if err := s.repo.Create(ctx, checklist); err != nil {
+		return nil, fmt.Errorf("failed to create edd checklist: %w", err)
+	}
+
+	return checklist, nil
+}
+
+// Get retrieves the EDD checklist of a submission
+func (s *EDDService) Get(ctx context.Context, submissionID int) (*models.EDDChecklist, error) {
+	checklist, err := s.repo.GetBySubmission(ctx, submissionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get edd checklist: %w", err)
+	}
+	return checklist, nil
+}
+
+// CompleteItem records the evidence for a checklist item
+func (s *EDDService) CompleteItem(ctx context.Context, submissionID int, itemType string, req dto.EDDItemCompleteRequest) (*models.EDDChecklist, error) {
+	if req.CompletedBy == 0 {
+		return nil, Validation("completed_by is required")
+	}
+	if req.Evidence == "" {
+		return nil, Validation("evidence is required")
+	}
+
+	checklist, err := s.requireChecklist(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	if checklist.Status == models.EDDStatusSignedOff {
+		return nil, Conflict("edd checklist has already been signed off")
+	}
+
+	var item *models.EDDItem
+	for i := range checklist.Items {
+		if checklist.Items[i].ItemType == itemType {
+			item = &checklist.Items[i]
+		}
+	}
+	if item == nil {
+		return nil, NotFound("edd item %q not found", itemType)
+	}
+
+	now := time.Now()
+	item.Status = models.EDDItemStatusCompleted
+	item.Evidence = &req.Evidence
+	item.CompletedBy = &req.CompletedBy
+	item.CompletedAt = &now
+	if req.Notes != "" {
+		item.Notes = &req.Notes
+	}
+	if err := s.repo.UpdateItem(ctx, item); err != nil {
+		return nil, repositoryError(err, "failed to update edd item")
+	}
+
+	if checklist.Complete() && checklist.Status == models.EDDStatusOpen {
+		checklist.Status = models.EDDStatusAwaitingSignOff
+		if err := s.repo.UpdateChecklist(ctx, checklist); err != nil {
+			return nil, repositoryError(err, "failed to update edd checklist")
+		}
+	}
+
+	return checklist, nil
+}
+
+// SignOff records senior management approval of a completed checklist.
+// The approver must hold an elevated role and must not have completed any of the items.
+func (s *EDDService) SignOff(ctx context.Context, submissionID int, req dto.EDDSignOffRequest) (*models.EDDChecklist, error) {
+	if req.ApproverID == 0 {
+		return nil, Validation("approver_id is required")
+	}
+	if !s.isApproverRole(req.ApproverRole) {
+		return nil, Forbidden("role %q cannot sign off enhanced due diligence", req.ApproverRole)
+	}
+
+	checklist, err := s.requireChecklist(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	if checklist.Status == models.EDDStatusSignedOff {
+		return nil, Conflict("edd checklist has already been signed off")
+	}
+	if !checklist.Complete() {
+		return nil, Conflict("all edd items must be completed before sign-off")
+	}
+	for _, item := range checklist.Items {
+		if item.CompletedBy != nil && *item.CompletedBy == req.ApproverID {
+			return nil, Forbidden("edd sign-off requires a second approver who did not complete the checklist")
+		}
+	}
+
+	now := time.Now()
+	checklist.Status = models.EDDStatusSignedOff
+	checklist.SignedOffBy = &req.ApproverID
+	checklist.SignedOffRole = &req.ApproverRole
+	checklist.SignedOffAt = &now
+	if req.Notes != "" {
+		checklist.SignOffNotes = &req.Notes
+	}
+	if err := s.repo.UpdateChecklist(ctx, checklist); err != nil {
+		return nil, repositoryError(err, "failed to sign off edd checklist")
+	}
+
+	return checklist, nil
+}
+
+// CheckApproval returns an error when the submission cannot be approved yet because of
+// outstanding enhanced due diligence
+func (s *EDDService) CheckApproval(ctx context.Context, submission *models.KYCSubmission, reviewerID int) error {
+	checklist, err := s.Evaluate(ctx, submission)
+	if err != nil {
+		return err
+	}
+	if checklist == nil {
+		return nil
+	}
+
+	if !checklist.Complete() {
+		return Conflict("enhanced due diligence is incomplete for this submission")
+	}
+	if checklist.Status != models.EDDStatusSignedOff {
+		return Conflict("enhanced due diligence requires senior management sign-off before approval")
+	}
+	if checklist.SignedOffBy != nil && *checklist.SignedOffBy == reviewerID {
+		return Forbidden("the EDD sign-off approver cannot also approve the submission")
+	}
+
+	return nil
+}
+
+func (s *EDDService) requireChecklist(ctx context.Context, submissionID int) (*models.EDDChecklist, error) {
+	checklist, err := s.Get(ctx, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	if checklist == nil {
+		return nil, NotFound("no edd checklist found for submission")
+	}
+	return checklist, nil
+}
+
+func (s *EDDService) reasons(ctx context.Context, submission *models.KYCSubmission) ([]string, error) {
+	var reasons []string
+
+	category := normalizeRiskKey(submission.BusinessCategory)
+	if category != "" && containsKey(s.cfg.Categories, category) {
+		reasons = append(reasons, "high_risk_category:"+category)
+	}
+
+	score, err := s.riskRepo.GetLatestByMerchant(ctx, submission.MerchantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk score: %w", err)
+	}
+	if score != nil && score.Tier == models.RiskTierHigh {
+		reasons = append(reasons, "high_risk_tier")
+	}
+
+	screening, err := s.screeningRepo.ListByMerchant(ctx, submission.MerchantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get screening results: %w", err)
+	}
+	// A PEP match later resolved as a false positive or clear no longer counts
+	if hasScreeningMatch(latestScreeningResults(screening), "pep", "confirmed_match") {
+		reasons = append(reasons, "pep_match")
+	}
+
+	return reasons, nil
+}
+
+func (s *EDDService) itemsFor(reasons []string) []string {
+	items := append([]string{}, s.cfg.Items...)
+	for _, reason := range reasons {
+		if reason == "pep_match" && !containsKey(items, "pep_declaration") {
+			items = append(items, "pep_declaration")
+		}
+	}
+	return items
+}
+
+func (s *EDDService) isApproverRole(role string) bool {
+	return role != "" && containsKey(s.cfg.ApproverRoles, normalizeRiskKey(role))
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kodra-pay/compliance-service/internal/repositories"
+)
+
+// ErrorKind classifies a domain error. Handlers map kinds to HTTP status codes; errors without a
+// kind are internal failures.
+type ErrorKind string
+
+const (
+	KindNotFound   ErrorKind = "not_found"
+	KindConflict   ErrorKind = "conflict"   // the request clashes with the current state
+	KindValidation ErrorKind = "validation" // the request itself is invalid
+	KindForbidden  ErrorKind = "forbidden"  // the caller may not perform the operation
+	KindUpstream   ErrorKind = "upstream"   // a dependency outside the service failed
+)
+
+// Error is a domain error with a message that is safe to show to the caller
+type Error struct {
+	Kind    ErrorKind
+	Message string
+	Err     error // underlying cause, never shown to the caller
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NotFound reports a missing resource
+func NotFound(format string, args ...interface{}) error {
+	return &Error{Kind: KindNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+// Conflict reports a request that is not allowed in the resource's current state
+func Conflict(format string, args ...interface{}) error {
+	return &Error{Kind: KindConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+// Validation reports an invalid request
+func Validation(format string, args ...interface{}) error {
+	return &Error{Kind: KindValidation, Message: fmt.Sprintf(format, args...)}
+}
+
+// Forbidden reports an operation the caller is not allowed to perform
+func Forbidden(format string, args ...interface{}) error {
+	return &Error{Kind: KindForbidden, Message: fmt.Sprintf(format, args...)}
+}
+
+// Upstream reports a failed call to another service
+func Upstream(err error, format string, args ...interface{}) error {
+	return &Error{Kind: KindUpstream, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// KindOf returns the kind of a domain error anywhere in err's chain, or "" for internal errors
+func KindOf(err error) ErrorKind {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Kind
+	}
+	return ""
+}
+
+// repositoryError reports a failed repository call. A missing record becomes NotFound and a record
+// changed by a concurrent request Conflict, anything else an internal error described by msg.
+func repositoryError(err error, msg string) error {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		return NotFound("%s", err)
+	case errors.Is(err, repositories.ErrConflict):
+		return Conflict("%s", err)
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// GraphML document structures, see http://graphml.graphdrawing.org/specification.html
+type graphMLDocument struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// GraphML renders an entity graph as GraphML for import into analyst tools. Node and edge
+// properties become GraphML attributes prefixed with "node_" and "edge_".
+func (s *GraphService) GraphML(graph *models.EntityGraph) ([]byte, error) {
+	doc := graphMLDocument{
+		Xmlns: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "type", For: "node", AttrName: "type", AttrType: "string"},
+			{ID: "key", For: "node", AttrName: "key", AttrType: "string"},
+			{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+			{ID: "depth", For: "node", AttrName: "depth", AttrType: "int"},
+			{ID: "edge_type", For: "edge", AttrName: "type", AttrType: "string"},
+			{ID: "source", For: "edge", AttrName: "source", AttrType: "string"},
+		},
+		Graph: graphMLGraph{ID: "entity-graph", EdgeDefault: "directed"},
+	}
+
+	nodeProps := make(map[string]bool)
+	for _, node := range graph.Nodes {
+		data := []graphMLData{
+			{Key: "type", Value: node.Type},
+			{Key: "key", Value: node.Key},
+			{Key: "label", Value: node.Label},
+			{Key: "depth", Value: strconv.Itoa(node.Depth)},
+		}
+		data = appendPropertyData(data, "node_", node.Properties, nodeProps)
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{ID: graphMLNodeID(node.ID), Data: data})
+	}
+
+	edgeProps := make(map[string]bool)
+	for _, edge := range graph.Edges {
+		data := []graphMLData{
+			{Key: "edge_type", Value: edge.Type},
+			{Key: "source", Value: edge.Source},
+		}
+		data = appendPropertyData(data, "edge_", edge.Properties, edgeProps)
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     fmt.Sprintf("e%d", edge.ID),
+			Source: graphMLNodeID(edge.FromNodeID),
+			Target: graphMLNodeID(edge.ToNodeID),
+			Data:   data,
+		})
+	}
+
+	for _, name := range sortedKeys(nodeProps) {
+		doc.Keys = append(doc.Keys, graphMLKey{ID: "node_" + name, For: "node", AttrName: name, AttrType: "string"})
+	}
+	for _, name := range sortedKeys(edgeProps) {
+		doc.Keys = append(doc.Keys, graphMLKey{ID: "edge_" + name, For: "edge", AttrName: name, AttrType: "string"})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode GraphML: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func graphMLNodeID(id int) string {
+	return fmt.Sprintf("n%d", id)
+}
+
+// appendPropertyData adds the properties in a stable order and records their names
+func appendPropertyData(data []graphMLData, prefix string, props map[string]string, seen map[string]bool) []graphMLData {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if props[name] == "" {
+			continue
+		}
+		seen[name] = true
+		data = append(data, graphMLData{Key: prefix + name, Value: props[name]})
+	}
+	return data
+}
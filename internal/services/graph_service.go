@@ -0,0 +1,427 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kodra-pay/compliance-service/internal/address"
+	"github.com/kodra-pay/compliance-service/internal/config"
+	"github.com/kodra-pay/compliance-service/internal/encryption"
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/kodra-pay/compliance-service/internal/pii"
+	"github.com/kodra-pay/compliance-service/internal/repositories"
+)
+
+// Graph sync sources
+const (
+	graphSourceSubmissions    = "kyc_submissions"
+	graphSourceAlerts         = "transaction_monitoring_alerts"
+	graphSourceCounterparties = "monitored_transactions"
+)
+
+// GraphService maintains the entity graph used in investigations and answers neighbourhood
+// queries. The graph is derived incrementally from KYC submissions, transaction monitoring alerts
+// and monitored transactions. BVN, phone and email nodes are keyed by blind index and labelled
+// with masked values.
+type GraphService struct {
+	repo    *repositories.GraphRepository
+	kycRepo repositories.KYCStore
+	enc     *encryption.FieldEncryptor
+	cfg     config.GraphConfig
+}
+
+func NewGraphService(repo *repositories.GraphRepository, kycRepo repositories.KYCStore, enc *encryption.FieldEncryptor, cfg config.GraphConfig) *GraphService {
+	return &GraphService{repo: repo, kycRepo: kycRepo, enc: enc, cfg: cfg}
+}
+
+// GetNeighbourhood returns the entities within depth hops of the entity identified by type and id.
+// BVN, phone and email entities are looked up by their plaintext value; addresses by the address
+// text or the node key. It returns nil if the entity is not in the graph.
+func (s *GraphService) GetNeighbourhood(ctx context.Context, nodeType, id string, depth int) (*models.EntityGraph, error) {
+	key, err := s.nodeKey(ctx, nodeType, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if depth <= 0 {
+		depth = s.cfg.DefaultDepth
+	}
+	if depth > s.cfg.MaxDepth {
+		return nil, Validation("depth must not exceed %d", s.cfg.MaxDepth)
+	}
+
+	root, err := s.repo.GetNode(ctx, nodeType, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get graph node: %w", err)
+	}
+	if root == nil {
+		return nil, nil
+	}
+
+	nodes, edges, err := s.repo.Neighbourhood(ctx, root.ID, depth, s.cfg.MaxNodes+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk entity graph: %w", err)
+	}
+
+	graph := &models.EntityGraph{Root: *root, Depth: depth, Nodes: nodes, Edges: edges}
+	if len(nodes) > s.cfg.MaxNodes {
+		graph.Truncated = true
+		graph.Nodes = nodes[:s.cfg.MaxNodes]
+		graph.Edges = edgesWithin(edges, graph.Nodes)
+	}
+	if graph.Nodes == nil {
+		graph.Nodes = []models.GraphNode{}
+	}
+	if graph.Edges == nil {
+		graph.Edges = []models.GraphEdge{}
+	}
+
+	return graph, nil
+}
+
+func (s *GraphService) nodeKey(ctx context.Context, nodeType, id string) (string, error) {
+	if strings.TrimSpace(id) == "" {
+		return "", Validation("entity id is required")
+	}
+
+	switch nodeType {
+	case models.NodeTypeMerchant, models.NodeTypePerson, models.NodeTypeBankAccount, models.NodeTypeTransaction:
+		return strings.TrimSpace(id), nil
+	case models.NodeTypeBVN:
+		return s.enc.BlindIndex(ctx, encryption.IndexBVN, id)
+	case models.NodeTypePhone:
+		return s.enc.BlindIndex(ctx, encryption.IndexPhone, id)
+	case models.NodeTypeEmail:
+		return s.enc.BlindIndex(ctx, encryption.IndexEmail, id)
+	case models.NodeTypeAddress:
+		return address.Key(id, ""), nil
+	default:
+		return "", Validation("unknown entity type %q", nodeType)
+	}
+}
+
+func edgesWithin(edges []models.GraphEdge, nodes []models.GraphNode) []models.GraphEdge {
+	kept := make(map[int]bool, len(nodes))
+	for _, node := range nodes {
+		kept[node.ID] = true
+	}
+
+	var within []models.GraphEdge
+	for _, edge := range edges {
+		if kept[edge.FromNodeID] && kept[edge.ToNodeID] {
+			within = append(within, edge)
+		}
+	}
+	return within
+}
+
+// Sync brings the graph up to date with the source tables. It is run periodically by the
+// scheduler and resumes from the last synced row of each source.
+func (s *GraphService) Sync(ctx context.Context) error {
+	if err := s.syncSubmissions(ctx); err != nil {
+		return err
+	}
+	if err := s.syncAlerts(ctx); err != nil {
+		return err
+	}
+	if err := s.syncCounterparties(ctx); err != nil {
+		return err
+	}
+
+	removed, err := s.repo.DeleteOrphanNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to delete orphan graph nodes: %w", err)
+	}
+	if removed > 0 {
+		logging.FromContext(ctx).Info("graph sync removed orphan nodes", "count", removed)
+	}
+
+	return nil
+}
+
+func (s *GraphService) syncSubmissions(ctx context.Context) error {
+	cursor, err := s.repo.GetCursor(ctx, graphSourceSubmissions)
+	if err != nil {
+		return fmt.Errorf("failed to get graph cursor: %w", err)
+	}
+
+	for ctx.Err() == nil {
+		ids, updatedAt, err := s.repo.ListChangedSubmissions(ctx, cursor, s.cfg.SyncBatch)
+		if err != nil {
+			return fmt.Errorf("failed to list changed kyc submissions: %w", err)
+		}
+
+		for i, id := range ids {
+			if err := s.syncSubmission(ctx, id); err != nil {
+				return err
+			}
+			cursor = repositories.GraphCursor{Until: updatedAt[i], LastID: id}
+		}
+		if len(ids) > 0 {
+			if err := s.repo.SaveCursor(ctx, graphSourceSubmissions, cursor); err != nil {
+				return fmt.Errorf("failed to save graph cursor: %w", err)
+			}
+		}
+		if len(ids) < s.cfg.SyncBatch {
+			return nil
+		}
+	}
+
+	return ctx.Err()
+}
+
+// syncSubmission rebuilds the edges derived from a submission: the merchant, its address and its
+// directors, shareholders and beneficial owners with their BVNs, phones and emails
+func (s *GraphService) syncSubmission(ctx context.Context, submissionID int) error {
+	submission, err := s.kycRepo.GetByID(ctx, submissionID)
+	if err != nil {
+		return fmt.Errorf("failed to get kyc submission %d: %w", submissionID, err)
+	}
+	if submission == nil {
+		return nil
+	}
+
+	persons, err := s.kycRepo.ListPersons(ctx, submissionID)
+	if err != nil {
+		return fmt.Errorf("failed to list kyc persons of submission %d: %w", submissionID, err)
+	}
+
+	source := fmt.Sprintf("kyc_submission:%d", submissionID)
+	if err := s.repo.DeleteEdgesBySource(ctx, source); err != nil {
+		return fmt.Errorf("failed to clear graph edges of submission %d: %w", submissionID, err)
+	}
+
+	merchant := &models.GraphNode{
+		Type:  models.NodeTypeMerchant,
+		Key:   strconv.Itoa(submission.MerchantID),
+		Label: submission.BusinessName,
+		Properties: map[string]string{
+			"business_type":     submission.BusinessType,
+			"business_category": submission.BusinessCategory,
+			"state":             submission.State,
+			"kyc_status":        submission.Status,
+		},
+	}
+	if err := s.repo.UpsertNode(ctx, merchant); err != nil {
+		return fmt.Errorf("failed to upsert merchant node: %w", err)
+	}
+
+	if node := addressNode(submission); node != nil {
+		if err := s.link(ctx, merchant, node, models.EdgeTypeLocatedAt, source, nil); err != nil {
+			return err
+		}
+	}
+
+	// Submissions that predate declared persons only carry the legacy director fields
+	if len(persons) == 0 && submission.DirectorName != "" {
+		persons = []models.KYCPerson{{
+			Role:     models.PersonRoleDirector,
+			FullName: submission.DirectorName,
+			BVN:      submission.DirectorBVN,
+			Phone:    submission.DirectorPhone,
+			Email:    submission.DirectorEmail,
+		}}
+	}
+
+	for _, person := range persons {
+		key := strconv.Itoa(person.ID)
+		if person.ID == 0 {
+			key = fmt.Sprintf("submission-%d-director", submissionID)
+		}
+
+		node := &models.GraphNode{
+			Type:  models.NodeTypePerson,
+			Key:   key,
+			Label: person.FullName,
+			Properties: map[string]string{
+				"identity_status":  person.IdentityStatus,
+				"screening_status": person.ScreeningStatus,
+				"nationality":      person.Nationality,
+			},
+		}
+		if err := s.link(ctx, merchant, node, person.Role, source, nil); err != nil {
+			return err
+		}
+
+		identifiers := []struct {
+			nodeType string
+			kind     encryption.IndexKind
+			edgeType string
+			value    string
+			mask     func(string) string
+		}{
+			{models.NodeTypeBVN, encryption.IndexBVN, models.EdgeTypeHasBVN, person.BVN, pii.MaskBVN},
+			{models.NodeTypePhone, encryption.IndexPhone, models.EdgeTypeHasPhone, person.Phone, pii.MaskPhone},
+			{models.NodeTypeEmail, encryption.IndexEmail, models.EdgeTypeHasEmail, person.Email, pii.MaskEmail},
+		}
+		for _, id := range identifiers {
+			index, err := s.enc.BlindIndex(ctx, id.kind, id.value)
+			if err != nil {
+				return fmt.Errorf("failed to index %s: %w", id.nodeType, err)
+			}
+			if index == "" {
+				continue
+			}
+			target := &models.GraphNode{Type: id.nodeType, Key: index, Label: id.mask(id.value)}
+			if err := s.link(ctx, node, target, id.edgeType, source, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// addressNode is the address node of a submission, keyed by its normalised address key so that
+// merchants at the same address share a node. It returns nil for submissions without an address.
+func addressNode(submission *models.KYCSubmission) *models.GraphNode {
+	normalized := submission.Address
+	if normalized == nil {
+		normalized = &models.NormalizedAddress{
+			Line:  submission.BusinessAddress,
+			City:  submission.City,
+			State: submission.State,
+			Key:   address.Key(submission.BusinessAddress, submission.City),
+		}
+	}
+	if normalized.Key == "" {
+		return nil
+	}
+
+	node := &models.GraphNode{
+		Type:  models.NodeTypeAddress,
+		Key:   normalized.Key,
+		Label: strings.Trim(normalized.Line+", "+normalized.City, ", "),
+		Properties: map[string]string{
+			"city":  normalized.City,
+			"state": normalized.State,
+			"flags": strings.Join(normalized.Flags, ","),
+		},
+	}
+	if normalized.Latitude != nil && normalized.Longitude != nil {
+		node.Properties["latitude"] = strconv.FormatFloat(*normalized.Latitude, 'f', 6, 64)
+		node.Properties["longitude"] = strconv.FormatFloat(*normalized.Longitude, 'f', 6, 64)
+	}
+	return node
+}
+
+func (s *GraphService) syncAlerts(ctx context.Context) error {
+	cursor, err := s.repo.GetCursor(ctx, graphSourceAlerts)
+	if err != nil {
+		return fmt.Errorf("failed to get graph cursor: %w", err)
+	}
+
+	for ctx.Err() == nil {
+		alerts, err := s.repo.ListChangedAlerts(ctx, cursor, s.cfg.SyncBatch)
+		if err != nil {
+			return fmt.Errorf("failed to list changed alerts: %w", err)
+		}
+
+		for _, alert := range alerts {
+			source := fmt.Sprintf("alert:%d", alert.ID)
+			if err := s.repo.DeleteEdgesBySource(ctx, source); err != nil {
+				return fmt.Errorf("failed to clear graph edges of alert %d: %w", alert.ID, err)
+			}
+
+			merchant := merchantReference(alert.UserID)
+			if err := s.repo.EnsureNode(ctx, merchant); err != nil {
+				return fmt.Errorf("failed to ensure merchant node: %w", err)
+			}
+
+			transaction := &models.GraphNode{
+				Type:  models.NodeTypeTransaction,
+				Key:   strconv.Itoa(alert.TransactionID),
+				Label: fmt.Sprintf("Transaction %d", alert.TransactionID),
+			}
+			props := map[string]string{
+				"alert_id": strconv.Itoa(alert.ID),
+				"rule":     alert.RuleTriggered,
+				"severity": alert.Severity,
+				"status":   alert.Status,
+			}
+			if err := s.link(ctx, merchant, transaction, models.EdgeTypeAlerted, source, props); err != nil {
+				return err
+			}
+
+			cursor = repositories.GraphCursor{Until: alert.UpdatedAt, LastID: alert.ID}
+		}
+		if len(alerts) > 0 {
+			if err := s.repo.SaveCursor(ctx, graphSourceAlerts, cursor); err != nil {
+				return fmt.Errorf("failed to save graph cursor: %w", err)
+			}
+		}
+		if len(alerts) < s.cfg.SyncBatch {
+			return nil
+		}
+	}
+
+	return ctx.Err()
+}
+
+func (s *GraphService) syncCounterparties(ctx context.Context) error {
+	cursor, err := s.repo.GetCursor(ctx, graphSourceCounterparties)
+	if err != nil {
+		return fmt.Errorf("failed to get graph cursor: %w", err)
+	}
+
+	for ctx.Err() == nil {
+		counterparties, err := s.repo.ListNewCounterparties(ctx, cursor, s.cfg.SyncBatch)
+		if err != nil {
+			return fmt.Errorf("failed to list monitored transactions: %w", err)
+		}
+
+		for _, cp := range counterparties {
+			merchant := merchantReference(cp.CustomerID)
+			if err := s.repo.EnsureNode(ctx, merchant); err != nil {
+				return fmt.Errorf("failed to ensure merchant node: %w", err)
+			}
+
+			label := pii.MaskAccountNumber(cp.CounterpartyAccount)
+			if cp.CounterpartyName != "" {
+				label = cp.CounterpartyName + " (" + label + ")"
+			}
+			account := &models.GraphNode{Type: models.NodeTypeBankAccount, Key: cp.CounterpartyAccount, Label: label}
+			if err := s.link(ctx, merchant, account, models.EdgeTypeCounterparty, graphSourceCounterparties, nil); err != nil {
+				return err
+			}
+
+			cursor = repositories.GraphCursor{Until: cp.CreatedAt, LastID: cp.ID}
+		}
+		if len(counterparties) > 0 {
+			if err := s.repo.SaveCursor(ctx, graphSourceCounterparties, cursor); err != nil {
+				return fmt.Errorf("failed to save graph cursor: %w", err)
+			}
+		}
+		if len(counterparties) < s.cfg.SyncBatch {
+			return nil
+		}
+	}
+
+	return ctx.Err()
+}
+
+// link upserts the target node and an edge to it from an already stored node
+func (s *GraphService) link(ctx context.Context, from, to *models.GraphNode, edgeType, source string, props map[string]string) error {
+	if err := s.repo.UpsertNode(ctx, to); err != nil {
+		return fmt.Errorf("failed to upsert %s node: %w", to.Type, err)
+	}
+
+	edge := &models.GraphEdge{FromNodeID: from.ID, ToNodeID: to.ID, Type: edgeType, Source: source, Properties: props}
+	if err := s.repo.UpsertEdge(ctx, edge); err != nil {
+		return fmt.Errorf("failed to upsert %s edge: %w", edgeType, err)
+	}
+	return nil
+}
+
+// merchantReference is a merchant node referenced from transaction data. It only creates the node
+// when the merchant has no KYC submission yet, so the submission's label is kept.
+func merchantReference(merchantID int) *models.GraphNode {
+	return &models.GraphNode{
+		Type:  models.NodeTypeMerchant,
+		Key:   strconv.Itoa(merchantID),
+		Label: fmt.Sprintf("Merchant %d", merchantID),
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/kodra-pay/compliance-service/internal/repositories"
+)
+
+// KeyRotationService moves encrypted KYC fields onto the current key version after a rotation,
+// and encrypts rows written before field encryption was enabled
+type KeyRotationService struct {
+	kycRepo   repositories.KYCStore
+	batchSize int
+}
+
+func NewKeyRotationService(kycRepo repositories.KYCStore, batchSize int) *KeyRotationService {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &KeyRotationService{kycRepo: kycRepo, batchSize: batchSize}
+}
+
+// Reencrypt processes batches until no rows remain on an old key version or ctx is cancelled
+func (s *KeyRotationService) Reencrypt(ctx context.Context) error {
+	var submissions, persons int
+
+	for ctx.Err() == nil {
+		n, err := s.kycRepo.ReencryptSubmissions(ctx, s.batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to re-encrypt kyc submissions: %w", err)
+		}
+		submissions += n
+		if n < s.batchSize {
+			break
+		}
+	}
+
+	for ctx.Err() == nil {
+		n, err := s.kycRepo.ReencryptPersons(ctx, s.batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to re-encrypt kyc persons: %w", err)
+		}
+		persons += n
+		if n < s.batchSize {
+			break
+		}
+	}
+
+	if submissions > 0 || persons > 0 {
+		logging.FromContext(ctx).Info("re-encrypted kyc records", "submissions", submissions, "persons", persons)
+	}
+
+	return ctx.Err()
+}
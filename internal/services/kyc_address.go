@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kodra-pay/compliance-service/internal/address"
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// addressFlagReasons describes the address flags in review reasons
+var addressFlagReasons = map[string]string{
+	models.AddressFlagPOBox:             "business address is a PO box",
+	models.AddressFlagVirtualOffice:     "business address appears to be a virtual office",
+	models.AddressFlagCityStateMismatch: "city is not in the declared state",
+}
+
+// normalizeAddress validates the submitted state and standardises the business address. A geocoding
+// failure is logged when the gazetteer located the address instead, and reported as an upstream
+// error when the address could not be located at all.
+func (s *KYCService) normalizeAddress(ctx context.Context, req *dto.KYCSubmissionRequest) (*models.NormalizedAddress, error) {
+	normalized, err := s.addresses.Normalize(address.Address{
+		Line:       req.BusinessAddress,
+		City:       req.City,
+		State:      req.State,
+		PostalCode: req.PostalCode,
+	})
+	if err != nil {
+		return nil, Validation("%v", err)
+	}
+
+	if err := s.addresses.Geocode(ctx, normalized); err != nil {
+		if normalized.Latitude == nil {
+			return nil, Upstream(err, "the business address could not be geocoded, try again later")
+		}
+		logging.FromContext(ctx).Warn("failed to geocode business address", "error", err)
+	}
+
+	return normalized, nil
+}
+
+// flagAddress flags a submission for manual review when its business address raised flags during
+// normalisation. Like related-merchant links, the outcome is not shown to the merchant.
+func (s *KYCService) flagAddress(ctx context.Context, submission *models.KYCSubmission) error {
+	if submission.Address == nil || len(submission.Address.Flags) == 0 {
+		return nil
+	}
+
+	reasons := make([]string, 0, len(submission.Address.Flags))
+	for _, flag := range submission.Address.Flags {
+		reasons = append(reasons, addressFlagReasons[flag])
+	}
+
+	if err := s.repo.FlagForReview(ctx, submission.ID, strings.Join(reasons, "; ")); err != nil {
+		return fmt.Errorf("failed to flag submission for review: %w", err)
+	}
+	return nil
+}
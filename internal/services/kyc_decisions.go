@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// proposeDecision records the maker's proposed status without changing the submission
+func (s *KYCService) proposeDecision(ctx context.Context, submission *models.KYCSubmission, status string, req dto.KYCStatusUpdateRequest) (*models.KYCDecision, error) {
+	open, err := s.repo.GetOpenDecision(ctx, submission.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check open KYC decisions: %w", err)
+	}
+	if open != nil {
+		return nil, Conflict("a KYC decision is already awaiting confirmation for this submission")
+	}
+
+	decision := &models.KYCDecision{
+		SubmissionID:   submission.ID,
+		MerchantID:     submission.MerchantID,
+		ProposedStatus: status,
+		ProposedBy:     req.ReviewerID,
+		Status:         models.DecisionStatusProposed,
+	}
+	if req.ReviewNotes != "" {
+		decision.ProposalNotes = &req.ReviewNotes
+	}
+
+	if err := s.repo.CreateDecision(ctx, decision); err != nil {
+		return nil, fmt.Errorf("failed to propose KYC decision: %w", err)
+	}
+
+	return decision, nil
+}
+
+// ConfirmDecision applies the proposed status on behalf of a second reviewer
+func (s *KYCService) ConfirmDecision(ctx context.Context, id int, req dto.KYCDecisionCheckRequest) (*models.KYCDecision, error) {
+	decision, err := s.openDecision(ctx, id, req.CheckerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.resolveDecision(ctx, decision, models.DecisionStatusConfirmed, decision.ProposedStatus, req)
+}
+
+// OverturnDecision replaces the proposed status with the second reviewer's status
+func (s *KYCService) OverturnDecision(ctx context.Context, id int, req dto.KYCDecisionCheckRequest) (*models.KYCDecision, error) {
+	status, err := normalizeKYCStatus(req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	decision, err := s.openDecision(ctx, id, req.CheckerID)
+	if err != nil {
+		return nil, err
+	}
+	if status == decision.ProposedStatus {
+		return nil, Validation("overturning requires a status different from the proposal, confirm it instead")
+	}
+
+	return s.resolveDecision(ctx, decision, models.DecisionStatusOverturned, status, req)
+}
+
+// ListPendingDecisions lists proposals awaiting a second reviewer
+func (s *KYCService) ListPendingDecisions(ctx context.Context, limit int) ([]models.KYCDecision, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	decisions, err := s.repo.ListDecisionsByStatus(ctx, models.DecisionStatusProposed, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending KYC decisions: %w", err)
+	}
+	if decisions == nil {
+		decisions = []models.KYCDecision{}
+	}
+	return decisions, nil
+}
+
+func (s *KYCService) openDecision(ctx context.Context, id int, checkerID int) (*models.KYCDecision, error) {
+	decision, err := s.repo.GetDecision(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get KYC decision: %w", err)
+	}
+	if decision == nil {
+		return nil, NotFound("kyc decision not found")
+	}
+	if decision.Status != models.DecisionStatusProposed {
+		return nil, Conflict("kyc decision has already been %s", decision.Status)
+	}
+	if decision.ProposedBy == checkerID {
+		return nil, Forbidden("a KYC decision must be checked by a different reviewer than the one who proposed it")
+	}
+	return decision, nil
+}
+
+func (s *KYCService) resolveDecision(ctx context.Context, decision *models.KYCDecision, outcome, finalStatus string, req dto.KYCDecisionCheckRequest) (*models.KYCDecision, error) {
+	submission, err := s.repo.GetByID(ctx, decision.SubmissionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kyc submission: %w", err)
+	}
+	if submission == nil {
+		return nil, NotFound("no KYC submission found for decision")
+	}
+
+	// A proposal made against a submission the merchant has since replaced must not overwrite the
+	// status the merchant's newer submission reports
+	latest, err := s.repo.GetLatestByMerchant(ctx, submission.MerchantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kyc submission: %w", err)
+	}
+	if latest == nil || latest.ID != submission.ID {
+		return nil, Conflict("the merchant has submitted newer KYC details since this decision was proposed")
+	}
+
+	// Preconditions are re-checked because the submission may have changed since the proposal
+	if err := s.checkTransition(ctx, submission, finalStatus, req.CheckerID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	decision.Status = outcome
+	decision.FinalStatus = &finalStatus
+	decision.CheckedBy = &req.CheckerID
+	decision.CheckedAt = &now
+	if req.ReviewNotes != "" {
+		decision.CheckNotes = &req.ReviewNotes
+	}
+	if err := s.repo.ResolveDecision(ctx, decision); err != nil {
+		return nil, repositoryError(err, "failed to resolve KYC decision")
+	}
+
+	notes := req.ReviewNotes
+	if notes == "" && decision.ProposalNotes != nil {
+		notes = *decision.ProposalNotes
+	}
+	if err := s.applyStatus(ctx, submission, finalStatus, req.CheckerID, notes); err != nil {
+		return nil, err
+	}
+
+	return decision, nil
+}
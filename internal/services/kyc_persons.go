@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/kodra-pay/compliance-service/internal/validation"
+)
+
+// buildPersons converts the declared directors, shareholders and beneficial owners into models
+// and enforces the beneficial ownership rules, returning every violation. Requests that only use
+// the legacy single-director fields are mapped to a single director, and the legacy fields are
+// filled from the first director when only the structured list is provided. Field formats are
+// checked by the request's validation tags.
+func (s *KYCService) buildPersons(req *dto.KYCSubmissionRequest) ([]models.KYCPerson, validation.Errors) {
+	if len(req.Directors) == 0 && req.DirectorName != "" {
+		req.Directors = []dto.KYCPersonRequest{{
+			FullName: req.DirectorName,
+			BVN:      req.DirectorBVN,
+			Phone:    req.DirectorPhone,
+			Email:    req.DirectorEmail,
+		}}
+	}
+	if req.DirectorName == "" && len(req.Directors) > 0 {
+		first := req.Directors[0]
+		req.DirectorName = first.FullName
+		req.DirectorBVN = first.BVN
+		req.DirectorPhone = first.Phone
+		req.DirectorEmail = first.Email
+	}
+
+	var persons []models.KYCPerson
+	var errs validation.Errors
+	groups := []struct {
+		field   string
+		role    string
+		entries []dto.KYCPersonRequest
+	}{
+		{"directors", models.PersonRoleDirector, req.Directors},
+		{"shareholders", models.PersonRoleShareholder, req.Shareholders},
+		{"beneficial_owners", models.PersonRoleBeneficialOwner, req.BeneficialOwners},
+	}
+
+	for _, group := range groups {
+		var totalOwnership float64
+		for _, entry := range group.entries {
+			person := buildPerson(group.role, entry)
+			if person.OwnershipPercentage != nil {
+				totalOwnership += *person.OwnershipPercentage
+			}
+			persons = append(persons, *person)
+		}
+		if totalOwnership > 100 {
+			errs.Add(group.field, "ownership_total", "ownership_percentage total must not exceed 100")
+		}
+	}
+
+	errs = append(errs, s.validateBeneficialOwners(req)...)
+
+	return persons, errs
+}
+
+func buildPerson(role string, entry dto.KYCPersonRequest) *models.KYCPerson {
+	person := &models.KYCPerson{
+		Role:                role,
+		FullName:            strings.TrimSpace(entry.FullName),
+		BVN:                 entry.BVN,
+		Phone:               entry.Phone,
+		Email:               entry.Email,
+		Nationality:         entry.Nationality,
+		OwnershipPercentage: entry.OwnershipPercentage,
+		IdentityStatus:      "pending",
+		ScreeningStatus:     "pending",
+	}
+
+	if dob, err := time.Parse(validation.DateLayout, entry.DateOfBirth); err == nil {
+		person.DateOfBirth = &dob
+	}
+
+	return person
+}
+
+// validateBeneficialOwners requires every beneficial owner to be identified and every shareholder
+// at or above the UBO threshold to be declared as a beneficial owner
+func (s *KYCService) validateBeneficialOwners(req *dto.KYCSubmissionRequest) validation.Errors {
+	var errs validation.Errors
+	for i, owner := range req.BeneficialOwners {
+		if owner.OwnershipPercentage == nil {
+			errs.Add(fmt.Sprintf("beneficial_owners[%d].ownership_percentage", i), "required", "is required")
+		}
+		if owner.BVN == "" {
+			errs.Add(fmt.Sprintf("beneficial_owners[%d].bvn", i), "required", "is required to identify the beneficial owner")
+		}
+	}
+
+	for i, holder := range req.Shareholders {
+		if holder.OwnershipPercentage == nil || *holder.OwnershipPercentage < s.cfg.UBOThreshold {
+			continue
+		}
+		if !declaredAsBeneficialOwner(holder, req.BeneficialOwners) {
+			errs.Add(fmt.Sprintf("shareholders[%d]", i), "beneficial_owner",
+				fmt.Sprintf("owns %.2f%% and must be declared as a beneficial owner (threshold %.2f%%)",
+					*holder.OwnershipPercentage, s.cfg.UBOThreshold))
+		}
+	}
+
+	return errs
+}
+
+func declaredAsBeneficialOwner(holder dto.KYCPersonRequest, owners []dto.KYCPersonRequest) bool {
+	for _, owner := range owners {
+		if holder.BVN != "" && owner.BVN == holder.BVN {
+			return true
+		}
+		if strings.EqualFold(strings.TrimSpace(owner.FullName), strings.TrimSpace(holder.FullName)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListPersons returns the declared persons of a submission with their personal data masked. Raw
+// values are only revealed through the audited PIIService.UnmaskSubmission.
+func (s *KYCService) ListPersons(ctx context.Context, submissionID int) ([]models.KYCPerson, error) {
+	persons, err := s.repo.ListPersons(ctx, submissionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kyc persons: %w", err)
+	}
+	if persons == nil {
+		persons = []models.KYCPerson{}
+	}
+	for i := range persons {
+		maskPerson(&persons[i])
+	}
+	return persons, nil
+}
+
+// RecordPersonIdentity records the identity verification outcome of a declared person and returns
+// the person, masked like ListPersons
+func (s *KYCService) RecordPersonIdentity(ctx context.Context, personID int, req dto.KYCPersonIdentityRequest) (*models.KYCPerson, error) {
+	status := strings.ToLower(req.Status)
+	if status != "verified" && status != "failed" {
+		return nil, Validation("status must be 'verified' or 'failed'")
+	}
+
+	var reference *string
+	if req.Reference != "" {
+		reference = &req.Reference
+	}
+	if err := s.repo.UpdatePersonIdentity(ctx, personID, status, reference); err != nil {
+		return nil, repositoryError(err, "failed to record identity verification")
+	}
+
+	person, err := s.repo.GetPerson(ctx, personID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kyc person: %w", err)
+	}
+	if person != nil {
+		maskPerson(person)
+	}
+	return person, nil
+}
+
+// checkPersonsCleared returns an error unless every declared person is verified and screened clear.
+// Screening results are recorded through the RiskService; without one, as with STORAGE=memory,
+// persons cannot be screened and only their identity is checked.
+func (s *KYCService) checkPersonsCleared(ctx context.Context, submissionID int) error {
+	persons, err := s.repo.ListPersons(ctx, submissionID)
+	if err != nil {
+		return fmt.Errorf("failed to list kyc persons: %w", err)
+	}
+
+	for _, person := range persons {
+		if person.IdentityStatus != "verified" {
+			return Conflict("%s %q has not been identity-verified", person.Role, person.FullName)
+		}
+		if s.risk != nil && person.ScreeningStatus != "clear" {
+			return Conflict("%s %q has not been screened clear", person.Role, person.FullName)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// linkRelatedMerchants records the submissions of other merchants that share identifiers with the
+// new submission and flags it for manual review. Shell-company networks typically reuse the same
+// directors, registration numbers and contact details across merchants. The merchant is not told,
+// so the outcome stays out of the submission response.
+func (s *KYCService) linkRelatedMerchants(ctx context.Context, submission *models.KYCSubmission) error {
+	links, err := s.repo.FindRelatedSubmissions(ctx, submission)
+	if err != nil {
+		return fmt.Errorf("failed to find related submissions: %w", err)
+	}
+	if len(links) == 0 {
+		return nil
+	}
+
+	if err := s.repo.CreateRelationships(ctx, links); err != nil {
+		return fmt.Errorf("failed to store merchant relationships: %w", err)
+	}
+
+	merchants := make(map[int]bool)
+	linkTypes := make(map[string]bool)
+	for _, link := range links {
+		merchants[link.RelatedMerchantID] = true
+		linkTypes[link.LinkType] = true
+	}
+
+	reason := fmt.Sprintf("shares %s with %d other merchant(s)", strings.Join(sortedKeys(linkTypes), ", "), len(merchants))
+	if err := s.repo.FlagForReview(ctx, submission.ID, reason); err != nil {
+		return fmt.Errorf("failed to flag submission for review: %w", err)
+	}
+
+	return nil
+}
+
+// GetRelated returns the related merchants of a submission, grouped by related submission.
+// It returns nil if the submission does not exist.
+func (s *KYCService) GetRelated(ctx context.Context, submissionID int) (*dto.RelatedMerchantsResponse, error) {
+	submission, err := s.repo.GetByID(ctx, submissionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kyc submission: %w", err)
+	}
+	if submission == nil {
+		return nil, nil
+	}
+
+	flagged, reason, err := s.repo.GetReviewFlag(ctx, submissionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review flag: %w", err)
+	}
+
+	links, err := s.repo.ListRelationships(ctx, submissionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list merchant relationships: %w", err)
+	}
+
+	response := &dto.RelatedMerchantsResponse{
+		SubmissionID:     submissionID,
+		FlaggedForReview: flagged,
+		FlagReason:       stringPtrToString(reason),
+		Related:          []dto.RelatedMerchant{},
+	}
+
+	bySubmission := make(map[int]int) // related submission ID -> index in response.Related
+	for _, link := range links {
+		i, ok := bySubmission[link.RelatedSubmissionID]
+		if !ok {
+			i = len(response.Related)
+			bySubmission[link.RelatedSubmissionID] = i
+			response.Related = append(response.Related, dto.RelatedMerchant{
+				MerchantID:   link.RelatedMerchantID,
+				SubmissionID: link.RelatedSubmissionID,
+			})
+		}
+		response.Related[i].LinkTypes = append(response.Related[i].LinkTypes, link.LinkType)
+	}
+	response.Total = len(response.Related)
+
+	return response, nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
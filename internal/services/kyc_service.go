@@ -6,41 +6,80 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/kodra-pay/compliance-service/internal/address"
+	"github.com/kodra-pay/compliance-service/internal/cache"
+	"github.com/kodra-pay/compliance-service/internal/config"
 	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/kodra-pay/compliance-service/internal/metrics"
 	"github.com/kodra-pay/compliance-service/internal/models"
 	"github.com/kodra-pay/compliance-service/internal/repositories"
+	"github.com/kodra-pay/compliance-service/internal/validation"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/kodra-pay/compliance-service/internal/services")
+
 type KYCService struct {
-	repo *repositories.KYCRepository
+	repo      repositories.KYCStore
+	risk      *RiskService
+	reviews   *ReviewService
+	edd       *EDDService
+	addresses *address.Normalizer
+	validator *validation.Validator
+	cfg       config.KYCConfig
+
+	statusCache *cache.Cache // optional read-through cache of GetLatest
 }
 
-func NewKYCService(repo *repositories.KYCRepository) *KYCService {
-	return &KYCService{repo: repo}
+func NewKYCService(repo repositories.KYCStore, risk *RiskService, reviews *ReviewService, edd *EDDService, addresses *address.Normalizer, cfg config.KYCConfig) *KYCService {
+	return &KYCService{
+		repo:      repo,
+		risk:      risk,
+		reviews:   reviews,
+		edd:       edd,
+		addresses: addresses,
+		validator: validation.New().WithEnum("business_category", cfg.BusinessCategories),
+		cfg:       cfg,
+	}
+}
+
+// WithStatusCache caches GetLatest responses for cfg.StatusCacheTTL. Submissions and status
+// changes made through the service invalidate the merchant's entry.
+func (s *KYCService) WithStatusCache(statusCache *cache.Cache) *KYCService {
+	s.statusCache = statusCache
+	return s
 }
 
 // Submit processes a KYC submission request
 func (s *KYCService) Submit(ctx context.Context, req dto.KYCSubmissionRequest) (*dto.KYCSubmissionResponse, error) {
-	// Validate required fields
-	if req.MerchantID == 0 { // int check
-		return nil, fmt.Errorf("merchant_id is required")
-	}
-	if req.BusinessName == "" {
-		return nil, fmt.Errorf("business_name is required")
+	// Validate the request, collecting every failing field. Directors, shareholders and beneficial
+	// owners are built at the same time (this also maps the legacy director fields).
+	errs := s.validator.Struct(req)
+	persons, personErrs := s.buildPersons(&req)
+	errs = append(errs, personErrs...)
+	if err := errs.Err(); err != nil {
+		return nil, err
 	}
 
 	// Normalize business type
-
-businessType := strings.ToLower(strings.TrimSpace(req.BusinessType))
+	businessType := strings.ToLower(strings.TrimSpace(req.BusinessType))
 	if businessType == "" {
-	
-businessType = "registered"
+		businessType = "registered"
 	}
-	if businessType != "registered" && businessType != "startup" {
-		return nil, fmt.Errorf("business_type must be 'registered' or 'startup'")
+
+	// Validate the state and standardise the business address
+	normalized, err := s.normalizeAddress(ctx, &req)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create submission model
@@ -52,44 +91,99 @@ businessType = "registered"
 		TINNumber:        req.TINNumber,
 		BusinessAddress:  req.BusinessAddress,
 		City:             req.City,
-		State:            req.State,
+		State:            normalized.State,
 		PostalCode:       req.PostalCode,
-		BusinessCategory: req.BusinessCategory,
+		Address:          normalized,
+		BusinessCategory: validation.EnumKey(req.BusinessCategory),
 		DirectorName:     req.DirectorName,
 		DirectorBVN:      req.DirectorBVN,
 		DirectorPhone:    req.DirectorPhone,
 		DirectorEmail:    req.DirectorEmail,
 		Documents:        req.Documents,
+		Persons:          persons,
 		Status:           "pending",
 	}
 
 	// Parse incorporation date if provided
 	if req.IncorporationDate != "" {
-		if parsed, err := time.Parse("2006-01-02", req.IncorporationDate); err == nil {
-			submission.IncorporationDate = &parsed
+		parsed, err := time.Parse(validation.DateLayout, req.IncorporationDate)
+		if err != nil {
+			return nil, Validation("incorporation_date must be in YYYY-MM-DD format")
 		}
+		submission.IncorporationDate = &parsed
 	}
 
 	// Save to database
 	if err := s.repo.Create(ctx, submission); err != nil {
 		return nil, fmt.Errorf("failed to create KYC submission: %w", err)
 	}
+	metrics.KYCSubmissions.WithLabelValues("pending").Inc()
+	s.invalidateStatus(ctx, req.MerchantID)
 
 	// Update merchant KYC status to pending via merchant service
-	if err := s.updateMerchantKYCStatus(req.MerchantID, "pending"); err != nil { // int
+	if err := s.updateMerchantKYCStatus(ctx, req.MerchantID, "pending"); err != nil { // int
 		// Log error but don't fail the submission
-		fmt.Printf("Warning: failed to update merchant KYC status: %v\n", err)
+		logging.FromContext(ctx).Warn("failed to update merchant KYC status", "error", err)
+	}
+
+	// PO boxes, virtual offices and cities outside the declared state are flagged for manual review
+	if err := s.flagAddress(ctx, submission); err != nil {
+		logging.FromContext(ctx).Warn("failed to flag business address", "error", err)
 	}
 
-	return &dto.KYCSubmissionResponse{
+	// Submissions sharing identifiers with other merchants are flagged for manual review
+	if err := s.linkRelatedMerchants(ctx, submission); err != nil {
+		logging.FromContext(ctx).Warn("failed to link related merchants", "error", err)
+	}
+
+	s.recalculateRisk(ctx, req.MerchantID, "kyc_submitted")
+
+	response := &dto.KYCSubmissionResponse{
 		SubmissionID: submission.ID, // int
 		Status:       "pending",
 		Message:      "KYC submission received and is under review",
-	}, nil
+	}
+
+	// High-risk merchants need enhanced due diligence before they can be approved
+	if s.edd != nil {
+		checklist, err := s.edd.Evaluate(ctx, submission)
+		if err != nil {
+			logging.FromContext(ctx).Warn("failed to evaluate enhanced due diligence", "error", err)
+		} else if checklist != nil {
+			response.EDDRequired = true
+		}
+	}
+
+	return response, nil
 }
 
-// GetLatest retrieves the latest KYC submission for a merchant
+// GetLatest retrieves the latest KYC submission for a merchant, through the status cache if one is
+// set. It returns nil when the merchant has not submitted KYC.
 func (s *KYCService) GetLatest(ctx context.Context, merchantID int) (*dto.KYCStatusResponse, error) { // int
+	if s.statusCache == nil {
+		return s.loadLatest(ctx, merchantID)
+	}
+
+	// The response is cached as JSON; null caches that the merchant has no submission
+	value, err := s.statusCache.Load(ctx, strconv.Itoa(merchantID), s.cfg.StatusCacheTTL, func(ctx context.Context) ([]byte, error) {
+		status, err := s.loadLatest(ctx, merchantID)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(status)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var status *dto.KYCStatusResponse
+	if err := json.Unmarshal(value, &status); err != nil {
+		return nil, fmt.Errorf("failed to decode cached KYC status: %w", err)
+	}
+	return status, nil
+}
+
+func (s *KYCService) loadLatest(ctx context.Context, merchantID int) (*dto.KYCStatusResponse, error) {
 	submission, err := s.repo.GetLatestByMerchant(ctx, merchantID) // int
 	if err != nil {
 		return nil, fmt.Errorf("failed to get KYC status: %w", err)
@@ -108,39 +202,103 @@ func (s *KYCService) GetLatest(ctx context.Context, merchantID int) (*dto.KYCSta
 	}, nil
 }
 
-// UpdateStatus updates the KYC status (admin operation)
-func (s *KYCService) UpdateStatus(ctx context.Context, req dto.KYCStatusUpdateRequest) error {
+// UpdateStatus updates the KYC status (admin operation). In four-eyes mode the change is only
+// proposed and the returned decision must be confirmed by a second reviewer before it applies.
+func (s *KYCService) UpdateStatus(ctx context.Context, req dto.KYCStatusUpdateRequest) (*models.KYCDecision, error) {
+	if err := s.validator.Struct(req).Err(); err != nil {
+		return nil, err
+	}
+
 	// Validate status
-	status := strings.ToLower(req.Status)
-	if status != "approved" && status != "rejected" && status != "pending" {
-		return fmt.Errorf("invalid status: must be 'approved', 'rejected', or 'pending'")
+	status, err := normalizeKYCStatus(req.Status)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get the latest submission for this merchant
 	latest, err := s.repo.GetLatestByMerchant(ctx, req.MerchantID) // int
-	if err != nil || latest == nil {
-		return fmt.Errorf("no KYC submission found for merchant")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kyc submission: %w", err)
+	}
+	if latest == nil {
+		return nil, NotFound("no KYC submission found for merchant")
+	}
+
+	if err := s.checkTransition(ctx, latest, status, req.ReviewerID); err != nil {
+		return nil, err
+	}
+
+	if s.cfg.FourEyes {
+		return s.proposeDecision(ctx, latest, status, req)
 	}
 
+	return nil, s.applyStatus(ctx, latest, status, req.ReviewerID, req.ReviewNotes)
+}
+
+// checkTransition enforces the preconditions for moving a submission to the given status
+func (s *KYCService) checkTransition(ctx context.Context, submission *models.KYCSubmission, status string, reviewerID int) error {
+	if status != "approved" {
+		return nil
+	}
+
+	// Every declared director, shareholder and beneficial owner must be verified and screened
+	if err := s.checkPersonsCleared(ctx, submission.ID); err != nil {
+		return err
+	}
+
+	// Enhanced due diligence must be complete and signed off before approval
+	if s.edd != nil {
+		if err := s.edd.CheckApproval(ctx, submission, reviewerID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyStatus persists a status change and runs its side effects
+func (s *KYCService) applyStatus(ctx context.Context, submission *models.KYCSubmission, status string, reviewer int, reviewNotes string) error {
 	// Update status in database
 	var reviewerID *int // Now *int
-	if req.ReviewerID != 0 {
-		reviewerID = &req.ReviewerID
+	if reviewer != 0 {
+		reviewerID = &reviewer
 	}
-	notes := &req.ReviewNotes
-	if err := s.repo.UpdateStatus(ctx, latest.ID, status, reviewerID, notes); err != nil { // int, *int
-		return fmt.Errorf("failed to update KYC status: %w", err)
+	notes := &reviewNotes
+	if err := s.repo.UpdateStatus(ctx, submission.ID, status, reviewerID, notes); err != nil { // int, *int
+		return repositoryError(err, "failed to update KYC status")
+	}
+	metrics.KYCSubmissions.WithLabelValues(status).Inc()
+	s.invalidateStatus(ctx, submission.MerchantID)
+	if status != "pending" {
+		metrics.KYCReviewTurnaround.WithLabelValues(status).Observe(time.Since(submission.CreatedAt).Seconds())
 	}
 
 	// Sync merchant KYC status
-	if err := s.updateMerchantKYCStatus(req.MerchantID, status); err != nil { // int
+	if err := s.updateMerchantKYCStatus(ctx, submission.MerchantID, status); err != nil { // int
 		// Log error but don't fail the update
-		fmt.Printf("Warning: failed to sync merchant KYC status: %v\n", err)
+		logging.FromContext(ctx).Warn("failed to sync merchant KYC status", "status", status, "error", err)
+	}
+
+	s.recalculateRisk(ctx, submission.MerchantID, "kyc_status_"+status)
+
+	// Approved merchants enter the periodic review cycle for their risk tier
+	if status == "approved" && s.reviews != nil {
+		if _, err := s.reviews.Schedule(ctx, submission.MerchantID, submission.ID, time.Now()); err != nil {
+			logging.FromContext(ctx).Warn("failed to schedule periodic KYC review", "error", err)
+		}
 	}
 
 	return nil
 }
 
+func normalizeKYCStatus(value string) (string, error) {
+	status := strings.ToLower(value)
+	if status != "approved" && status != "rejected" && status != "pending" {
+		return "", Validation("invalid status: must be 'approved', 'rejected', or 'pending'")
+	}
+	return status, nil
+}
+
 // ListByStatus lists KYC submissions by status
 func (s *KYCService) ListByStatus(ctx context.Context, status string, limit int) (*dto.KYCListResponse, error) {
 	if limit <= 0 || limit > 100 {
@@ -170,10 +328,53 @@ func (s *KYCService) ListByStatus(ctx context.Context, status string, limit int)
 	}, nil
 }
 
+// invalidateStatus drops the merchant's cached status after a change. If Redis is unavailable the
+// entry stays until its TTL expires.
+func (s *KYCService) invalidateStatus(ctx context.Context, merchantID int) {
+	if s.statusCache == nil {
+		return
+	}
+	if err := s.statusCache.Delete(ctx, strconv.Itoa(merchantID)); err != nil {
+		logging.FromContext(ctx).Warn("failed to invalidate cached KYC status", "error", err)
+	}
+}
+
+// RecordBacklogMetrics sets the pending review backlog gauges from the store
+func (s *KYCService) RecordBacklogMetrics(ctx context.Context) error {
+	count, oldest, err := s.repo.PendingBacklog(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read pending KYC backlog: %w", err)
+	}
+
+	age := 0.0
+	if oldest != nil {
+		age = time.Since(*oldest).Seconds()
+	}
+	metrics.KYCPendingSubmissions.Set(float64(count))
+	metrics.KYCPendingOldestAge.Set(age)
+	return nil
+}
+
 // Helper function to update merchant KYC status via merchant service
-func (s *KYCService) updateMerchantKYCStatus(merchantID int, status string) error { // int
+func (s *KYCService) updateMerchantKYCStatus(ctx context.Context, merchantID int, status string) (err error) { // int
 	// Call merchant service to update KYC status
-	url := fmt.Sprintf("http://merchant-service:7002/merchants/%d/kyc-status", merchantID) // int, changed %s to %d
+	url := fmt.Sprintf("%s/merchants/%d/kyc-status", strings.TrimRight(s.cfg.MerchantServiceURL, "/"), merchantID)
+
+	// The merchant service continues the trace through the traceparent header
+	ctx, span := tracer.Start(ctx, "PUT merchant-service /merchants/:id/kyc-status",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", http.MethodPut),
+			attribute.String("url.full", url),
+			attribute.Int("merchant.id", merchantID),
+		))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
 
 	payload := map[string]string{
 		"kyc_status": status,
@@ -184,26 +385,40 @@ func (s *KYCService) updateMerchantKYCStatus(merchantID int, status string) erro
 		return err
 	}
 
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 
 	client := &http.Client{Timeout: 5 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
+		metrics.MerchantSyncFailures.WithLabelValues("request").Inc()
 		return err
 	}
 	defer resp.Body.Close()
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		metrics.MerchantSyncFailures.WithLabelValues("status").Inc()
 		return fmt.Errorf("merchant service returned status %d", resp.StatusCode)
 	}
 
 	return nil
 }
 
+// recalculateRisk rescores the merchant after a KYC event without failing the caller
+func (s *KYCService) recalculateRisk(ctx context.Context, merchantID int, trigger string) {
+	if s.risk == nil {
+		return
+	}
+	if _, err := s.risk.Recalculate(ctx, merchantID, trigger); err != nil {
+		logging.FromContext(ctx).Warn("failed to recalculate merchant risk score", "trigger", trigger, "error", err)
+	}
+}
+
 // Helper functions
 func timePtrToString(t *time.Time) string {
 	if t == nil {
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/kodra-pay/compliance-service/internal/pii"
+	"github.com/kodra-pay/compliance-service/internal/repositories"
+)
+
+// unmaskedFields are the KYC fields revealed by an unmask, recorded on every access log entry
+var unmaskedFields = []string{"tin_number", "director_bvn", "director_phone", "director_email", "persons.bvn", "persons.phone", "persons.email"}
+
+// PIIService reveals masked personal data to permitted callers and audits every access
+type PIIService struct {
+	kycRepo    repositories.KYCStore
+	accessRepo *repositories.PIIAccessRepository
+}
+
+func NewPIIService(kycRepo repositories.KYCStore, accessRepo *repositories.PIIAccessRepository) *PIIService {
+	return &PIIService{kycRepo: kycRepo, accessRepo: accessRepo}
+}
+
+// UnmaskSubmission returns the unmasked personal data of a submission, or nil if it does not exist.
+// The access is logged before anything is revealed, and nothing is revealed if the log write fails.
+func (s *PIIService) UnmaskSubmission(ctx context.Context, submissionID int, req dto.PIIUnmaskRequest) (*dto.PIIUnmaskResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, Validation("reason is required")
+	}
+
+	submission, err := s.kycRepo.GetByID(ctx, submissionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kyc submission: %w", err)
+	}
+	if submission == nil {
+		return nil, nil
+	}
+
+	persons, err := s.kycRepo.ListPersons(ctx, submissionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kyc persons: %w", err)
+	}
+
+	access := &models.PIIAccess{
+		ActorID:   req.ActorID,
+		ActorRole: req.ActorRole,
+		Entity:    "kyc_submission",
+		EntityID:  submissionID,
+		Fields:    unmaskedFields,
+		Reason:    reason,
+		IP:        req.IP,
+		RequestID: req.RequestID,
+	}
+	if err := s.accessRepo.Create(ctx, access); err != nil {
+		return nil, fmt.Errorf("failed to record pii access: %w", err)
+	}
+
+	response := &dto.PIIUnmaskResponse{
+		AccessID:      access.ID,
+		SubmissionID:  submission.ID,
+		MerchantID:    submission.MerchantID,
+		TINNumber:     submission.TINNumber,
+		DirectorBVN:   submission.DirectorBVN,
+		DirectorPhone: submission.DirectorPhone,
+		DirectorEmail: submission.DirectorEmail,
+		Persons:       make([]dto.PIIPersonData, 0, len(persons)),
+	}
+	for _, person := range persons {
+		response.Persons = append(response.Persons, dto.PIIPersonData{
+			PersonID: person.ID,
+			Role:     person.Role,
+			FullName: person.FullName,
+			BVN:      person.BVN,
+			Phone:    person.Phone,
+			Email:    person.Email,
+		})
+	}
+
+	return response, nil
+}
+
+// maskPerson masks the personal data of a person before it leaves the service
+func maskPerson(person *models.KYCPerson) {
+	person.BVN = pii.MaskBVN(person.BVN)
+	person.Phone = pii.MaskPhone(person.Phone)
+	person.Email = pii.MaskEmail(person.Email)
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/config"
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/kodra-pay/compliance-service/internal/repositories"
+)
+
+// openErasureStatuses are the statuses of erasure requests that are not completed yet
+var openErasureStatuses = []string{
+	models.ErasureStatusReceived,
+	models.ErasureStatusDeferred,
+	models.ErasureStatusOnHold,
+}
+
+// PrivacyService applies the data retention policy and handles right-to-erasure requests.
+// Personal data is anonymised rather than deleted so aggregates and audit trails stay intact.
+type PrivacyService struct {
+	repo *repositories.PrivacyRepository
+	cfg  config.RetentionConfig
+}
+
+func NewPrivacyService(repo *repositories.PrivacyRepository, cfg config.RetentionConfig) *PrivacyService {
+	return &PrivacyService{repo: repo, cfg: cfg}
+}
+
+// CreateErasureRequest records an erasure request and processes it straight away. A request for
+// a merchant ends the merchant relationship, which starts the retention period of its KYC data.
+func (s *PrivacyService) CreateErasureRequest(ctx context.Context, req dto.ErasureRequestCreate) (*models.ErasureRequest, error) {
+	subjectType, err := normalizeSubjectType(req.SubjectType)
+	if err != nil {
+		return nil, err
+	}
+	if req.SubjectID == 0 {
+		return nil, Validation("subject_id is required")
+	}
+
+	request := &models.ErasureRequest{
+		SubjectType: subjectType,
+		SubjectID:   req.SubjectID,
+		RequestedBy: req.RequestedBy,
+		Status:      models.ErasureStatusReceived,
+	}
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		request.Reason = &reason
+	}
+	if err := s.repo.CreateErasureRequest(ctx, request); err != nil {
+		return nil, fmt.Errorf("failed to create erasure request: %w", err)
+	}
+
+	if subjectType == models.SubjectTypeMerchant {
+		if err := s.repo.EndMerchantRelationship(ctx, req.SubjectID, request.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to end merchant relationship: %w", err)
+		}
+	}
+
+	// The purge job retries requests that could not be processed now
+	if err := s.processErasure(ctx, request, time.Now()); err != nil {
+		logging.FromContext(ctx).Warn("failed to process erasure request", "erasure_request_id", request.ID, "error", err)
+	}
+
+	return request, nil
+}
+
+// GetErasureRequest retrieves an erasure request by ID
+func (s *PrivacyService) GetErasureRequest(ctx context.Context, id int) (*models.ErasureRequest, error) {
+	request, err := s.repo.GetErasureRequest(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get erasure request: %w", err)
+	}
+	return request, nil
+}
+
+// ListErasureRequests lists erasure requests by status. An empty status lists the open requests.
+func (s *PrivacyService) ListErasureRequests(ctx context.Context, status string, limit int) (*dto.ErasureRequestListResponse, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	statuses := openErasureStatuses
+	if status != "" {
+		statuses = []string{strings.ToLower(status)}
+	}
+
+	requests, err := s.repo.ListErasureRequestsByStatus(ctx, statuses, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list erasure requests: %w", err)
+	}
+	if requests == nil {
+		requests = []models.ErasureRequest{}
+	}
+
+	return &dto.ErasureRequestListResponse{Requests: requests, Total: len(requests)}, nil
+}
+
+// EndMerchantRelationship starts the retention period of a merchant's KYC data
+func (s *PrivacyService) EndMerchantRelationship(ctx context.Context, merchantID int) error {
+	if err := s.repo.EndMerchantRelationship(ctx, merchantID, time.Now()); err != nil {
+		return fmt.Errorf("failed to end merchant relationship: %w", err)
+	}
+	return nil
+}
+
+// PlaceLegalHold suspends purges and erasure for a data subject
+func (s *PrivacyService) PlaceLegalHold(ctx context.Context, req dto.LegalHoldRequest) (*models.LegalHold, error) {
+	subjectType, err := normalizeSubjectType(req.SubjectType)
+	if err != nil {
+		return nil, err
+	}
+	if req.SubjectID == 0 {
+		return nil, Validation("subject_id is required")
+	}
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, Validation("reason is required")
+	}
+
+	hold := &models.LegalHold{
+		SubjectType: subjectType,
+		SubjectID:   req.SubjectID,
+		Reason:      strings.TrimSpace(req.Reason),
+		PlacedBy:    req.PlacedBy,
+	}
+	if err := s.repo.CreateLegalHold(ctx, hold); err != nil {
+		return nil, fmt.Errorf("failed to place legal hold: %w", err)
+	}
+
+	return hold, nil
+}
+
+// ReleaseLegalHold releases a legal hold. Erasure requests it blocked are picked up by the next
+// purge run. It returns nil if the hold does not exist or was already released.
+func (s *PrivacyService) ReleaseLegalHold(ctx context.Context, id, releasedBy int) (*models.LegalHold, error) {
+	hold, err := s.repo.ReleaseLegalHold(ctx, id, releasedBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release legal hold: %w", err)
+	}
+	return hold, nil
+}
+
+// RunPurge processes open erasure requests and anonymises data that is past its retention period.
+// It is run periodically by the scheduler.
+func (s *PrivacyService) RunPurge(ctx context.Context) error {
+	now := time.Now()
+
+	requests, err := s.repo.ListDueErasureRequests(ctx, now, s.batchSize())
+	if err != nil {
+		return fmt.Errorf("failed to list due erasure requests: %w", err)
+	}
+	for i := range requests {
+		if err := s.processErasure(ctx, &requests[i], now); err != nil {
+			logging.FromContext(ctx).Warn("failed to process erasure request", "erasure_request_id", requests[i].ID, "error", err)
+		}
+	}
+
+	policies := []struct {
+		entity string
+		months int
+		list   func(context.Context, time.Time, int) ([]int, error)
+		purge  func(context.Context, []int) error
+	}{
+		{"kyc_submissions", s.cfg.KYCSubmissionMonths, s.repo.ListExpiredSubmissions, s.repo.AnonymiseSubmissions},
+		{"documents", s.cfg.DocumentMonths, s.repo.ListExpiredDocuments, s.repo.PurgeDocuments},
+		{"kyc_records", s.cfg.KYCRecordMonths, s.repo.ListExpiredKYCRecords, s.repo.AnonymiseKYCRecords},
+		{"alerts", s.cfg.AlertMonths, s.repo.ListExpiredAlerts, s.repo.AnonymiseAlerts},
+	}
+
+	for _, policy := range policies {
+		ids, err := policy.list(ctx, now.AddDate(0, -policy.months, 0), s.batchSize())
+		if err != nil {
+			return fmt.Errorf("failed to list expired %s: %w", policy.entity, err)
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		if err := policy.purge(ctx, ids); err != nil {
+			return fmt.Errorf("failed to purge expired %s: %w", policy.entity, err)
+		}
+		logging.FromContext(ctx).Info("retention purge anonymised records", "entity", policy.entity, "count", len(ids))
+	}
+
+	return nil
+}
+
+// processErasure erases the subject's data unless a legal hold applies or the retention period
+// is still running, recording why the request is waiting otherwise
+func (s *PrivacyService) processErasure(ctx context.Context, request *models.ErasureRequest, now time.Time) error {
+	held, err := s.repo.HasActiveLegalHold(ctx, request.SubjectType, request.SubjectID)
+	if err != nil {
+		return fmt.Errorf("failed to check legal hold: %w", err)
+	}
+	if held {
+		return s.updateErasure(ctx, request, models.ErasureStatusOnHold, nil, "subject is under legal hold")
+	}
+
+	retainUntil, err := s.retainUntil(ctx, request)
+	if err != nil {
+		return err
+	}
+	if retainUntil != nil && retainUntil.After(now) {
+		return s.updateErasure(ctx, request, models.ErasureStatusDeferred, retainUntil,
+			"data is within its mandated retention period")
+	}
+
+	if err := s.eraseSubject(ctx, request); err != nil {
+		return err
+	}
+
+	completedAt := now
+	request.CompletedAt = &completedAt
+	return s.updateErasure(ctx, request, models.ErasureStatusCompleted, retainUntil, "personal data anonymised")
+}
+
+// retainUntil returns the end of the subject's longest running retention period
+func (s *PrivacyService) retainUntil(ctx context.Context, request *models.ErasureRequest) (*time.Time, error) {
+	if request.SubjectType == models.SubjectTypeMerchant {
+		endedAt, err := s.repo.MerchantRelationshipEndedAt(ctx, request.SubjectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get merchant relationship end: %w", err)
+		}
+		if endedAt == nil {
+			return nil, nil
+		}
+		until := endedAt.AddDate(0, max(s.cfg.KYCSubmissionMonths, s.cfg.DocumentMonths), 0)
+		return &until, nil
+	}
+
+	lastRecord, lastAlert, err := s.repo.UserRetentionAnchors(ctx, request.SubjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user retention anchors: %w", err)
+	}
+
+	var until *time.Time
+	for _, anchor := range []struct {
+		at     *time.Time
+		months int
+	}{{lastRecord, s.cfg.KYCRecordMonths}, {lastAlert, s.cfg.AlertMonths}} {
+		if anchor.at == nil {
+			continue
+		}
+		end := anchor.at.AddDate(0, anchor.months, 0)
+		if until == nil || end.After(*until) {
+			until = &end
+		}
+	}
+	return until, nil
+}
+
+func (s *PrivacyService) eraseSubject(ctx context.Context, request *models.ErasureRequest) error {
+	if request.SubjectType == models.SubjectTypeMerchant {
+		ids, err := s.repo.ListSubmissionIDsByMerchant(ctx, request.SubjectID)
+		if err != nil {
+			return fmt.Errorf("failed to list merchant submissions: %w", err)
+		}
+		if err := s.repo.AnonymiseSubmissions(ctx, ids); err != nil {
+			return fmt.Errorf("failed to anonymise merchant submissions: %w", err)
+		}
+		return nil
+	}
+
+	recordIDs, err := s.repo.ListKYCRecordIDsByUser(ctx, request.SubjectID)
+	if err != nil {
+		return fmt.Errorf("failed to list user kyc records: %w", err)
+	}
+	if err := s.repo.AnonymiseKYCRecords(ctx, recordIDs); err != nil {
+		return fmt.Errorf("failed to anonymise user kyc records: %w", err)
+	}
+
+	alertIDs, err := s.repo.ListAlertIDsByUser(ctx, request.SubjectID)
+	if err != nil {
+		return fmt.Errorf("failed to list user alerts: %w", err)
+	}
+	if err := s.repo.AnonymiseAlerts(ctx, alertIDs); err != nil {
+		return fmt.Errorf("failed to anonymise user alerts: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PrivacyService) updateErasure(ctx context.Context, request *models.ErasureRequest, status string, retainUntil *time.Time, notes string) error {
+	request.Status = status
+	request.RetainUntil = retainUntil
+	request.StatusNotes = &notes
+	if err := s.repo.UpdateErasureRequest(ctx, request); err != nil {
+		return repositoryError(err, "failed to update erasure request")
+	}
+	return nil
+}
+
+func (s *PrivacyService) batchSize() int {
+	if s.cfg.PurgeBatch <= 0 {
+		return 100
+	}
+	return s.cfg.PurgeBatch
+}
+
+func normalizeSubjectType(value string) (string, error) {
+	subjectType := strings.ToLower(strings.TrimSpace(value))
+	if subjectType != models.SubjectTypeMerchant && subjectType != models.SubjectTypeUser {
+		return "", Validation("subject_type must be 'merchant' or 'user'")
+	}
+	return subjectType, nil
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/config"
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/kodra-pay/compliance-service/internal/repositories"
+)
+
+// ReviewService schedules ongoing due diligence reviews of approved merchants based on risk tier
+type ReviewService struct {
+	repo     *repositories.ReviewRepository
+	riskRepo *repositories.RiskRepository
+	cfg      config.ReviewConfig
+}
+
+func NewReviewService(repo *repositories.ReviewRepository, riskRepo *repositories.RiskRepository, cfg config.ReviewConfig) *ReviewService {
+	return &ReviewService{repo: repo, riskRepo: riskRepo, cfg: cfg}
+}
+
+// Schedule plans the next review of a merchant counting from the given review date.
+// An existing active review is rescheduled rather than duplicated.
+func (s *ReviewService) Schedule(ctx context.Context, merchantID, submissionID int, reviewedAt time.Time) (*models.KYCReview, error) {
+	tier, err := s.currentTier(ctx, merchantID)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := s.repo.GetActiveByMerchant(ctx, merchantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active review: %w", err)
+	}
+
+	if review == nil {
+		review = &models.KYCReview{
+			MerchantID:     merchantID,
+			SubmissionID:   submissionID,
+			RiskTier:       tier,
+			Status:         models.ReviewStatusScheduled,
+			LastReviewedAt: reviewedAt,
+			DueAt:          s.dueDate(reviewedAt, tier),
+		}
+		if err := s.repo.Create(ctx, review); err != nil {
+			return nil, fmt.Errorf("failed to schedule review: %w", err)
+		}
+		return review, nil
+	}
+
+	review.SubmissionID = submissionID
+	review.RiskTier = tier
+	review.Status = models.ReviewStatusScheduled
+	review.LastReviewedAt = reviewedAt
+	review.DueAt = s.dueDate(reviewedAt, tier)
+	review.OpenedAt = nil
+	review.EscalatedAt = nil
+	if err := s.repo.Update(ctx, review); err != nil {
+		return nil, repositoryError(err, "failed to reschedule review")
+	}
+	return review, nil
+}
+
+// Complete closes an open review and schedules the next cycle
+func (s *ReviewService) Complete(ctx context.Context, id int, req dto.KYCReviewCompleteRequest) (*models.KYCReview, error) {
+	review, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	if review == nil {
+		return nil, NotFound("kyc review not found")
+	}
+	if review.Status != models.ReviewStatusOpen && review.Status != models.ReviewStatusEscalated {
+		return nil, Conflict("only open or escalated reviews can be completed")
+	}
+
+	now := time.Now()
+	review.Status = models.ReviewStatusCompleted
+	review.CompletedAt = &now
+	if req.ReviewerID != 0 {
+		review.ReviewerID = &req.ReviewerID
+	}
+	if req.ReviewNotes != "" {
+		review.ReviewNotes = &req.ReviewNotes
+	}
+	if err := s.repo.Update(ctx, review); err != nil {
+		return nil, repositoryError(err, "failed to complete review")
+	}
+
+	if _, err := s.Schedule(ctx, review.MerchantID, review.SubmissionID, now); err != nil {
+		logging.FromContext(ctx).Warn("failed to schedule next review", "merchant_id", review.MerchantID, "error", err)
+	}
+
+	return review, nil
+}
+
+// ListDue lists open and escalated reviews, earliest due first
+func (s *ReviewService) ListDue(ctx context.Context, limit int) (*dto.KYCReviewListResponse, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	reviews, err := s.repo.ListByStatus(ctx, []string{models.ReviewStatusOpen, models.ReviewStatusEscalated}, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due reviews: %w", err)
+	}
+	if reviews == nil {
+		reviews = []models.KYCReview{}
+	}
+
+	return &dto.KYCReviewListResponse{Reviews: reviews, Total: len(reviews)}, nil
+}
+
+// RunCycle is the scheduler entry point. It backfills approved merchants without a review,
+// re-tiers scheduled reviews, opens reviews coming due and escalates overdue ones.
+func (s *ReviewService) RunCycle(ctx context.Context) error {
+	if err := s.backfill(ctx); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	active, err := s.repo.ListByStatus(ctx, []string{
+		models.ReviewStatusScheduled, models.ReviewStatusOpen,
+	}, 1000)
+	if err != nil {
+		return fmt.Errorf("failed to list active reviews: %w", err)
+	}
+
+	for i := range active {
+		review := &active[i]
+		changed := false
+
+		if review.Status == models.ReviewStatusScheduled {
+			tier, err := s.currentTier(ctx, review.MerchantID)
+			if err != nil {
+				logging.FromContext(ctx).Warn("failed to read current risk tier", "merchant_id", review.MerchantID, "error", err)
+			} else if tier != review.RiskTier {
+				review.RiskTier = tier
+				review.DueAt = s.dueDate(review.LastReviewedAt, tier)
+				changed = true
+			}
+
+			if !now.Before(review.DueAt.Add(-s.cfg.OpenBefore)) {
+				review.Status = models.ReviewStatusOpen
+				review.OpenedAt = &now
+				changed = true
+			}
+		}
+
+		if review.Status == models.ReviewStatusOpen && now.After(review.DueAt.Add(s.cfg.EscalateAfter)) {
+			review.Status = models.ReviewStatusEscalated
+			review.EscalatedAt = &now
+			changed = true
+			logging.FromContext(ctx).Warn("overdue KYC review escalated",
+				"review_id", review.ID, "merchant_id", review.MerchantID, "due_at", review.DueAt.Format(time.RFC3339))
+		}
+
+		if changed {
+			if err := s.repo.Update(ctx, review); err != nil {
+				logging.FromContext(ctx).Warn("failed to update review", "review_id", review.ID, "error", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// backfill schedules reviews for merchants approved before periodic reviews existed
+func (s *ReviewService) backfill(ctx context.Context) error {
+	merchants, err := s.repo.ListApprovedWithoutReview(ctx, 500)
+	if err != nil {
+		return fmt.Errorf("failed to list approved merchants without review: %w", err)
+	}
+
+	for _, m := range merchants {
+		if _, err := s.Schedule(ctx, m.MerchantID, m.SubmissionID, m.ApprovedAt); err != nil {
+			logging.FromContext(ctx).Warn("failed to schedule review", "merchant_id", m.MerchantID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *ReviewService) currentTier(ctx context.Context, merchantID int) (string, error) {
+	score, err := s.riskRepo.GetLatestByMerchant(ctx, merchantID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get risk tier for merchant %d: %w", merchantID, err)
+	}
+	if score == nil {
+		// Unscored merchants are reviewed on the medium-risk cycle until a score exists
+		return models.RiskTierMedium, nil
+	}
+	return score.Tier, nil
+}
+
+func (s *ReviewService) dueDate(from time.Time, tier string) time.Time {
+	switch tier {
+	case models.RiskTierHigh:
+		return from.AddDate(0, s.cfg.HighRiskMonths, 0)
+	case models.RiskTierLow:
+		return from.AddDate(0, s.cfg.LowRiskMonths, 0)
+	default:
+		return from.AddDate(0, s.cfg.MediumRiskMonths, 0)
+	}
+}
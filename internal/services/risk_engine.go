@@ -0,0 +1,277 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/address"
+	"github.com/kodra-pay/compliance-service/internal/config"
+	"github.com/kodra-pay/compliance-service/internal/models"
+)
+
+// RiskInputs is everything the risk engine knows about a merchant at scoring time
+type RiskInputs struct {
+	Submission   *models.KYCSubmission
+	Screening    []models.ScreeningResult // newest first; only the latest per subject and list counts
+	AlertCounts  map[string]int           // severity -> open alerts in the lookback window
+	Transactions *models.TransactionSummary
+	Now          time.Time
+}
+
+// RiskEngine combines weighted risk factors into a score between 0 and 100
+type RiskEngine struct {
+	cfg config.RiskConfig
+}
+
+func NewRiskEngine(cfg config.RiskConfig) *RiskEngine {
+	return &RiskEngine{cfg: cfg}
+}
+
+// Score evaluates every factor and returns the weighted score, the resulting tier and the breakdown
+func (e *RiskEngine) Score(in RiskInputs) (float64, string, []models.RiskFactor) {
+	screening := latestScreeningResults(in.Screening)
+	factors := []models.RiskFactor{
+		e.businessCategoryFactor(in.Submission),
+		e.businessTypeFactor(in.Submission),
+		e.stateFactor(in.Submission),
+		e.incorporationAgeFactor(in.Submission, in.Now),
+		e.screeningFactor(screening),
+		e.alertHistoryFactor(in.AlertCounts),
+		e.transactionFactor(in.Transactions),
+	}
+
+	var totalWeight float64
+	for i := range factors {
+		factors[i].Weight = e.cfg.Weights[factors[i].Name]
+		totalWeight += factors[i].Weight
+	}
+
+	var score float64
+	if totalWeight > 0 {
+		for i := range factors {
+			factors[i].Contribution = round2(factors[i].Score * factors[i].Weight / totalWeight)
+			score += factors[i].Score * factors[i].Weight / totalWeight
+		}
+	}
+	score = round2(score)
+
+	tier := e.tierFor(score)
+	// A confirmed sanctions match always puts the merchant in the high tier
+	if hasScreeningMatch(screening, "sanctions", "confirmed_match") {
+		tier = models.RiskTierHigh
+	}
+
+	return score, tier, factors
+}
+
+func (e *RiskEngine) tierFor(score float64) string {
+	switch {
+	case score >= e.cfg.HighTierThreshold:
+		return models.RiskTierHigh
+	case score >= e.cfg.MediumTierThreshold:
+		return models.RiskTierMedium
+	default:
+		return models.RiskTierLow
+	}
+}
+
+func (e *RiskEngine) businessCategoryFactor(sub *models.KYCSubmission) models.RiskFactor {
+	factor := models.RiskFactor{Name: "business_category", Value: "unknown", Score: 50}
+	if sub == nil || sub.BusinessCategory == "" {
+		return factor
+	}
+
+	category := normalizeRiskKey(sub.BusinessCategory)
+	factor.Value = category
+	switch {
+	case containsKey(e.cfg.HighRiskCategories, category):
+		factor.Score = 100
+	case containsKey(e.cfg.MediumRiskCategories, category):
+		factor.Score = 50
+	default:
+		factor.Score = 10
+	}
+	return factor
+}
+
+func (e *RiskEngine) businessTypeFactor(sub *models.KYCSubmission) models.RiskFactor {
+	factor := models.RiskFactor{Name: "business_type", Value: "unknown", Score: 50}
+	if sub == nil {
+		return factor
+	}
+
+	factor.Value = sub.BusinessType
+	if sub.BusinessType == "startup" {
+		factor.Score = 60
+	} else {
+		factor.Score = 20
+	}
+	return factor
+}
+
+func (e *RiskEngine) stateFactor(sub *models.KYCSubmission) models.RiskFactor {
+	factor := models.RiskFactor{Name: "state", Value: "unknown", Score: 50}
+	if sub == nil || sub.State == "" {
+		return factor
+	}
+
+	state := stateRiskKey(sub.State)
+	factor.Value = state
+	if containsState(e.cfg.HighRiskStates, state) {
+		factor.Score = 80
+	} else {
+		factor.Score = 20
+	}
+	return factor
+}
+
+func (e *RiskEngine) incorporationAgeFactor(sub *models.KYCSubmission, now time.Time) models.RiskFactor {
+	factor := models.RiskFactor{Name: "incorporation_age", Value: "unknown", Score: 70}
+	if sub == nil || sub.IncorporationDate == nil {
+		return factor
+	}
+
+	years := now.Sub(*sub.IncorporationDate).Hours() / 24 / 365
+	factor.Value = fmt.Sprintf("%.1f years", years)
+	switch {
+	case years < 1:
+		factor.Score = 80
+	case years < 3:
+		factor.Score = 50
+	default:
+		factor.Score = 15
+	}
+	return factor
+}
+
+func (e *RiskEngine) screeningFactor(results []models.ScreeningResult) models.RiskFactor {
+	factor := models.RiskFactor{Name: "screening", Value: "clear", Score: 0}
+
+	switch {
+	case hasScreeningMatch(results, "sanctions", "confirmed_match"):
+		factor.Value, factor.Score = "confirmed sanctions match", 100
+	case hasScreeningMatch(results, "pep", "confirmed_match"):
+		factor.Value, factor.Score = "confirmed PEP match", 80
+	case hasScreeningMatch(results, "adverse_media", "confirmed_match"):
+		factor.Value, factor.Score = "confirmed adverse media", 70
+	case hasScreeningMatch(results, "", "potential_match"):
+		factor.Value, factor.Score = "unresolved potential match", 50
+	case len(results) == 0:
+		factor.Value, factor.Score = "not screened", 40
+	}
+	return factor
+}
+
+func (e *RiskEngine) alertHistoryFactor(counts map[string]int) models.RiskFactor {
+	high, medium, low := counts["high"], counts["medium"], counts["low"]
+	score := math.Min(100, float64(high*40+medium*20+low*5))
+	return models.RiskFactor{
+		Name:  "alert_history",
+		Value: fmt.Sprintf("%d high, %d medium, %d low", high, medium, low),
+		Score: score,
+	}
+}
+
+func (e *RiskEngine) transactionFactor(summary *models.TransactionSummary) models.RiskFactor {
+	factor := models.RiskFactor{Name: "transaction_behaviour", Value: "no activity", Score: 0}
+	if summary == nil || summary.TransactionCount == 0 {
+		return factor
+	}
+
+	var score float64
+	if e.cfg.HighVolumeThreshold > 0 {
+		score += math.Min(60, 60*float64(summary.TotalAmount)/float64(e.cfg.HighVolumeThreshold))
+	}
+	cashRatio := float64(summary.CashAmount) / float64(summary.TotalAmount)
+	if cashRatio > 0.5 {
+		score += 40
+	}
+
+	factor.Value = fmt.Sprintf("%d txns, volume %s, %.0f%% cash",
+		summary.TransactionCount, formatMinorUnits(summary.TotalAmount), cashRatio*100)
+	factor.Score = round2(math.Min(100, score))
+	return factor
+}
+
+// latestScreeningResults keeps the newest result per subject and list from results ordered newest
+// first, so a later clear or false positive resolves an earlier match. Results for a declared
+// person are keyed by the person, others by subject type and name.
+func latestScreeningResults(results []models.ScreeningResult) []models.ScreeningResult {
+	seen := make(map[string]bool)
+	var latest []models.ScreeningResult
+	for _, r := range results {
+		subject := r.SubjectType + ":" + strings.ToLower(strings.TrimSpace(r.SubjectName))
+		if r.PersonID != nil {
+			subject = fmt.Sprintf("person:%d", *r.PersonID)
+		}
+		key := subject + "|" + r.ListType
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		latest = append(latest, r)
+	}
+	return latest
+}
+
+// personScreeningStatus derives a declared person's screening status from their results, newest
+// first. The person is clear only when the latest result on every list is clear or a false
+// positive; otherwise the strongest outstanding match wins.
+func personScreeningStatus(results []models.ScreeningResult) string {
+	latest := latestScreeningResults(results)
+	switch {
+	case len(latest) == 0:
+		return "pending"
+	case hasScreeningMatch(latest, "", "confirmed_match"):
+		return "confirmed_match"
+	case hasScreeningMatch(latest, "", "potential_match"):
+		return "potential_match"
+	}
+	return "clear"
+}
+
+func hasScreeningMatch(results []models.ScreeningResult, listType, status string) bool {
+	for _, r := range results {
+		if (listType == "" || r.ListType == listType) && r.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeRiskKey(value string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(value)), " ", "_")
+}
+
+func containsKey(list []string, key string) bool {
+	for _, item := range list {
+		if normalizeRiskKey(item) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// stateRiskKey resolves state aliases such as "Abuja" or "LA" before normalising, so submissions
+// made before address normalisation and the configured state lists compare alike
+func stateRiskKey(state string) string {
+	if canonical, ok := address.CanonicalState(state); ok {
+		state = canonical
+	}
+	return normalizeRiskKey(state)
+}
+
+func containsState(list []string, key string) bool {
+	for _, item := range list {
+		if stateRiskKey(item) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/config"
+	"github.com/kodra-pay/compliance-service/internal/dto"
+	"github.com/kodra-pay/compliance-service/internal/logging"
+	"github.com/kodra-pay/compliance-service/internal/metrics"
+	"github.com/kodra-pay/compliance-service/internal/models"
+	"github.com/kodra-pay/compliance-service/internal/repositories"
+)
+
+// Lookback windows for behavioural risk factors
+const (
+	riskAlertLookback       = 90 * 24 * time.Hour
+	riskTransactionLookback = 30 * 24 * time.Hour
+)
+
+type RiskService struct {
+	repo           *repositories.RiskRepository
+	kycRepo        repositories.KYCStore
+	screeningRepo  *repositories.ScreeningRepository
+	complianceRepo repositories.ComplianceRepository
+	txnRepo        *repositories.TransactionRepository
+	engine         *RiskEngine
+	cfg            config.RiskConfig
+}
+
+func NewRiskService(
+	repo *repositories.RiskRepository,
+	kycRepo repositories.KYCStore,
+	screeningRepo *repositories.ScreeningRepository,
+	complianceRepo repositories.ComplianceRepository,
+	txnRepo *repositories.TransactionRepository,
+	cfg config.RiskConfig,
+) *RiskService {
+	return &RiskService{
+		repo:           repo,
+		kycRepo:        kycRepo,
+		screeningRepo:  screeningRepo,
+		complianceRepo: complianceRepo,
+		txnRepo:        txnRepo,
+		engine:         NewRiskEngine(cfg),
+		cfg:            cfg,
+	}
+}
+
+// Recalculate scores a merchant from its current data and appends the result to its history
+func (s *RiskService) Recalculate(ctx context.Context, merchantID int, trigger string) (*models.RiskScore, error) {
+	now := time.Now()
+
+	submission, err := s.kycRepo.GetLatestByMerchant(ctx, merchantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load KYC submission: %w", err)
+	}
+
+	screening, err := s.screeningRepo.ListByMerchant(ctx, merchantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load screening results: %w", err)
+	}
+
+	alertCounts, err := s.complianceRepo.CountAlertsBySeverity(merchantID, now.Add(-riskAlertLookback))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load alert history: %w", err)
+	}
+
+	txnSummary, err := s.txnRepo.Summary(ctx, merchantID, now.Add(-riskTransactionLookback).UTC(), now.UTC(), s.cfg.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transaction behaviour: %w", err)
+	}
+
+	score, tier, factors := s.engine.Score(RiskInputs{
+		Submission:   submission,
+		Screening:    screening,
+		AlertCounts:  alertCounts,
+		Transactions: txnSummary,
+		Now:          now,
+	})
+
+	riskScore := &models.RiskScore{
+		MerchantID: merchantID,
+		Score:      score,
+		Tier:       tier,
+		Factors:    factors,
+		Trigger:    trigger,
+	}
+	if err := s.repo.Create(ctx, riskScore); err != nil {
+		return nil, fmt.Errorf("failed to store risk score: %w", err)
+	}
+
+	return riskScore, nil
+}
+
+// GetMerchantRisk returns the current score and up to limit entries of score history
+func (s *RiskService) GetMerchantRisk(ctx context.Context, merchantID int, limit int) (*dto.MerchantRiskResponse, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	history, err := s.repo.ListByMerchant(ctx, merchantID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get risk history: %w", err)
+	}
+	if len(history) == 0 {
+		return nil, nil
+	}
+
+	return &dto.MerchantRiskResponse{
+		MerchantID: merchantID,
+		Current:    &history[0],
+		History:    history,
+	}, nil
+}
+
+// RecordScreeningResult stores a screening outcome and rescores the merchant
+func (s *RiskService) RecordScreeningResult(ctx context.Context, req dto.ScreeningResultRequest) (*models.ScreeningResult, error) {
+	if req.MerchantID == 0 {
+		return nil, Validation("merchant_id is required")
+	}
+	if req.SubjectName == "" {
+		return nil, Validation("subject_name is required")
+	}
+
+	listType := strings.ToLower(req.ListType)
+	if listType != "sanctions" && listType != "pep" && listType != "adverse_media" {
+		return nil, Validation("list_type must be 'sanctions', 'pep' or 'adverse_media'")
+	}
+
+	status := strings.ToLower(req.Status)
+	switch status {
+	case "clear", "potential_match", "confirmed_match", "false_positive":
+	default:
+		return nil, Validation("status must be 'clear', 'potential_match', 'confirmed_match' or 'false_positive'")
+	}
+
+	subjectType := strings.ToLower(req.SubjectType)
+	if subjectType == "" {
+		subjectType = "business"
+	}
+
+	result := &models.ScreeningResult{
+		MerchantID:  req.MerchantID,
+		SubjectName: req.SubjectName,
+		SubjectType: subjectType,
+		ListType:    listType,
+		ListName:    req.ListName,
+		MatchScore:  req.MatchScore,
+		Status:      status,
+	}
+	if req.PersonID != 0 {
+		person, err := s.kycRepo.GetPerson(ctx, req.PersonID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get kyc person: %w", err)
+		}
+		if person == nil {
+			return nil, NotFound("kyc person not found")
+		}
+		submission, err := s.kycRepo.GetByID(ctx, person.SubmissionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get kyc submission: %w", err)
+		}
+		if submission == nil || submission.MerchantID != req.MerchantID {
+			return nil, Validation("person_id %d is not declared on a KYC submission of merchant %d", req.PersonID, req.MerchantID)
+		}
+		result.PersonID = &req.PersonID
+		result.SubjectType = person.Role
+	}
+
+	if err := s.screeningRepo.Create(ctx, result); err != nil {
+		return nil, fmt.Errorf("failed to record screening result: %w", err)
+	}
+	metrics.ScreeningResults.WithLabelValues(listType, status).Inc()
+
+	// The screening status of a declared person is tracked on the person itself, derived from
+	// the latest result on each list
+	if result.PersonID != nil {
+		results, err := s.screeningRepo.ListByPerson(ctx, *result.PersonID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load person screening results: %w", err)
+		}
+		if err := s.kycRepo.UpdatePersonScreening(ctx, *result.PersonID, personScreeningStatus(results)); err != nil {
+			return nil, repositoryError(err, "failed to update person screening status")
+		}
+	}
+
+	if _, err := s.Recalculate(ctx, req.MerchantID, "screening_result"); err != nil {
+		logging.FromContext(ctx).Warn("failed to recalculate risk score", "merchant_id", req.MerchantID, "error", err)
+	}
+
+	return result, nil
+}
+
+// RescoreStale recalculates merchants whose latest score is older than the configured maximum age.
+// It picks up alert and transaction behaviour changes that are not tied to a KYC event.
+func (s *RiskService) RescoreStale(ctx context.Context) error {
+	merchantIDs, err := s.repo.ListStaleMerchants(ctx, time.Now().Add(-s.cfg.MaxScoreAge), 500)
+	if err != nil {
+		return fmt.Errorf("failed to list merchants due for rescoring: %w", err)
+	}
+
+	for _, merchantID := range merchantIDs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := s.Recalculate(ctx, merchantID, "scheduled"); err != nil {
+			logging.FromContext(ctx).Warn("failed to rescore merchant", "merchant_id", merchantID, "error", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package validation
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/address"
+)
+
+// DateLayout is the layout of date fields in requests
+const DateLayout = "2006-01-02"
+
+var (
+	bvnPattern   = regexp.MustCompile(`^\d{11}$`)
+	phonePattern = regexp.MustCompile(`^\+[1-9]\d{7,14}$`)
+	cacPattern   = regexp.MustCompile(`(?i)^(RC|BN|IT)[\s-]?\d{1,8}$`)
+)
+
+// format is a rule that checks the text of a string field
+type format struct {
+	valid   func(string) bool
+	message string
+}
+
+var formats = map[string]format{
+	"bvn":   {bvnPattern.MatchString, "must be 11 digits"},
+	"phone": {phonePattern.MatchString, "must be an E.164 phone number, e.g. +2348012345678"},
+	"email": {validEmail, "must be a valid email address"},
+	"cac":   {cacPattern.MatchString, "must be a CAC number such as RC123456 or BN1234567"},
+	"date":  {validDate, "must be a date in YYYY-MM-DD format"},
+	"past":  {pastDate, "must be a date in YYYY-MM-DD format and not in the future"},
+	"state": {validState, "must be a Nigerian state or the FCT"},
+}
+
+func validEmail(value string) bool {
+	parsed, err := mail.ParseAddress(value)
+	if err != nil || parsed.Address != value {
+		return false
+	}
+	_, domain, _ := strings.Cut(value, "@")
+	return strings.Contains(domain, ".")
+}
+
+func validDate(value string) bool {
+	_, err := time.Parse(DateLayout, value)
+	return err == nil
+}
+
+func pastDate(value string) bool {
+	date, err := time.Parse(DateLayout, value)
+	return err == nil && !date.After(time.Now())
+}
+
+func validState(value string) bool {
+	_, ok := address.CanonicalState(value)
+	return ok
+}
@@ -0,0 +1,236 @@
+// Package validation checks request DTOs against rules declared in `validate` struct tags and
+// reports every failing field with its JSON path, e.g. "directors[0].bvn".
+//
+// Rules are comma separated. Format rules skip empty values, so combine them with "required" for
+// mandatory fields:
+//
+//	required      non-zero value; strings must not be blank
+//	bvn           11 digit Bank Verification Number
+//	phone         E.164 phone number, e.g. +2348012345678
+//	email         email address
+//	cac           CAC registration number: RC (companies), BN (business names) or IT (trustees)
+//	date          YYYY-MM-DD date
+//	past          YYYY-MM-DD date that is not in the future
+//	state         Nigerian state or the FCT
+//	oneof=a b c   one of the listed values, case insensitive
+//	enum=name     one of the values registered under name with WithEnum
+//	min=n, max=n  number bounds, or string length bounds
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldError is a single failing field
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Errors collects every failing field of a request
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		messages = append(messages, fieldErr.Field+": "+fieldErr.Message)
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Add appends a failing field. It is used for cross-field rules that tags cannot express.
+func (e *Errors) Add(field, rule, message string) {
+	*e = append(*e, FieldError{Field: field, Rule: rule, Message: message})
+}
+
+// Err returns the errors as an error, or nil if there are none
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validator validates structs against their `validate` tags
+type Validator struct {
+	enums map[string]map[string]bool
+}
+
+func New() *Validator {
+	return &Validator{enums: make(map[string]map[string]bool)}
+}
+
+// WithEnum registers the allowed values for "enum=name" rules. Values are compared by EnumKey.
+func (v *Validator) WithEnum(name string, values []string) *Validator {
+	allowed := make(map[string]bool, len(values))
+	for _, value := range values {
+		allowed[EnumKey(value)] = true
+	}
+	v.enums[name] = allowed
+	return v
+}
+
+// EnumKey is the form enum values are compared in: lower case with spaces as underscores
+func EnumKey(value string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(value)), " ", "_")
+}
+
+// Struct validates a struct, or a pointer to one, and returns every failing field. Nested structs
+// and slices of structs are validated too.
+func (v *Validator) Struct(value interface{}) Errors {
+	var errs Errors
+	v.walk(reflect.ValueOf(value), "", &errs)
+	return errs
+}
+
+func (v *Validator) walk(value reflect.Value, path string, errs *Errors) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		v.walkStruct(value, path, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			v.walk(value.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+func (v *Validator) walkStruct(value reflect.Value, path string, errs *Errors) {
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := jsonName(field)
+		if name == "-" {
+			continue
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+
+		fieldValue := value.Field(i)
+		if tag := field.Tag.Get("validate"); tag != "" {
+			for _, rule := range strings.Split(tag, ",") {
+				if message, ok := v.check(fieldValue, rule); !ok {
+					ruleName, _, _ := strings.Cut(rule, "=")
+					errs.Add(name, ruleName, message)
+					break // report the first failing rule of a field
+				}
+			}
+		}
+
+		v.walk(fieldValue, name, errs)
+	}
+}
+
+// check applies one rule to a field value and returns the failure message
+func (v *Validator) check(value reflect.Value, rule string) (string, bool) {
+	name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			if name == "required" {
+				return "is required", false
+			}
+			return "", true
+		}
+		value = value.Elem()
+	}
+
+	if name == "required" {
+		if value.Kind() == reflect.String && strings.TrimSpace(value.String()) == "" || value.IsZero() {
+			return "is required", false
+		}
+		return "", true
+	}
+
+	if name == "min" || name == "max" {
+		return checkBound(value, name, param)
+	}
+
+	if value.Kind() != reflect.String {
+		return "", true
+	}
+	text := strings.TrimSpace(value.String())
+	if text == "" {
+		return "", true
+	}
+
+	switch name {
+	case "oneof":
+		for _, option := range strings.Fields(param) {
+			if strings.EqualFold(option, text) {
+				return "", true
+			}
+		}
+		return "must be one of: " + strings.Join(strings.Fields(param), ", "), false
+	case "enum":
+		allowed, ok := v.enums[param]
+		if !ok || !allowed[EnumKey(text)] {
+			return "is not a supported " + strings.ReplaceAll(param, "_", " "), false
+		}
+		return "", true
+	}
+
+	format, ok := formats[name]
+	if !ok {
+		panic(fmt.Sprintf("validation: unknown rule %q", name))
+	}
+	if !format.valid(text) {
+		return format.message, false
+	}
+	return "", true
+}
+
+func checkBound(value reflect.Value, name, param string) (string, bool) {
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validation: invalid %s bound %q", name, param))
+	}
+
+	var actual float64
+	var unit string
+	switch value.Kind() {
+	case reflect.String:
+		actual, unit = float64(len([]rune(value.String()))), " characters"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		actual = float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		actual = float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		actual = value.Float()
+	case reflect.Slice, reflect.Map:
+		actual, unit = float64(value.Len()), " items"
+	default:
+		return "", true
+	}
+
+	if name == "min" && actual < bound {
+		return fmt.Sprintf("must be at least %s%s", param, unit), false
+	}
+	if name == "max" && actual > bound {
+		return fmt.Sprintf("must be at most %s%s", param, unit), false
+	}
+	return "", true
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
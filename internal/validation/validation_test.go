@@ -0,0 +1,165 @@
+package validation_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kodra-pay/compliance-service/internal/validation"
+)
+
+type person struct {
+	Name      string   `json:"full_name" validate:"required,max=10"`
+	BVN       string   `json:"bvn,omitempty" validate:"bvn"`
+	Ownership *float64 `json:"ownership_percentage" validate:"min=0,max=100"`
+}
+
+type request struct {
+	MerchantID int      `json:"merchant_id" validate:"required"`
+	Type       string   `json:"business_type" validate:"required,oneof=registered startup"`
+	CAC        string   `json:"cac_number" validate:"cac"`
+	Phone      string   `json:"phone" validate:"phone"`
+	Email      string   `json:"email" validate:"email"`
+	State      string   `json:"state" validate:"state"`
+	Founded    string   `json:"incorporation_date" validate:"past"`
+	Expiry     string   `json:"expiry_date" validate:"date"`
+	Category   string   `json:"business_category" validate:"enum=business_category"`
+	Notes      *string  `json:"notes" validate:"required"`
+	Persons    []person `json:"persons" validate:"max=2"`
+	Ignored    string   `json:"-" validate:"required"`
+	unexported string
+}
+
+// valid returns a request that passes every rule
+func valid() request {
+	notes := "ok"
+	return request{
+		MerchantID: 42,
+		Type:       "Registered",
+		CAC:        "RC-123456",
+		Phone:      "+2348012345678",
+		Email:      "ada@example.com",
+		State:      "lagos",
+		Founded:    "2019-05-01",
+		Expiry:     "2031-01-31",
+		Category:   "Food and Beverage",
+		Notes:      &notes,
+		Persons:    []person{{Name: "Ada Obi", BVN: "22123456789"}},
+	}
+}
+
+func newValidator() *validation.Validator {
+	return validation.New().WithEnum("business_category", []string{"food_and_beverage", "retail"})
+}
+
+func TestStruct(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(validation.DateLayout)
+	over := 100.5
+
+	tests := []struct {
+		name   string
+		change func(r *request)
+		want   []validation.FieldError
+	}{
+		{"valid", func(r *request) {}, nil},
+		{"empty format fields are skipped", func(r *request) {
+			r.CAC, r.Phone, r.Email, r.State, r.Founded, r.Expiry, r.Category = "", "", "", "", "", "", ""
+		}, nil},
+		{"missing required fields", func(r *request) {
+			r.MerchantID, r.Type, r.Notes = 0, "   ", nil
+		}, []validation.FieldError{
+			{Field: "merchant_id", Rule: "required", Message: "is required"},
+			{Field: "business_type", Rule: "required", Message: "is required"},
+			{Field: "notes", Rule: "required", Message: "is required"},
+		}},
+		{"oneof", func(r *request) { r.Type = "charity" }, []validation.FieldError{
+			{Field: "business_type", Rule: "oneof", Message: "must be one of: registered, startup"},
+		}},
+		{"formats", func(r *request) {
+			r.CAC, r.Phone, r.Email, r.State = "XY123", "08012345678", "ada@localhost", "Lagoon"
+		}, []validation.FieldError{
+			{Field: "cac_number", Rule: "cac", Message: "must be a CAC number such as RC123456 or BN1234567"},
+			{Field: "phone", Rule: "phone", Message: "must be an E.164 phone number, e.g. +2348012345678"},
+			{Field: "email", Rule: "email", Message: "must be a valid email address"},
+			{Field: "state", Rule: "state", Message: "must be a Nigerian state or the FCT"},
+		}},
+		{"dates", func(r *request) { r.Founded, r.Expiry = tomorrow, "31/01/2031" }, []validation.FieldError{
+			{Field: "incorporation_date", Rule: "past", Message: "must be a date in YYYY-MM-DD format and not in the future"},
+			{Field: "expiry_date", Rule: "date", Message: "must be a date in YYYY-MM-DD format"},
+		}},
+		{"enum", func(r *request) { r.Category = "gambling" }, []validation.FieldError{
+			{Field: "business_category", Rule: "enum", Message: "is not a supported business category"},
+		}},
+		{"nested fields report their path and first failing rule", func(r *request) {
+			r.Persons = append(r.Persons, person{Name: "Chukwuemeka Obi", BVN: "2212345678", Ownership: &over})
+		}, []validation.FieldError{
+			{Field: "persons[1].full_name", Rule: "max", Message: "must be at most 10 characters"},
+			{Field: "persons[1].bvn", Rule: "bvn", Message: "must be 11 digits"},
+			{Field: "persons[1].ownership_percentage", Rule: "max", Message: "must be at most 100"},
+		}},
+		{"slice length", func(r *request) {
+			r.Persons = []person{{Name: "A"}, {Name: "B"}, {Name: "C"}}
+		}, []validation.FieldError{
+			{Field: "persons", Rule: "max", Message: "must be at most 2 items"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.change(&r)
+
+			got := newValidator().Struct(&r)
+			if len(got) == 0 && len(tt.want) == 0 {
+				if got.Err() != nil {
+					t.Errorf("Err() = %v for no errors, want nil", got.Err())
+				}
+				return
+			}
+			if !reflect.DeepEqual([]validation.FieldError(got), tt.want) {
+				t.Errorf("got %+v\nwant %+v", got, tt.want)
+			}
+			if err := got.Err(); err == nil || !strings.HasPrefix(err.Error(), "validation failed: ") {
+				t.Errorf("Err() = %v, want a validation failure", err)
+			}
+		})
+	}
+}
+
+func TestStructPanicsOnBadTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"unknown rule", &struct {
+			Code string `validate:"iban"`
+		}{Code: "NG00"}, `validation: unknown rule "iban"`},
+		{"invalid bound", &struct {
+			Name string `validate:"max=ten"`
+		}{Name: "Ada"}, `validation: invalid max bound "ten"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if got := recover(); got != tt.want {
+					t.Errorf("panic = %v, want %q", got, tt.want)
+				}
+			}()
+			validation.New().Struct(tt.value)
+		})
+	}
+}
+
+func TestErrorsAdd(t *testing.T) {
+	var errs validation.Errors
+	if errs.Err() != nil {
+		t.Fatalf("Err() of no errors = %v, want nil", errs.Err())
+	}
+
+	errs.Add("directors", "beneficial_owner", "at least one beneficial owner is required")
+	want := "validation failed: directors: at least one beneficial owner is required"
+	if err := errs.Err(); err == nil || err.Error() != want {
+		t.Errorf("Err() = %v, want %q", err, want)
+	}
+}
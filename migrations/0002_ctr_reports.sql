@@ -0,0 +1,49 @@
+-- Create monitored_transactions table (transaction monitoring store)
+CREATE TABLE IF NOT EXISTS monitored_transactions (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    reference VARCHAR(100) NOT NULL UNIQUE,
+    customer_id BIGINT NOT NULL,
+    customer_type VARCHAR(20) NOT NULL,
+    customer_name VARCHAR(255),
+    channel VARCHAR(20) NOT NULL,
+    direction VARCHAR(10) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'NGN',
+    counterparty_name VARCHAR(255),
+    counterparty_account VARCHAR(50),
+    occurred_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_monitored_transactions_occurred_at ON monitored_transactions (occurred_at);
+CREATE INDEX IF NOT EXISTS idx_monitored_transactions_customer ON monitored_transactions (customer_id, occurred_at);
+
+-- Create ctr_batches table
+CREATE TABLE IF NOT EXISTS ctr_batches (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    report_date DATE NOT NULL UNIQUE,
+    status VARCHAR(50) NOT NULL,
+    individual_threshold BIGINT NOT NULL,
+    corporate_threshold BIGINT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    record_count INTEGER NOT NULL DEFAULT 0,
+    total_amount BIGINT NOT NULL DEFAULT 0,
+    goaml_xml TEXT NOT NULL,
+    csv_payload TEXT NOT NULL,
+    generated_by VARCHAR(100) NOT NULL,
+    generated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create ctr_batch_entries table
+CREATE TABLE IF NOT EXISTS ctr_batch_entries (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    batch_id BIGINT NOT NULL REFERENCES ctr_batches (id) ON DELETE CASCADE,
+    customer_id BIGINT NOT NULL,
+    customer_type VARCHAR(20) NOT NULL,
+    customer_name VARCHAR(255),
+    transaction_count INTEGER NOT NULL,
+    total_amount BIGINT NOT NULL,
+    threshold BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ctr_batch_entries_batch ON ctr_batch_entries (batch_id);
@@ -0,0 +1,27 @@
+-- Create screening_results table (sanctions, PEP and adverse media screening outcomes)
+CREATE TABLE IF NOT EXISTS screening_results (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    merchant_id BIGINT NOT NULL,
+    subject_name VARCHAR(255) NOT NULL,
+    subject_type VARCHAR(50) NOT NULL,
+    list_type VARCHAR(50) NOT NULL,
+    list_name VARCHAR(255),
+    match_score NUMERIC(5, 2) NOT NULL DEFAULT 0,
+    status VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_screening_results_merchant ON screening_results (merchant_id, created_at);
+
+-- Create risk_scores table (score history with factor breakdown)
+CREATE TABLE IF NOT EXISTS risk_scores (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    merchant_id BIGINT NOT NULL,
+    score NUMERIC(5, 2) NOT NULL,
+    tier VARCHAR(20) NOT NULL,
+    factors JSONB NOT NULL,
+    trigger VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_scores_merchant ON risk_scores (merchant_id, created_at DESC);
@@ -0,0 +1,20 @@
+-- Create kyc_reviews table (periodic KYC review tasks)
+CREATE TABLE IF NOT EXISTS kyc_reviews (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    merchant_id BIGINT NOT NULL,
+    submission_id BIGINT NOT NULL,
+    risk_tier VARCHAR(20) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    last_reviewed_at TIMESTAMP NOT NULL,
+    due_at TIMESTAMP NOT NULL,
+    opened_at TIMESTAMP,
+    escalated_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    reviewer_id BIGINT,
+    review_notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_reviews_status_due ON kyc_reviews (status, due_at);
+CREATE INDEX IF NOT EXISTS idx_kyc_reviews_merchant ON kyc_reviews (merchant_id);
@@ -0,0 +1,29 @@
+-- Create edd_checklists table (enhanced due diligence attached to a KYC submission)
+CREATE TABLE IF NOT EXISTS edd_checklists (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    submission_id BIGINT NOT NULL UNIQUE,
+    merchant_id BIGINT NOT NULL,
+    reasons JSONB NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    signed_off_by BIGINT,
+    signed_off_role VARCHAR(50),
+    signoff_notes TEXT,
+    signed_off_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create edd_items table
+CREATE TABLE IF NOT EXISTS edd_items (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    checklist_id BIGINT NOT NULL REFERENCES edd_checklists (id) ON DELETE CASCADE,
+    item_type VARCHAR(100) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    evidence TEXT,
+    notes TEXT,
+    completed_by BIGINT,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (checklist_id, item_type)
+);
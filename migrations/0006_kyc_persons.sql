@@ -0,0 +1,24 @@
+-- Create kyc_persons table (directors, shareholders and beneficial owners of a submission)
+CREATE TABLE IF NOT EXISTS kyc_persons (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    submission_id BIGINT NOT NULL,
+    role VARCHAR(50) NOT NULL,
+    full_name VARCHAR(255) NOT NULL,
+    bvn VARCHAR(20),
+    phone VARCHAR(50),
+    email VARCHAR(255),
+    date_of_birth DATE,
+    nationality VARCHAR(100),
+    ownership_percentage NUMERIC(5, 2),
+    identity_status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    identity_reference VARCHAR(255),
+    screening_status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_persons_submission ON kyc_persons (submission_id);
+CREATE INDEX IF NOT EXISTS idx_kyc_persons_bvn ON kyc_persons (bvn);
+
+-- Link screening results to the individual person that was screened
+ALTER TABLE screening_results ADD COLUMN IF NOT EXISTS person_id BIGINT;
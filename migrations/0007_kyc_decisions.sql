@@ -0,0 +1,18 @@
+-- Create kyc_decisions table (maker-checker proposals for KYC status changes)
+CREATE TABLE IF NOT EXISTS kyc_decisions (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    submission_id BIGINT NOT NULL,
+    merchant_id BIGINT NOT NULL,
+    proposed_status VARCHAR(50) NOT NULL,
+    proposed_by BIGINT NOT NULL,
+    proposal_notes TEXT,
+    proposed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    status VARCHAR(50) NOT NULL,
+    final_status VARCHAR(50),
+    checked_by BIGINT,
+    check_notes TEXT,
+    checked_at TIMESTAMP
+);
+
+-- At most one open proposal per submission
+CREATE UNIQUE INDEX IF NOT EXISTS idx_kyc_decisions_open ON kyc_decisions (submission_id) WHERE status = 'proposed';
@@ -0,0 +1,66 @@
+-- kyc_submissions predates these migrations; create it for fresh databases
+CREATE TABLE IF NOT EXISTS kyc_submissions (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    merchant_id BIGINT NOT NULL,
+    business_type VARCHAR(50) NOT NULL,
+    business_name VARCHAR(255) NOT NULL,
+    cac_number VARCHAR(100),
+    tin_number TEXT,
+    business_address TEXT,
+    city VARCHAR(100),
+    state VARCHAR(100),
+    postal_code VARCHAR(20),
+    incorporation_date DATE,
+    business_category VARCHAR(100),
+    director_name VARCHAR(255),
+    director_bvn TEXT,
+    director_phone TEXT,
+    director_email TEXT,
+    documents JSONB,
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    reviewer_id BIGINT,
+    review_notes TEXT,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_merchant ON kyc_submissions (merchant_id, created_at DESC);
+
+-- Encrypted values are longer than the plaintext columns allowed
+ALTER TABLE kyc_submissions
+    ALTER COLUMN tin_number TYPE TEXT,
+    ALTER COLUMN director_bvn TYPE TEXT,
+    ALTER COLUMN director_phone TYPE TEXT,
+    ALTER COLUMN director_email TYPE TEXT;
+
+-- Blind indexes for exact-match lookups and the key version of the encrypted columns (0 = plaintext)
+ALTER TABLE kyc_submissions
+    ADD COLUMN IF NOT EXISTS tin_number_bidx VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS director_bvn_bidx VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS director_phone_bidx VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS director_email_bidx VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS encryption_key_version INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_tin_bidx ON kyc_submissions (tin_number_bidx);
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_bvn_bidx ON kyc_submissions (director_bvn_bidx);
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_phone_bidx ON kyc_submissions (director_phone_bidx);
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_email_bidx ON kyc_submissions (director_email_bidx);
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_key_version ON kyc_submissions (encryption_key_version);
+
+ALTER TABLE kyc_persons
+    ALTER COLUMN bvn TYPE TEXT,
+    ALTER COLUMN phone TYPE TEXT,
+    ALTER COLUMN email TYPE TEXT;
+
+ALTER TABLE kyc_persons
+    ADD COLUMN IF NOT EXISTS bvn_bidx VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS phone_bidx VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS email_bidx VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS encryption_key_version INTEGER NOT NULL DEFAULT 0;
+
+DROP INDEX IF EXISTS idx_kyc_persons_bvn;
+CREATE INDEX IF NOT EXISTS idx_kyc_persons_bvn_bidx ON kyc_persons (bvn_bidx);
+CREATE INDEX IF NOT EXISTS idx_kyc_persons_phone_bidx ON kyc_persons (phone_bidx);
+CREATE INDEX IF NOT EXISTS idx_kyc_persons_email_bidx ON kyc_persons (email_bidx);
+CREATE INDEX IF NOT EXISTS idx_kyc_persons_key_version ON kyc_persons (encryption_key_version);
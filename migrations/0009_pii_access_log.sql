@@ -0,0 +1,16 @@
+-- Create pii_access_log table (every unmask of encrypted KYC fields is recorded here)
+CREATE TABLE IF NOT EXISTS pii_access_log (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    actor_id BIGINT NOT NULL,
+    actor_role VARCHAR(50) NOT NULL,
+    entity VARCHAR(50) NOT NULL,
+    entity_id BIGINT NOT NULL,
+    fields TEXT[] NOT NULL,
+    reason TEXT NOT NULL,
+    ip VARCHAR(64),
+    request_id VARCHAR(100),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pii_access_log_entity ON pii_access_log (entity, entity_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_pii_access_log_actor ON pii_access_log (actor_id, created_at DESC);
@@ -0,0 +1,42 @@
+-- Retention bookkeeping on the entities covered by the retention policy
+ALTER TABLE kyc_submissions
+    ADD COLUMN IF NOT EXISTS relationship_ended_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS documents_purged_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS anonymised_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_retention ON kyc_submissions (relationship_ended_at) WHERE anonymised_at IS NULL;
+
+ALTER TABLE kyc_records ADD COLUMN IF NOT EXISTS anonymised_at TIMESTAMP;
+ALTER TABLE transaction_monitoring_alerts ADD COLUMN IF NOT EXISTS anonymised_at TIMESTAMP;
+
+-- Create legal_holds table (held subjects are excluded from purges and erasure)
+CREATE TABLE IF NOT EXISTS legal_holds (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    subject_type VARCHAR(20) NOT NULL, -- "merchant" or "user"
+    subject_id BIGINT NOT NULL,
+    reason TEXT NOT NULL,
+    placed_by BIGINT NOT NULL,
+    placed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    released_by BIGINT,
+    released_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_legal_holds_active ON legal_holds (subject_type, subject_id) WHERE released_at IS NULL;
+
+-- Create erasure_requests table (right-to-erasure requests and their progress)
+CREATE TABLE IF NOT EXISTS erasure_requests (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    subject_type VARCHAR(20) NOT NULL,
+    subject_id BIGINT NOT NULL,
+    reason TEXT,
+    requested_by BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    retain_until TIMESTAMP,
+    status_notes TEXT,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_erasure_requests_status ON erasure_requests (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_erasure_requests_subject ON erasure_requests (subject_type, subject_id);
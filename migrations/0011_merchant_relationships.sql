@@ -0,0 +1,22 @@
+-- Create merchant_relationships table (links between submissions of different merchants that
+-- share identifiers, forming the related-merchant graph)
+CREATE TABLE IF NOT EXISTS merchant_relationships (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    submission_id BIGINT NOT NULL,
+    merchant_id BIGINT NOT NULL,
+    related_submission_id BIGINT NOT NULL,
+    related_merchant_id BIGINT NOT NULL,
+    link_type VARCHAR(20) NOT NULL, -- "bvn", "cac", "tin", "address", "phone", "email"
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (submission_id, related_submission_id, link_type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_merchant_relationships_related ON merchant_relationships (related_submission_id);
+CREATE INDEX IF NOT EXISTS idx_merchant_relationships_merchants ON merchant_relationships (merchant_id, related_merchant_id);
+
+-- Submissions sharing identifiers with other merchants are flagged for manual review
+ALTER TABLE kyc_submissions
+    ADD COLUMN IF NOT EXISTS flagged_for_review BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS flag_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_cac ON kyc_submissions ((regexp_replace(UPPER(cac_number), '[^A-Z0-9]', '', 'g')));
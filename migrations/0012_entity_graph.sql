@@ -0,0 +1,40 @@
+-- Create graph_nodes table (entities of the investigation graph). BVN, phone and email nodes are
+-- keyed by their blind index so the graph never holds those values in plaintext.
+CREATE TABLE IF NOT EXISTS graph_nodes (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    node_type VARCHAR(20) NOT NULL, -- "merchant", "person", "bvn", "phone", "email", "address", "bank_account", "transaction"
+    node_key VARCHAR(255) NOT NULL,
+    label VARCHAR(255) NOT NULL,
+    properties JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (node_type, node_key)
+);
+
+-- Create graph_edges table. source identifies the row the edge was derived from so it can be
+-- rebuilt when that row changes.
+CREATE TABLE IF NOT EXISTS graph_edges (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    from_node_id BIGINT NOT NULL REFERENCES graph_nodes (id) ON DELETE CASCADE,
+    to_node_id BIGINT NOT NULL REFERENCES graph_nodes (id) ON DELETE CASCADE,
+    edge_type VARCHAR(50) NOT NULL,
+    source VARCHAR(100) NOT NULL,
+    properties JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (from_node_id, to_node_id, edge_type, source)
+);
+
+CREATE INDEX IF NOT EXISTS idx_graph_edges_from ON graph_edges (from_node_id);
+CREATE INDEX IF NOT EXISTS idx_graph_edges_to ON graph_edges (to_node_id);
+CREATE INDEX IF NOT EXISTS idx_graph_edges_source ON graph_edges (source);
+
+-- Create graph_sync_state table (incremental sync cursor per source table)
+CREATE TABLE IF NOT EXISTS graph_sync_state (
+    source VARCHAR(50) PRIMARY KEY,
+    synced_until TIMESTAMP NOT NULL,
+    last_id BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_updated ON kyc_submissions (updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_transaction_monitoring_alerts_updated ON transaction_monitoring_alerts (updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_monitored_transactions_created ON monitored_transactions (created_at, id);
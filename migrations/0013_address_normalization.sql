@@ -0,0 +1,8 @@
+-- Normalised business addresses: canonical state, standardised street line, PO box and
+-- virtual-office flags and geocoded coordinates. address_key is the exact-match key used for
+-- related-merchant detection.
+ALTER TABLE kyc_submissions
+    ADD COLUMN IF NOT EXISTS address_normalized JSONB,
+    ADD COLUMN IF NOT EXISTS address_key TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_address_key ON kyc_submissions (address_key) WHERE address_key IS NOT NULL;
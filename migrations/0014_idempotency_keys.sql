@@ -0,0 +1,14 @@
+-- Idempotency-Key claims and the stored responses replayed for retried requests. Used when Redis
+-- is not configured. status_code is NULL while the request is in progress; expired rows are reused
+-- by the next request with the key and purged periodically.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key VARCHAR(512) PRIMARY KEY,
+    request_hash VARCHAR(64) NOT NULL,
+    status_code INTEGER,
+    content_type VARCHAR(255),
+    body BYTEA,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
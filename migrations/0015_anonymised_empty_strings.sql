@@ -0,0 +1,5 @@
+-- Anonymisation used to set these columns to NULL, which the repositories cannot scan into their
+-- string fields. It now writes empty strings; rows anonymised before are brought in line.
+UPDATE kyc_submissions SET postal_code = '' WHERE anonymised_at IS NOT NULL AND postal_code IS NULL;
+UPDATE kyc_records SET document_id = '' WHERE anonymised_at IS NOT NULL AND document_id IS NULL;
+UPDATE transaction_monitoring_alerts SET description = '' WHERE anonymised_at IS NOT NULL AND description IS NULL;
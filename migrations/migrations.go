@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL schema migrations so the binary can apply them itself
+package migrations
+
+import "embed"
+
+// Files holds the migrations, applied in file name order
+//
+//go:embed *.sql
+var Files embed.FS